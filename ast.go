@@ -0,0 +1,250 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidPartType     = errors.New("invalid part type")
+	ErrInvalidPartModifier = errors.New("invalid part modifier")
+	ErrInvalidPartRegexp   = errors.New("part's regexp cannot be represented in a pattern string")
+	ErrInvalidGroupName    = errors.New("invalid group name")
+)
+
+// PartType is the type of a Part.
+//
+// https://urlpattern.spec.whatwg.org/#part-type
+type PartType uint8
+
+// The values match the ones of the internal partType.
+const (
+	// PartFixedText represents a simple fixed text string.
+	PartFixedText = PartType(partFixedText)
+	// PartRegexp represents a matching group with a custom regular expression.
+	PartRegexp = PartType(partRegexp)
+	// PartSegmentWildcard represents a matching group that matches code points up to the next separator code point, like ":foo".
+	PartSegmentWildcard = PartType(partSegmentWildcard)
+	// PartFullWildcard represents a matching group that greedily matches all code points, like "*".
+	PartFullWildcard = PartType(partFullWildcard)
+)
+
+func (t PartType) String() string {
+	switch t {
+	case PartFixedText:
+		return "fixed-text"
+	case PartRegexp:
+		return "regexp"
+	case PartSegmentWildcard:
+		return "segment-wildcard"
+	case PartFullWildcard:
+		return "full-wildcard"
+	default:
+		return "unknown"
+	}
+}
+
+// Modifier is the modifier of a Part.
+//
+// https://urlpattern.spec.whatwg.org/#part-modifier
+type Modifier uint8
+
+// The values match the ones of the internal partModifier.
+const (
+	// ModifierNone means the part does not have a modifier.
+	ModifierNone = Modifier(partModifierNone)
+	// ModifierOptional is indicated by the U+003F (?) code point.
+	ModifierOptional = Modifier(partModifierOptional)
+	// ModifierZeroOrMore is indicated by the U+002A (*) code point.
+	ModifierZeroOrMore = Modifier(partModifierZeroOrMore)
+	// ModifierOneOrMore is indicated by the U+002B (+) code point.
+	ModifierOneOrMore = Modifier(partModifierOneOrMore)
+)
+
+func (m Modifier) String() string {
+	switch m {
+	case ModifierNone:
+		return "none"
+	case ModifierOptional:
+		return "optional"
+	case ModifierZeroOrMore:
+		return "zero-or-more"
+	case ModifierOneOrMore:
+		return "one-or-more"
+	default:
+		return "unknown"
+	}
+}
+
+// Part is a node of the parsed representation of a component pattern.
+//
+// https://urlpattern.spec.whatwg.org/#part
+type Part struct {
+	Type PartType
+	// Value is the fixed text for PartFixedText parts and the regular
+	// expression for PartRegexp parts. It is empty for wildcards.
+	Value    string
+	Modifier Modifier
+	// Name is the name of the group, or a number for anonymous groups. It is
+	// empty for PartFixedText parts.
+	Name   string
+	Prefix string
+	Suffix string
+}
+
+// Parts returns the parsed representation of the pattern of the component
+// with the given name (e.g. "pathname"), or nil if the name is unknown.
+//
+// The returned slice is a copy: modifying it doesn't alter the pattern.
+func (u *URLPattern) Parts(component string) []Part {
+	c := u.component(component)
+	if c == nil {
+		return nil
+	}
+
+	return c.parts()
+}
+
+func (c *component) parts() []Part {
+	parts := make([]Part, len(c.partList))
+	for i, p := range c.partList {
+		parts[i] = Part{
+			Type:     PartType(p.pType),
+			Value:    p.value,
+			Modifier: Modifier(p.modifier),
+			Name:     p.name,
+			Prefix:   p.prefix,
+			Suffix:   p.suffix,
+		}
+	}
+
+	return parts
+}
+
+// CompileOptions are the options used by CompileParts.
+//
+// https://urlpattern.spec.whatwg.org/#options-header
+type CompileOptions struct {
+	// DelimiterCodePoint is the ASCII code point up to which segment
+	// wildcards match, e.g. '/' for pathnames and '.' for hostnames.
+	DelimiterCodePoint byte
+	// PrefixCodePoint is the ASCII code point automatically used as prefix
+	// of groups, e.g. '/' for pathnames.
+	PrefixCodePoint byte
+	IgnoreCase      bool
+}
+
+// Component is a component pattern compiled by CompileParts.
+type Component struct {
+	component *component
+}
+
+// CompileParts compiles a component pattern from its parsed representation,
+// without having to build and escape a pattern string. This allows code
+// generators to assemble patterns safely.
+//
+// Values are used as is: fixed text, prefixes and suffixes must already be
+// canonicalized for the component they are intended for. Names are checked as
+// in pattern strings: ErrInvalidGroupName is returned for names which aren't
+// valid JavaScript identifiers, except for the numeric names of anonymous
+// regexp groups and full wildcards.
+func CompileParts(parts []Part, opts CompileOptions) (*Component, error) {
+	pl := make(partList, len(parts))
+	seenNames := make(map[string]struct{}, len(parts))
+
+	for i, p := range parts {
+		if err := p.validate(seenNames); err != nil {
+			return nil, typeError(err)
+		}
+
+		pl[i] = part{pType: partType(p.Type), value: p.Value, modifier: partModifier(p.Modifier), name: p.Name, prefix: p.Prefix, suffix: p.Suffix}
+	}
+
+	c, err := compilePartList("", pl, options{delimiterCodePoint: opts.DelimiterCodePoint, prefixCodePoint: opts.PrefixCodePoint, ignoreCase: opts.IgnoreCase})
+	if err != nil {
+		return nil, typeError(err)
+	}
+
+	return &Component{c}, nil
+}
+
+func (p Part) validate(seenNames map[string]struct{}) error {
+	if p.Type > PartFullWildcard {
+		return ErrInvalidPartType
+	}
+
+	if p.Modifier > ModifierOneOrMore {
+		return ErrInvalidPartModifier
+	}
+
+	if p.Type == PartFixedText {
+		if p.Value == "" {
+			return ErrUnexpectedEmptyString
+		}
+
+		return nil
+	}
+
+	if p.Name == "" {
+		return ErrEmptyPartName
+	}
+
+	// As in pattern strings, segment wildcards have a valid name, and the
+	// other groups either a valid name or a numeric anonymous name.
+	if !isValidName(p.Name) && (p.Type == PartSegmentWildcard || !IsAnonymousGroupName(p.Name)) {
+		return fmt.Errorf("%w: %q", ErrInvalidGroupName, p.Name)
+	}
+
+	if _, seen := seenNames[p.Name]; seen {
+		return ErrDuplicatePartName
+	}
+	seenNames[p.Name] = struct{}{}
+
+	if p.Type != PartRegexp {
+		return nil
+	}
+
+	// The regexp must be a single regexp token of a pattern string to be
+	// representable in the generated pattern string.
+	tl, err := tokenize("("+p.Value+")", tokenizePolicyStrict)
+	if err != nil || len(tl) != 2 || tl[0].tType != tokenRegexp {
+		return ErrInvalidPartRegexp
+	}
+
+	return nil
+}
+
+// String returns the pattern string of the component, which can be used in
+// a URLPatternInit or a constructor string.
+func (c *Component) String() string {
+	return c.component.patternString
+}
+
+// Regexp returns the regular expression generated for the component. Groups
+// are generated as Go named groups when their names allow it.
+func (c *Component) Regexp() string {
+	return c.component.regularExpression.String()
+}
+
+// Parts returns the parsed representation of the component pattern.
+func (c *Component) Parts() []Part {
+	return c.component.parts()
+}
+
+// HasRegexpGroups reports whether the component uses regexp groups.
+func (c *Component) HasRegexpGroups() bool {
+	return c.component.hasRegexpGroups
+}
+
+// Exec matches input against the component. The input is not
+// canonicalized. It returns nil if the input doesn't match.
+func (c *Component) Exec(input string) *URLPatternComponentResult {
+	execResult := c.component.exec(input)
+	if execResult == nil {
+		return nil
+	}
+
+	result := createComponentMatchResult(*c.component, input, execResult)
+
+	return &result
+}
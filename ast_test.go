@@ -0,0 +1,89 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestParts(t *testing.T) {
+	pattern, err := urlpattern.New("https://example.com/books/:id(\\d+)/*?", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []urlpattern.Part{
+		{Type: urlpattern.PartFixedText, Value: "/books", Modifier: urlpattern.ModifierNone},
+		{Type: urlpattern.PartRegexp, Value: "\\d+", Modifier: urlpattern.ModifierNone, Name: "id", Prefix: "/"},
+		{Type: urlpattern.PartFullWildcard, Modifier: urlpattern.ModifierOptional, Name: "0", Prefix: "/"},
+	}
+	if got := pattern.Parts("pathname"); !reflect.DeepEqual(want, got) {
+		t.Errorf("want %#v; got %#v", want, got)
+	}
+
+	if got := pattern.Parts("unknown"); got != nil {
+		t.Errorf("want nil; got %#v", got)
+	}
+}
+
+func TestCompileParts(t *testing.T) {
+	c, err := urlpattern.CompileParts([]urlpattern.Part{
+		{Type: urlpattern.PartFixedText, Value: "/items:(new)+"},
+		{Type: urlpattern.PartSegmentWildcard, Name: "id", Prefix: "/"},
+		{Type: urlpattern.PartRegexp, Value: "\\d+", Name: "page", Prefix: "/", Modifier: urlpattern.ModifierOptional},
+	}, urlpattern.CompileOptions{DelimiterCodePoint: '/', PrefixCodePoint: '/'})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `/items\:\(new\)\+/:id/:page(\d+)?`; c.String() != want {
+		t.Errorf("want %q; got %q", want, c.String())
+	}
+	if !c.HasRegexpGroups() {
+		t.Error("want regexp groups")
+	}
+
+	r := c.Exec("/items:(new)+/foo/42")
+	if r == nil || r.Groups["id"] != "foo" || r.Groups["page"] != "42" {
+		t.Errorf("unexpected result %#v", r)
+	}
+
+	if c.Exec("/items/foo") != nil {
+		t.Error("want no match")
+	}
+
+	// The generated pattern string round-trips.
+	pattern, err := urlpattern.NewBuilder().Pathname(c.String()).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.Parts(), pattern.Parts("pathname")) {
+		t.Errorf("want %#v; got %#v", c.Parts(), pattern.Parts("pathname"))
+	}
+}
+
+func TestCompilePartsInvalid(t *testing.T) {
+	for name, parts := range map[string][]urlpattern.Part{
+		"empty fixed text": {{Type: urlpattern.PartFixedText}},
+		"empty name":       {{Type: urlpattern.PartSegmentWildcard}},
+		"duplicate name":   {{Type: urlpattern.PartSegmentWildcard, Name: "a"}, {Type: urlpattern.PartFullWildcard, Name: "a"}},
+		"capturing group":  {{Type: urlpattern.PartRegexp, Name: "a", Value: "(a)"}},
+		"unbalanced":       {{Type: urlpattern.PartRegexp, Name: "a", Value: "a)(b"}},
+		"invalid type":     {{Type: 42, Name: "a"}},
+		"invalid name":     {{Type: urlpattern.PartSegmentWildcard, Name: "a-b"}},
+		"anonymous name":   {{Type: urlpattern.PartSegmentWildcard, Name: "0"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := urlpattern.CompileParts(parts, urlpattern.CompileOptions{}); err == nil {
+				t.Error("want error")
+			}
+		})
+	}
+
+	_, err := urlpattern.CompileParts([]urlpattern.Part{{Type: urlpattern.PartRegexp, Value: "\\d+", Name: "a b"}}, urlpattern.CompileOptions{})
+	if !errors.Is(err, urlpattern.ErrInvalidGroupName) || !errors.Is(err, urlpattern.ErrTypeError) {
+		t.Errorf("want ErrInvalidGroupName; got %v", err)
+	}
+}
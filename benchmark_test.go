@@ -1,6 +1,7 @@
 package urlpattern_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/dunglas/go-urlpattern"
@@ -84,3 +85,73 @@ func BenchmarkExec(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkHostRouter(b *testing.B) {
+	var router urlpattern.HostRouter
+	for i := range 10000 {
+		p, err := urlpattern.New(fmt.Sprintf("https://*.tenant%d.example.com/*", i), "", nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		router.Add(p)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	var r *urlpattern.URLPatternResult
+	for range b.N {
+		_, r = router.Match("https://www.tenant9999.example.com/users/42")
+	}
+	benchResultSink = r
+}
+
+func BenchmarkURLPatternList(b *testing.B) {
+	var list urlpattern.URLPatternList
+	for i := range 10000 {
+		p, err := urlpattern.New(fmt.Sprintf("https://example.com/resources%d/:id", i), "", nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		list.Add(p)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	var r *urlpattern.URLPatternResult
+	for range b.N {
+		_, r = list.Match("https://example.com/resources9999/42")
+	}
+	benchResultSink = r
+}
+
+func BenchmarkExecStdlibURL(b *testing.B) {
+	for _, bc := range benchmarkMatches {
+		p, err := urlpattern.Compile(bc.pattern, urlpattern.WithStdlibURL())
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var r *urlpattern.URLPatternResult
+			for range b.N {
+				r = p.Exec(bc.input, "")
+			}
+			benchResultSink = r
+		})
+	}
+}
+
+func BenchmarkExecCanonical(b *testing.B) {
+	p, err := urlpattern.New("https://example.com/users/:id/posts/:postId", "", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	input := urlpattern.CanonicalURL{Protocol: "https", Hostname: "example.com", Pathname: "/users/42/posts/7"}
+
+	b.ReportAllocs()
+	var r *urlpattern.URLPatternResult
+	for range b.N {
+		r = p.ExecCanonical(input)
+	}
+	benchResultSink = r
+}
@@ -0,0 +1,128 @@
+package urlpattern
+
+import "fmt"
+
+// Builder builds a URLPattern component by component, without having to
+// deal with the *string fields of URLPatternInit:
+//
+//	pattern := urlpattern.NewBuilder().
+//		Protocol("https").
+//		Hostname("*.example.com").
+//		Pathname("/:id").
+//		MustBuild()
+//
+// The syntax of each component pattern is validated as soon as it is set.
+// The first error is retained and returned by Build; subsequent calls are
+// no-ops.
+type Builder struct {
+	init    URLPatternInit
+	options Options
+	err     error
+}
+
+// NewBuilder returns an empty Builder. Components that are not set default
+// to the "*" wildcard, as with URLPatternInit.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Protocol sets the protocol component pattern.
+func (b *Builder) Protocol(pattern string) *Builder {
+	return b.set("protocol", &b.init.Protocol, pattern, options{})
+}
+
+// Username sets the username component pattern.
+func (b *Builder) Username(pattern string) *Builder {
+	return b.set("username", &b.init.Username, pattern, options{})
+}
+
+// Password sets the password component pattern.
+func (b *Builder) Password(pattern string) *Builder {
+	return b.set("password", &b.init.Password, pattern, options{})
+}
+
+// Hostname sets the hostname component pattern.
+func (b *Builder) Hostname(pattern string) *Builder {
+	return b.set("hostname", &b.init.Hostname, pattern, options{delimiterCodePoint: '.'})
+}
+
+// Port sets the port component pattern.
+func (b *Builder) Port(pattern string) *Builder {
+	return b.set("port", &b.init.Port, pattern, options{})
+}
+
+// Pathname sets the pathname component pattern.
+func (b *Builder) Pathname(pattern string) *Builder {
+	return b.set("pathname", &b.init.Pathname, pattern, options{delimiterCodePoint: '/', prefixCodePoint: '/'})
+}
+
+// Search sets the search component pattern.
+func (b *Builder) Search(pattern string) *Builder {
+	return b.set("search", &b.init.Search, pattern, options{})
+}
+
+// Hash sets the hash component pattern.
+func (b *Builder) Hash(pattern string) *Builder {
+	return b.set("hash", &b.init.Hash, pattern, options{})
+}
+
+// BaseURL sets the URL from which components that are not set are
+// inherited.
+func (b *Builder) BaseURL(baseURL string) *Builder {
+	if b.err == nil {
+		b.init.BaseURL = &baseURL
+	}
+
+	return b
+}
+
+// IgnoreCase enables case-insensitive matching.
+func (b *Builder) IgnoreCase() *Builder {
+	b.options.IgnoreCase = true
+
+	return b
+}
+
+// Build compiles the pattern. It returns the first error encountered while
+// setting components, if any.
+func (b *Builder) Build() (*URLPattern, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return b.init.New(&b.options)
+}
+
+// MustBuild is like Build but panics if the pattern cannot be compiled.
+// It simplifies safe initialization of global variables holding patterns.
+func (b *Builder) MustBuild() *URLPattern {
+	u, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	return u
+}
+
+func (b *Builder) set(name string, field **string, pattern string, options options) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	// Canonicalization depends on other components (e.g. the hostname
+	// depends on the protocol), so only the syntax is checked here; Build
+	// compiles the whole pattern.
+	pl, err := parsePatternString(pattern, options, func(s string) (string, error) { return s, nil })
+	if err == nil {
+		err = checkRegexpFeatures(name, pl)
+	}
+	if err != nil {
+		b.err = typeError(fmt.Errorf("%s: %w", name, err))
+
+		return b
+	}
+
+	*field = &pattern
+
+	return b
+}
@@ -0,0 +1,59 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestBuilder(t *testing.T) {
+	pattern := urlpattern.NewBuilder().
+		Protocol("https").
+		Hostname("*.example.com").
+		Pathname("/:id").
+		MustBuild()
+
+	if pattern.Protocol() != "https" || pattern.Hostname() != "*.example.com" || pattern.Pathname() != "/:id" || pattern.Search() != "*" {
+		t.Errorf("unexpected pattern %q %q %q %q", pattern.Protocol(), pattern.Hostname(), pattern.Pathname(), pattern.Search())
+	}
+
+	r := pattern.Exec("https://api.example.com/42", "")
+	if r == nil || r.Pathname.Groups["id"] != "42" {
+		t.Errorf("unexpected result %#v", r)
+	}
+}
+
+func TestBuilderBaseURLAndIgnoreCase(t *testing.T) {
+	pattern, err := urlpattern.NewBuilder().
+		BaseURL("https://example.com").
+		Pathname("/books/:id").
+		IgnoreCase().
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !pattern.Test("https://example.com/BOOKS/1", "") {
+		t.Error("want match")
+	}
+	if pattern.Test("https://example.org/books/1", "") {
+		t.Error("want no match")
+	}
+}
+
+func TestBuilderValidatesAsItGoes(t *testing.T) {
+	b := urlpattern.NewBuilder().Pathname("/(foo").Hostname("example.com")
+
+	_, err := b.Build()
+	if !errors.Is(err, urlpattern.ErrTypeError) {
+		t.Fatalf("want ErrTypeError; got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustBuild must panic")
+		}
+	}()
+	b.MustBuild()
+}
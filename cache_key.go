@@ -0,0 +1,84 @@
+package urlpattern
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// CacheKey returns a stable cache key made of the parts of r selected by
+// include, for caching layers keying responses on some parts of the URL only.
+// A selector is either:
+//   - the name of a component (e.g. "hostname"), for its canonicalized input,
+//   - a component name and a group name separated by a dot (e.g.
+//     "pathname.id"), for the value of the group,
+//   - a query parameter name preceded by "?" (e.g. "?page"), for its values
+//     in the search component, in order.
+//
+// For instance, to ignore the search except the page parameter:
+//
+//	key := result.CacheKey([]string{"protocol", "hostname", "port", "pathname", "?page"})
+//
+// The selectors are sorted and deduplicated, so the order of include doesn't
+// matter, and the key is URL-encoded: "hostname=example.com&pathname.id=42".
+// Unknown components and groups, and missing query parameters, are written
+// without "=", so that they differ from empty ones. It returns an empty
+// string if r is nil.
+func (r *URLPatternResult) CacheKey(include []string) string {
+	if r == nil {
+		return ""
+	}
+
+	selectors := slices.Compact(slices.Sorted(slices.Values(include)))
+
+	var query url.Values
+	var b strings.Builder
+	write := func(selector, value string, ok bool) {
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(selector))
+		if ok {
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(value))
+		}
+	}
+
+	for _, s := range selectors {
+		if param, ok := strings.CutPrefix(s, "?"); ok {
+			if query == nil {
+				query, _ = url.ParseQuery(r.Search.Input)
+			}
+
+			values, ok := query[param]
+			if !ok {
+				write(s, "", false)
+			}
+			for _, v := range values {
+				write(s, v, true)
+			}
+
+			continue
+		}
+
+		name, group, isGroup := strings.Cut(s, ".")
+
+		c := r.component(name)
+		if c == nil {
+			write(s, "", false)
+
+			continue
+		}
+
+		if !isGroup {
+			write(s, c.Input, true)
+
+			continue
+		}
+
+		// Groups may lack the groups which matched the empty string.
+		write(s, c.Groups[group], group != "" && slices.Contains(c.groupNames, group))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestCacheKey(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://:tenant.example.com/books/:id{/:format}?\\?*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := pattern.Exec("https://acme.example.com/books/42?utm_source=x&page=2&tag=a&tag=b%20c", "")
+	if r == nil {
+		t.Fatal("must match")
+	}
+
+	for _, tt := range []struct {
+		include  []string
+		expected string
+	}{
+		{[]string{"protocol", "hostname", "pathname", "?page"}, "%3Fpage=2&hostname=acme.example.com&pathname=%2Fbooks%2F42&protocol=https"},
+		{[]string{"?page", "pathname", "hostname", "protocol", "pathname"}, "%3Fpage=2&hostname=acme.example.com&pathname=%2Fbooks%2F42&protocol=https"},
+		{[]string{"hostname.tenant", "pathname.id"}, "hostname.tenant=acme&pathname.id=42"},
+		{[]string{"pathname.format", "pathname.missing", "?tag", "?lang", "unknown"}, "%3Flang&%3Ftag=a&%3Ftag=b+c&pathname.format=&pathname.missing&unknown"},
+		{nil, ""},
+	} {
+		if key := r.CacheKey(tt.include); key != tt.expected {
+			t.Errorf("CacheKey(%q) = %q, want %q", tt.include, key, tt.expected)
+		}
+	}
+
+	other := pattern.Exec("https://acme.example.com/books/42?page=2&utm_source=y", "")
+	if r.CacheKey([]string{"hostname", "pathname", "?page"}) != other.CacheKey([]string{"hostname", "pathname", "?page"}) {
+		t.Error("ignored query parameters must not change the key")
+	}
+
+	var nilResult *urlpattern.URLPatternResult
+	if nilResult.CacheKey([]string{"pathname"}) != "" {
+		t.Error("the key of a nil result must be empty")
+	}
+}
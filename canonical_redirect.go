@@ -0,0 +1,123 @@
+package urlpattern
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CanonicalRedirect returns a middleware enforcing the canonical form of URLs
+// described by canonical: requests whose URL isn't in canonical form are
+// redirected to it with the 308 Permanent Redirect status, which preserves
+// their method and body. For instance, to only serve HTTPS requests for
+// example.com, without trailing slash:
+//
+//	canonical, err := urlpattern.Compile("https://example.com/:path*")
+//	// ...
+//	handler = urlpattern.CanonicalRedirect(canonical, urlpattern.ProxyTrust{})(handler)
+//
+// The canonical URL is built from the URL of the request, canonicalized as by
+// the WHATWG URL standard (lowercase hostname, no default port...). Each
+// component not matched by canonical is replaced by the first of these
+// candidates it matches: its lowercase form, the pathname without or with a
+// trailing slash, and the pattern of the component if it is fixed text (e.g.
+// "https" for the protocol or "example.com" for the hostname). Requests whose
+// URL can't be fixed this way, and requests already in canonical form, are
+// passed to next.
+//
+// The URL of the request is reconstructed with trust, see
+// ProxyTrust.RequestURL.
+func CanonicalRedirect(canonical *URLPattern, trust ProxyTrust) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			input := trust.RequestURL(r)
+			if target, ok := canonical.canonicalURL(input); ok && target != input {
+				http.Redirect(w, r, target, http.StatusPermanentRedirect)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// canonicalURL returns the canonical form of input according to u, see
+// CanonicalRedirect, and whether input could be fixed to match u.
+func (u *URLPattern) canonicalURL(input string) (string, bool) {
+	c, err := u.parseURL(input, "")
+	if err != nil {
+		return "", false
+	}
+
+	values := map[string]string{
+		"protocol": c.Protocol,
+		"username": c.Username,
+		"password": c.Password,
+		"hostname": c.Hostname,
+		"port":     c.Port,
+		"pathname": c.Pathname,
+		"search":   c.Search,
+		"hash":     c.Hash,
+	}
+
+	for _, name := range componentNames {
+		component := u.component(name)
+		value := values[name]
+		if u.exec(name, component, value) != nil {
+			continue
+		}
+
+		candidates := []string{strings.ToLower(value)}
+		if name == "pathname" {
+			if trimmed, ok := strings.CutSuffix(value, "/"); ok && trimmed != "" {
+				candidates = append(candidates, trimmed)
+			} else {
+				candidates = append(candidates, value+"/")
+			}
+		}
+		if fixed, ok := component.fixedValue(); ok {
+			candidates = append(candidates, fixed)
+		}
+
+		fixed := false
+		for _, candidate := range candidates {
+			if u.exec(name, component, candidate) != nil {
+				values[name], fixed = candidate, true
+
+				break
+			}
+		}
+		if !fixed {
+			return "", false
+		}
+	}
+
+	target := assembleURL(values)
+	if !u.Test(target, "") {
+		return "", false
+	}
+
+	return target, true
+}
+
+// fixedValue returns the value matched by c without its optional parts, and
+// whether its other parts are fixed text.
+func (c *component) fixedValue() (string, bool) {
+	if c.portRanges != nil {
+		return "", false
+	}
+
+	var b strings.Builder
+	for _, p := range c.partList {
+		switch {
+		case p.modifier == partModifierOptional || p.modifier == partModifierZeroOrMore:
+			continue
+		case p.pType != partFixedText:
+			return "", false
+		}
+
+		b.WriteString(p.value)
+	}
+
+	return b.String(), true
+}
@@ -0,0 +1,50 @@
+package urlpattern_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestCanonicalRedirect(t *testing.T) {
+	canonical, err := urlpattern.Compile("https://example.com/:path*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := urlpattern.CanonicalRedirect(canonical, urlpattern.ProxyTrust{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, tt := range []struct {
+		method   string
+		target   string
+		location string
+	}{
+		{"GET", "https://example.com/books/1", ""},
+		{"GET", "https://example.com/", ""},
+		{"GET", "http://example.com/books/1?page=2", "https://example.com/books/1?page=2"},
+		{"POST", "https://example.com/books/1/", "https://example.com/books/1"},
+		{"GET", "https://EXAMPLE.com/books", "https://example.com/books"},
+		{"GET", "http://www.example.com:8080/books/", "https://example.com/books"},
+		{"GET", "https://example.com/books//1", ""},
+	} {
+		r := httptest.NewRequest(tt.method, tt.target, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if tt.location == "" {
+			if w.Code != http.StatusNoContent {
+				t.Errorf("%s: want no redirect; got %d to %s", tt.target, w.Code, w.Header().Get("Location"))
+			}
+
+			continue
+		}
+
+		if w.Code != http.StatusPermanentRedirect || w.Header().Get("Location") != tt.location {
+			t.Errorf("%s: want redirect to %s; got %d to %s", tt.target, tt.location, w.Code, w.Header().Get("Location"))
+		}
+	}
+}
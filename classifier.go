@@ -0,0 +1,185 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidClass is returned by NewClassifier when the label of a class
+// refers to a group or a component missing from its pattern.
+var ErrInvalidClass = errors.New("invalid class")
+
+// Class is a class of URLs, see Classifier.
+type Class struct {
+	Pattern *URLPattern
+	// Label is the label of the URLs matching Pattern. It may project parts
+	// of the match with placeholders written {selector}: with the pattern
+	// "https://example.com/api/v:version/*", the label "api-v{version}" gives
+	// "api-v2" for "https://example.com/api/v2/books". A selector is either:
+	//   - the name of a component (e.g. "hostname"), for its input,
+	//   - a component name and a group name separated by a dot (e.g.
+	//     "pathname.id"), for the value of the group,
+	//   - a group name, for the value of the group in the first component
+	//     having it in the order of the specification.
+	Label string
+}
+
+// Classifier assigns labels to URLs, e.g. to bucket metrics, apply rate
+// limits or route A/B tests by kind of page:
+//
+//	classifier, err := urlpattern.NewClassifier(
+//		urlpattern.Class{Pattern: product, Label: "product-page"},
+//		urlpattern.Class{Pattern: api, Label: "api-v{version}"},
+//	)
+//	label := classifier.Classify("https://example.com/api/v2/books")
+//
+// A Classifier is safe for concurrent use.
+type Classifier struct {
+	list   URLPatternList
+	labels [][]labelPart
+}
+
+// labelPart is either fixed text or the selector of a part of the match
+// projected in a label.
+type labelPart struct {
+	text     string
+	selector string
+}
+
+// NewClassifier returns a Classifier trying the classes in order. It returns
+// ErrInvalidClass if a label refers to a group or a component missing from
+// the pattern of its class.
+func NewClassifier(classes ...Class) (*Classifier, error) {
+	c := &Classifier{labels: make([][]labelPart, len(classes))}
+	for i, class := range classes {
+		parts := parseLabel(class.Label)
+		for _, p := range parts {
+			if p.selector != "" && !class.Pattern.hasSelector(p.selector) {
+				return nil, fmt.Errorf("%w: class #%d (%q): %q not found", ErrInvalidClass, i, class.Label, p.selector)
+			}
+		}
+
+		c.labels[i] = parts
+		c.list.Add(class.Pattern)
+	}
+
+	return c, nil
+}
+
+// Classify returns the label of the first class matching input, with the
+// values of the projected groups, or an empty string if no class matches.
+func (c *Classifier) Classify(input string) string {
+	index, _, result := c.list.match(input)
+	if index == -1 {
+		return ""
+	}
+
+	return expandLabel(c.labels[index], result)
+}
+
+// hasSelector reports whether the part of the matches of u selected by
+// selector exists, see Class.Label.
+func (u *URLPattern) hasSelector(selector string) bool {
+	if name, group, ok := strings.Cut(selector, "."); ok {
+		c := u.component(name)
+
+		return c != nil && slices.Contains(c.groupNames, group)
+	}
+
+	if u.component(selector) != nil {
+		return true
+	}
+
+	return slices.ContainsFunc(componentNames[:], func(name string) bool {
+		return slices.Contains(u.component(name).groupNames, selector)
+	})
+}
+
+// expandLabel returns the label made of parts, with the selected parts of
+// result. Missing parts are replaced by empty strings.
+func expandLabel(parts []labelPart, result *URLPatternResult) string {
+	var b strings.Builder
+	for _, p := range parts {
+		if p.selector == "" {
+			b.WriteString(p.text)
+
+			continue
+		}
+
+		if name, group, ok := strings.Cut(p.selector, "."); ok {
+			if c := result.component(name); c != nil {
+				b.WriteString(c.Groups[group])
+			}
+
+			continue
+		}
+
+		if c := result.component(p.selector); c != nil {
+			b.WriteString(c.Input)
+
+			continue
+		}
+
+		for _, name := range componentNames {
+			if value, ok := result.component(name).Groups[p.selector]; ok {
+				b.WriteString(value)
+
+				break
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// parseLabel splits label into fixed text and placeholders. Braces not
+// enclosing a valid selector are fixed text.
+func parseLabel(label string) []labelPart {
+	var parts []labelPart
+	for label != "" {
+		start := strings.IndexByte(label, '{')
+		if start == -1 {
+			break
+		}
+
+		end := strings.IndexByte(label[start:], '}')
+		if end == -1 {
+			break
+		}
+
+		selector := label[start+1 : start+end]
+		if !isValidSelector(selector) {
+			parts = append(parts, labelPart{text: label[:start+1]})
+			label = label[start+1:]
+
+			continue
+		}
+
+		if start > 0 {
+			parts = append(parts, labelPart{text: label[:start]})
+		}
+		parts = append(parts, labelPart{selector: selector})
+		label = label[start+end+1:]
+	}
+
+	if label != "" {
+		parts = append(parts, labelPart{text: label})
+	}
+
+	return parts
+}
+
+// isValidSelector reports whether s is a component name, a group name, or
+// both separated by a dot.
+func isValidSelector(s string) bool {
+	if name, group, ok := strings.Cut(s, "."); ok {
+		s = group
+		if !slices.Contains(componentNames[:], name) {
+			return false
+		}
+	}
+
+	return isValidName(s) || IsAnonymousGroupName(s)
+}
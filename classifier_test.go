@@ -0,0 +1,49 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestClassifier(t *testing.T) {
+	var patterns []*urlpattern.URLPattern
+	for _, p := range []string{
+		"https://example.com/products/:id",
+		"https://:region.example.com/api/v:version/*",
+		"https://example.com/*",
+	} {
+		u, err := urlpattern.Compile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		patterns = append(patterns, u)
+	}
+
+	classifier, err := urlpattern.NewClassifier(
+		urlpattern.Class{Pattern: patterns[0], Label: "product-page"},
+		urlpattern.Class{Pattern: patterns[1], Label: "api-v{version}-{hostname.region}{0}{}{a-b}{search}"},
+		urlpattern.Class{Pattern: patterns[2], Label: "other"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for input, expected := range map[string]string{
+		"https://example.com/products/42":          "product-page",
+		"https://eu.example.com/api/v2/books?q=go": "api-v2-eubooks{}{a-b}q=go",
+		"https://example.com/products/42/reviews":  "other",
+		"https://example.org/":                     "",
+	} {
+		if label := classifier.Classify(input); label != expected {
+			t.Errorf("Classify(%q) = %q, want %q", input, label, expected)
+		}
+	}
+
+	for _, label := range []string{"product-{slug}", "product-{hostname.id}"} {
+		if _, err := urlpattern.NewClassifier(urlpattern.Class{Pattern: patterns[0], Label: label}); !errors.Is(err, urlpattern.ErrInvalidClass) {
+			t.Errorf("%s: unexpected error %v", label, err)
+		}
+	}
+}
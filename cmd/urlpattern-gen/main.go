@@ -0,0 +1,77 @@
+// Command urlpattern-gen generates Go code declaring the routes of a route
+// table, see urlpattern.GenerateRoutes and routes.LoadFS.
+//
+// Usage:
+//
+//	urlpattern-gen [-pkg name] [-o file] table
+//
+// The table is a YAML or TOML file, or a glob matching several files,
+// relative to the current directory. For instance, with go:generate:
+//
+//	//go:generate go run github.com/dunglas/go-urlpattern/cmd/urlpattern-gen -pkg routes -o routes_gen.go routes.yaml
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dunglas/go-urlpattern"
+	"github.com/dunglas/go-urlpattern/routes"
+)
+
+func main() {
+	pkg := flag.String("pkg", "", "package of the generated file (default: the name of the directory of the output)")
+	output := flag.String("o", "", "output file (default: standard output)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-pkg name] [-o file] table\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *pkg, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "urlpattern-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(table, pkg, output string) error {
+	if pkg == "" {
+		dir, err := filepath.Abs(filepath.Dir(output))
+		if err != nil {
+			return err
+		}
+
+		pkg = filepath.Base(dir)
+	}
+
+	dir, glob := filepath.Split(table)
+	if dir == "" {
+		dir = "."
+	}
+
+	list, err := routes.LoadFS(os.DirFS(dir), glob)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := urlpattern.GenerateRoutes(&b, list, urlpattern.GenerateOptions{Package: pkg, Source: filepath.ToSlash(table)}); err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err := os.Stdout.Write(b.Bytes())
+
+		return err
+	}
+
+	return os.WriteFile(output, b.Bytes(), 0o644)
+}
@@ -0,0 +1,290 @@
+package urlpattern
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	gotoken "go/token"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrUnsupportedGeneration is returned by GenerateRoutes for patterns
+	// compiled with options other than WithIgnoreCase and WithBaseURL, which
+	// cannot be reproduced in the generated code.
+	ErrUnsupportedGeneration = errors.New("pattern options not supported by the code generator")
+	// ErrInvalidPackageName is returned by GenerateRoutes when the package
+	// name isn't a Go identifier.
+	ErrInvalidPackageName = errors.New("invalid package name")
+)
+
+// GenerateOptions configures GenerateRoutes.
+type GenerateOptions struct {
+	// Package is the name of the package of the generated file.
+	Package string
+	// Source is the name of the route table, mentioned in the header of the
+	// generated file.
+	Source string
+}
+
+// GenerateRoutes writes the Go source code of a file declaring the routes of
+// list, typically loaded with the Load or LoadFS functions of the routes
+// subpackage. For each route, the file declares:
+//   - a constant holding the name of the route, if any (e.g. RouteBook),
+//   - the pattern, built from its canonical components (e.g. BookPattern),
+//   - a constant for each group name (e.g. BookGroupID), except for the
+//     components matching anything ("*"),
+//   - a struct with a field for each group (e.g. BookParams), and a function
+//     extracting it from the result of a match (e.g. NewBookParams).
+//
+// NewRoutes returns a URLPatternList containing all the routes, with their
+// name and priority, in the order of list. The metadata of the routes aren't
+// generated.
+//
+// Route tables are parsed and validated when generating the code, not when
+// the program starts, and typos in group names become compilation errors.
+// As Go regular expressions cannot be built at compile time, the patterns
+// are still compiled when the generated package is initialized.
+//
+// The cmd/urlpattern-gen command wraps GenerateRoutes for go:generate.
+func GenerateRoutes(w io.Writer, list *URLPatternList, opts GenerateOptions) error {
+	if !gotoken.IsIdentifier(opts.Package) {
+		return fmt.Errorf("%w: %q", ErrInvalidPackageName, opts.Package)
+	}
+
+	var b bytes.Buffer
+
+	b.WriteString("// Code generated by urlpattern-gen")
+	if opts.Source != "" {
+		b.WriteString(" from " + opts.Source)
+	}
+	b.WriteString(". DO NOT EDIT.\n\npackage " + opts.Package + "\n\nimport \"github.com/dunglas/go-urlpattern\"\n")
+
+	routes := list.Routes()
+	identifiers := make([]string, len(routes))
+	groups := make([][]generatedGroup, len(routes))
+	// used holds the identifiers declared at the top level of the file, so
+	// that the identifiers derived from different route names don't collide
+	// (e.g. NewBookParams for "book" and for "new book").
+	used := map[string]bool{"NewRoutes": true, "mustCompile": true, "ptr": true}
+	for i, r := range routes {
+		if !r.Pattern.config.generatable() {
+			return fmt.Errorf("%w: route #%d", ErrUnsupportedGeneration, i)
+		}
+
+		groups[i] = routeGroups(r.Pattern)
+
+		id := goIdentifier(r.Name)
+		if id == "" || !unicode.IsLetter(firstCodePoint(id)) {
+			id = "Route" + strconv.Itoa(i)
+		}
+		for base, n := id, 2; slices.ContainsFunc(declaredIdentifiers(id, r, groups[i]), func(s string) bool { return used[s] }); n++ {
+			id = base + strconv.Itoa(n)
+		}
+		for _, s := range declaredIdentifiers(id, r, groups[i]) {
+			used[s] = true
+		}
+		identifiers[i] = id
+	}
+
+	if slices.ContainsFunc(routes, func(r Route) bool { return r.Name != "" }) {
+		b.WriteString("\n// Names of the routes.\nconst (\n")
+		for i, r := range routes {
+			if r.Name != "" {
+				fmt.Fprintf(&b, "\tRoute%s = %q\n", identifiers[i], r.Name)
+			}
+		}
+		b.WriteString(")\n")
+	}
+
+	for i, r := range routes {
+		writeRoute(&b, identifiers[i], r, groups[i])
+	}
+
+	b.WriteString("\n// NewRoutes returns a list containing all the routes.\nfunc NewRoutes() *urlpattern.URLPatternList {\n\tlist := &urlpattern.URLPatternList{}\n")
+	for i, r := range routes {
+		fmt.Fprintf(&b, "\tlist.AddRoute(urlpattern.Route{Pattern: %sPattern", identifiers[i])
+		if r.Name != "" {
+			fmt.Fprintf(&b, ", Name: Route%s", identifiers[i])
+		}
+		if r.Priority != 0 {
+			fmt.Fprintf(&b, ", Priority: %d", r.Priority)
+		}
+		b.WriteString("})\n")
+	}
+	b.WriteString("\n\treturn list\n}\n")
+
+	b.WriteString(`
+func mustCompile(init *urlpattern.URLPatternInit, opts ...urlpattern.Option) *urlpattern.URLPattern {
+	pattern, err := urlpattern.Compile(init, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return pattern
+}
+
+func ptr(s string) *string {
+	return &s
+}
+`)
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(src)
+
+	return err
+}
+
+// generatable reports whether the generated code can reproduce the patterns
+// compiled with c: the base URL is already applied to the pattern strings of
+// the components, and ignoreCase is the only option passed to the generated
+// code. The other options, including the ones added later, are unsupported.
+func (c config) generatable() bool {
+	c.baseURL, c.ignoreCase = nil, false
+
+	return reflect.ValueOf(c).IsZero()
+}
+
+// generatedGroup is a group of a route, see GenerateRoutes.
+type generatedGroup struct {
+	component string
+	name      string
+	field     string
+}
+
+// writeRoute writes the declarations of route r, named id, having groups.
+func writeRoute(b *bytes.Buffer, id string, r Route, groups []generatedGroup) {
+	u := r.Pattern
+
+	fmt.Fprintf(b, "\n// %sPattern is the pattern of the %s route.\nvar %sPattern = mustCompile(&urlpattern.URLPatternInit{\n", id, routeDescription(id, r), id)
+	for _, name := range componentNames {
+		fmt.Fprintf(b, "\t%s: ptr(%q),\n", exportedName(name), u.component(name).patternString)
+	}
+	b.WriteString("}")
+	if u.config.ignoreCase {
+		b.WriteString(", urlpattern.WithIgnoreCase()")
+	}
+	b.WriteString(")\n")
+
+	if len(groups) > 0 {
+		fmt.Fprintf(b, "\n// Group names of the %s route.\nconst (\n", routeDescription(id, r))
+		for _, g := range groups {
+			fmt.Fprintf(b, "\t%sGroup%s = %q\n", id, g.field, g.name)
+		}
+		b.WriteString(")\n")
+	}
+
+	fmt.Fprintf(b, "\n// %sParams holds the groups of the %s route.\ntype %sParams struct {\n", id, routeDescription(id, r), id)
+	for _, g := range groups {
+		fmt.Fprintf(b, "\t%s string // %q group of the %s\n", g.field, g.name, g.component)
+	}
+	b.WriteString("}\n")
+
+	fmt.Fprintf(b, "\n// New%sParams extracts the groups of r, the result of a match of %sPattern.\nfunc New%sParams(r *urlpattern.URLPatternResult) %sParams {\n\treturn %sParams{\n", id, id, id, id, id)
+	for _, g := range groups {
+		fmt.Fprintf(b, "\t\t%s: r.%s.Groups[%sGroup%s],\n", g.field, exportedName(g.component), id, g.field)
+	}
+	b.WriteString("\t}\n}\n")
+}
+
+// routeGroups returns the named groups of u, see GenerateRoutes.
+func routeGroups(u *URLPattern) []generatedGroup {
+	var groups []generatedGroup
+	count := make(map[string]int)
+	for _, component := range componentNames {
+		// The group of "*" components is rarely useful.
+		if u.component(component).patternString == "*" {
+			continue
+		}
+
+		for _, name := range u.component(component).groupNames {
+			if name != "" {
+				groups = append(groups, generatedGroup{component: component, name: name})
+				count[goIdentifier(name)]++
+			}
+		}
+	}
+
+	used := make(map[string]bool, len(groups))
+	for i := range groups {
+		g := &groups[i]
+
+		field := goIdentifier(g.name)
+		if count[field] > 1 || IsAnonymousGroupName(g.name) {
+			field = exportedName(g.component) + field
+		}
+		if field == "" || !unicode.IsLetter(firstCodePoint(field)) || used[field] {
+			field = exportedName(g.component) + "Group" + strconv.Itoa(i)
+		}
+		used[field] = true
+		g.field = field
+	}
+
+	return groups
+}
+
+// declaredIdentifiers returns the top-level identifiers declared by
+// writeRoute for route r, named id, having groups.
+func declaredIdentifiers(id string, r Route, groups []generatedGroup) []string {
+	identifiers := []string{id + "Pattern", id + "Params", "New" + id + "Params"}
+	if r.Name != "" {
+		identifiers = append(identifiers, "Route"+id)
+	}
+	for _, g := range groups {
+		identifiers = append(identifiers, id+"Group"+g.field)
+	}
+
+	return identifiers
+}
+
+// routeDescription returns the name of r to use in comments.
+func routeDescription(id string, r Route) string {
+	if r.Name != "" {
+		return strconv.Quote(r.Name)
+	}
+
+	return id
+}
+
+// exportedName capitalizes the first letter of the ASCII string s.
+func exportedName(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// initialisms are the words written in uppercase in Go identifiers.
+var initialisms = map[string]bool{"api": true, "id": true, "ip": true, "uri": true, "url": true, "uuid": true}
+
+// goIdentifier converts s, such as "user_id" or "book-list", to an exported Go
+// identifier, such as "UserID" or "BookList".
+func goIdentifier(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+
+			continue
+		}
+
+		r := firstCodePoint(word)
+		b.WriteRune(unicode.ToUpper(r))
+		b.WriteString(word[len(string(r)):])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,144 @@
+package urlpattern_test
+
+import (
+	"bytes"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+	"github.com/dunglas/go-urlpattern/routes"
+)
+
+func TestGenerateRoutes(t *testing.T) {
+	list, err := routes.Load(strings.NewReader(`routes:
+  - pattern: https://:tenant.example.com/books/:id
+    name: book
+    priority: 10
+  - pattern: https://example.com/static/*
+  - pattern: https://example.com/users/:user_id/:id?
+    name: user-profile
+    ignoreCase: true
+`), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := urlpattern.GenerateRoutes(&b, list, urlpattern.GenerateOptions{Package: "routes", Source: "routes.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parser.ParseFile(token.NewFileSet(), "routes_gen.go", b.Bytes(), parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name.Name != "routes" {
+		t.Errorf("unexpected package %s", f.Name.Name)
+	}
+
+	src := b.String()
+	for _, want := range []string{
+		"// Code generated by urlpattern-gen from routes.yaml. DO NOT EDIT.",
+		`RouteBook        = "book"`,
+		`Hostname: ptr(":tenant.example.com"),`,
+		`BookGroupTenant = "tenant"`,
+		"Tenant: r.Hostname.Groups[BookGroupTenant],",
+		`Route1GroupPathname0 = "0"`,
+		"}, urlpattern.WithIgnoreCase())",
+		"UserID string // \"user_id\" group of the pathname",
+		"list.AddRoute(urlpattern.Route{Pattern: BookPattern, Name: RouteBook, Priority: 10})",
+		"list.AddRoute(urlpattern.Route{Pattern: Route1Pattern})",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code doesn't contain %q:\n%s", want, src)
+		}
+	}
+
+	list, err = routes.Load(strings.NewReader(`routes:
+  - pattern: https://example.com/books/:id
+    name: book
+  - pattern: https://example.com/books/new
+    name: new book
+  - pattern: https://example.com/books/:id/cover
+    name: book pattern
+  - pattern: https://example.com/books/:id/reviews
+    name: route book
+  - pattern: https://example.com/books/:id/ptr
+    name: ptr
+`), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.Reset()
+	if err := urlpattern.GenerateRoutes(&b, list, urlpattern.GenerateOptions{Package: "routes"}); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = parser.ParseFile(token.NewFileSet(), "routes_gen.go", b.Bytes(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	declared := make(map[string]int)
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			declared[d.Name.Name]++
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						declared[name.Name]++
+					}
+				case *ast.TypeSpec:
+					declared[spec.Name.Name]++
+				}
+			}
+		}
+	}
+	for name, n := range declared {
+		if n > 1 {
+			t.Errorf("%s declared %d times:\n%s", name, n, b.String())
+		}
+	}
+	for _, want := range []string{"NewBookParams", "NewBook2Params", "NewNewBook2Params", "RouteBookPattern", "RouteBook2Pattern", "PtrPattern"} {
+		if declared[want] != 1 {
+			t.Errorf("%s not declared:\n%s", want, b.String())
+		}
+	}
+
+	pattern, err := urlpattern.Compile("https://example.com/*", urlpattern.WithWildcardNames("rest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	list = &urlpattern.URLPatternList{}
+	list.Add(pattern)
+	if err := urlpattern.GenerateRoutes(&b, list, urlpattern.GenerateOptions{Package: "routes"}); !errors.Is(err, urlpattern.ErrUnsupportedGeneration) {
+		t.Errorf("want ErrUnsupportedGeneration; got %v", err)
+	}
+
+	if err := urlpattern.GenerateRoutes(&b, list, urlpattern.GenerateOptions{Package: "my-routes"}); !errors.Is(err, urlpattern.ErrInvalidPackageName) {
+		t.Errorf("want ErrInvalidPackageName; got %v", err)
+	}
+
+	pattern, err = urlpattern.New("/books/:id", "https://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list = &urlpattern.URLPatternList{}
+	list.Add(pattern)
+
+	b.Reset()
+	if err := urlpattern.GenerateRoutes(&b, list, urlpattern.GenerateOptions{Package: "routes"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `Hostname: ptr("example.com"),`) {
+		t.Errorf("want the base URL applied to the hostname:\n%s", b.String())
+	}
+}
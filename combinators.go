@@ -0,0 +1,110 @@
+package urlpattern
+
+import "maps"
+
+// Matcher matches URLs. It is implemented by *URLPattern, *ShadowMatcher and
+// the combinators returned by Any and All, which can be nested to express
+// conditions such as "the pathname matches A or B, and the hostname matches
+// C":
+//
+//	m := urlpattern.All(urlpattern.Any(a, b), c)
+//	result := m.Exec("https://example.com/books/42", "")
+type Matcher interface {
+	// Exec matches input, relative to baseURL if not empty, and returns the
+	// result of the match, or nil if input doesn't match.
+	Exec(input, baseURL string) *URLPatternResult
+	// Test reports whether input, relative to baseURL if not empty,
+	// matches.
+	Test(input, baseURL string) bool
+}
+
+// Any returns a Matcher matching the URLs matched by at least one of
+// matchers. They are tried in order, and the result is the one of the first
+// matching Matcher. Any without matchers matches nothing.
+func Any(matchers ...Matcher) Matcher {
+	return anyMatcher(matchers)
+}
+
+// All returns a Matcher matching the URLs matched by all of matchers. They
+// are tried in order, and matching stops at the first one not matching. The
+// result holds the groups of all the results: when several matchers have a
+// group with the same name, the value of the last one is used. All without
+// matchers matches nothing.
+//
+// Each Matcher parses the input, so combining many patterns is slower than
+// matching a single pattern describing all the components.
+func All(matchers ...Matcher) Matcher {
+	return allMatcher(matchers)
+}
+
+type anyMatcher []Matcher
+
+func (m anyMatcher) Exec(input, baseURL string) *URLPatternResult {
+	for _, matcher := range m {
+		if result := matcher.Exec(input, baseURL); result != nil {
+			return result
+		}
+	}
+
+	return nil
+}
+
+func (m anyMatcher) Test(input, baseURL string) bool {
+	for _, matcher := range m {
+		if matcher.Test(input, baseURL) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type allMatcher []Matcher
+
+func (m allMatcher) Exec(input, baseURL string) *URLPatternResult {
+	var merged *URLPatternResult
+	for _, matcher := range m {
+		result := matcher.Exec(input, baseURL)
+		if result == nil {
+			return nil
+		}
+
+		if merged != nil {
+			mergeResults(result, merged)
+		}
+		merged = result
+	}
+
+	return merged
+}
+
+func (m allMatcher) Test(input, baseURL string) bool {
+	for _, matcher := range m {
+		if !matcher.Test(input, baseURL) {
+			return false
+		}
+	}
+
+	return len(m) > 0
+}
+
+// mergeResults adds the groups and search parameters of previous missing
+// from r.
+func mergeResults(r, previous *URLPatternResult) {
+	mergeGroups(&r.Protocol, previous.Protocol)
+	mergeGroups(&r.Username, previous.Username)
+	mergeGroups(&r.Password, previous.Password)
+	mergeGroups(&r.Hostname, previous.Hostname)
+	mergeGroups(&r.Port, previous.Port)
+	mergeGroups(&r.Pathname, previous.Pathname)
+	mergeGroups(&r.Search, previous.Search)
+	mergeGroups(&r.Hash, previous.Hash)
+
+	if len(previous.SearchParams) == 0 {
+		return
+	}
+
+	searchParams := maps.Clone(previous.SearchParams)
+	maps.Copy(searchParams, r.SearchParams)
+	r.SearchParams = searchParams
+}
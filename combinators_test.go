@@ -0,0 +1,41 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestCombinators(t *testing.T) {
+	var patterns []*urlpattern.URLPattern
+	for _, p := range []string{"*://*/books/:id", "*://*/authors/:id", "https://:subdomain.example.com/*"} {
+		u, err := urlpattern.Compile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		patterns = append(patterns, u)
+	}
+	books, authors, host := patterns[0], patterns[1], patterns[2]
+
+	m := urlpattern.All(urlpattern.Any(books, authors), host)
+
+	r := m.Exec("https://api.example.com/authors/42", "")
+	if r == nil || r.Pathname.Groups["id"] != "42" || r.Hostname.Groups["subdomain"] != "api" || r.Pathname.Groups["0"] != "authors/42" {
+		t.Fatalf("unexpected result %#v", r)
+	}
+	if !m.Test("https://api.example.com/books/42", "") {
+		t.Error("books must match")
+	}
+
+	for _, input := range []string{"https://api.example.org/books/42", "https://api.example.com/publishers/42"} {
+		if m.Test(input, "") || m.Exec(input, "") != nil {
+			t.Errorf("%q must not match", input)
+		}
+	}
+
+	for _, m := range []urlpattern.Matcher{urlpattern.Any(), urlpattern.All()} {
+		if m.Test("https://example.com/", "") || m.Exec("https://example.com/", "") != nil {
+			t.Error("empty combinators must match nothing")
+		}
+	}
+}
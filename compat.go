@@ -0,0 +1,104 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+)
+
+//go:generate go run ./internal/compatgen -o compat_table.go
+
+// ErrCompatDivergence is returned when a pattern compiled with
+// WithStrictCompat is canonicalized differently than by browsers.
+var ErrCompatDivergence = errors.New("canonicalization diverges from browsers")
+
+// CompatDivergence describes a component canonicalized differently than by
+// browsers.
+type CompatDivergence struct {
+	// Component is the name of the component, e.g. "hostname".
+	Component string
+	// Input is the value of the component in the constructor string or the
+	// URLPatternInit.
+	Input string
+	// Got is the pattern string of the component.
+	Got string
+	// Want is the pattern string produced by browsers.
+	Want string
+}
+
+func (d CompatDivergence) String() string {
+	return fmt.Sprintf("%s %q canonicalized to %q, browsers produce %q", d.Component, d.Input, d.Got, d.Want)
+}
+
+// WithStrictCompat cross-checks, when compiling, the canonicalization of the
+// components against a table of results observed in browsers, generated from
+// the Web Platform Tests embedded in the urlpatterntest package. The URL
+// parser used by this package occasionally differs from browsers, and options
+// such as WithIDNAMode intentionally do.
+//
+// Divergences are passed to report. If report is nil, they are returned as
+// errors wrapping ErrCompatDivergence.
+func WithStrictCompat(report func(CompatDivergence)) Option {
+	return func(c *config) {
+		c.strictCompat = true
+		c.compatReport = report
+	}
+}
+
+// auditCompat checks the components of u created from init against
+// compatTable.
+func (init *URLPatternInit) auditCompat(u *URLPattern, special bool, report func(CompatDivergence)) error {
+	for _, name := range componentNames {
+		input := init.component(name)
+		if input == nil || (!special && (name == "hostname" || name == "pathname")) {
+			continue
+		}
+
+		want, ok := compatTable[name][*input]
+		if !ok {
+			continue
+		}
+
+		c := u.component(name)
+		if c.ignored {
+			continue
+		}
+
+		got := c.patternString
+		if got == want {
+			continue
+		}
+
+		d := CompatDivergence{Component: name, Input: *input, Got: got, Want: want}
+		if report == nil {
+			return fmt.Errorf("%w: %s", ErrCompatDivergence, d)
+		}
+
+		report(d)
+	}
+
+	return nil
+}
+
+// component returns the value of the component with the given name, or nil.
+func (init *URLPatternInit) component(name string) *string {
+	switch name {
+	case "protocol":
+		return init.Protocol
+	case "username":
+		return init.Username
+	case "password":
+		return init.Password
+	case "hostname":
+		return init.Hostname
+	case "port":
+		return init.Port
+	case "pathname":
+		return init.Pathname
+	case "search":
+		return init.Search
+	case "hash":
+		return init.Hash
+	}
+
+	return nil
+}
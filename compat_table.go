@@ -0,0 +1,75 @@
+// Code generated by compatgen. DO NOT EDIT.
+
+package urlpattern
+
+// compatTable maps the input of components to the pattern strings produced by
+// browsers, derived from the expected results of the Web Platform Tests (see
+// the urlpatterntest package). Hostname and pathname entries apply to special
+// schemes.
+var compatTable = map[string]map[string]string{
+	"protocol": {
+		"(.*)":      "*",
+		"http":      "http",
+		"http{s}?:": "http{s}?",
+	},
+	"username": {
+		"café": "caf%C3%A9",
+	},
+	"password": {
+		"café": "caf%C3%A9",
+	},
+	"hostname": {
+		":domain(.*)":                 ":domain(.*)",
+		"[\\:\\:AB\\::num]":           "[\\:\\:ab\\::num]",
+		"bad\thostname":               "badhostname",
+		"bad\nhostname":               "badhostname",
+		"bad\rhostname":               "badhostname",
+		"bad#hostname":                "bad",
+		"bad/hostname":                "bad",
+		"bad\\\\hostname":             "bad",
+		"café.com":                    "xn--caf-dma.com",
+		"example.com#ignoredhash":     "example.com",
+		"example.com/ignoredpath":     "example.com",
+		"example.com\\?ignoredsearch": "example.com",
+	},
+	"port": {
+		"(.*)": "*",
+		"80 ":  "80",
+	},
+	"pathname": {
+		"(foo)?(.*)":      "(foo)?*",
+		"*\\/*":           "*/{*}",
+		"*{}**?":          "*(.*)?",
+		"/:foo\\bar":      "{/:foo}bar",
+		"/café":           "/caf%C3%A9",
+		"/foo/(.*)":       "/foo/*",
+		"/foo/(.*)*":      "/foo/**",
+		"/foo/(.*)+":      "/foo/*+",
+		"/foo/(.*)?":      "/foo/*?",
+		"/foo/../bar":     "/bar",
+		"/foo\\{":         "/foo%7B",
+		"/foo{/bar}":      "/foo/bar",
+		":a󠄀b":            ":a󠄀b",
+		":foo\\bar":       "{:foo}bar",
+		":foo{}(.*)":      "{:foo}(.*)",
+		":foo{}?bar":      "{:foo}bar",
+		":foo{}bar":       "{:foo}bar",
+		"test/:a𐑐b":       "test/:a𐑐b",
+		"var x = 1;":      "var%20x%20=%201;",
+		"{:foo\\.bar}":    "{:foo.bar}",
+		"{:foo}:bar(.*)":  ":foo:bar(.*)",
+		"{:foo}?(.*)":     ":foo?*",
+		"{:foo}{(.*)bar}": ":foo{*bar}",
+		"{:foo}{(.*)}":    "{:foo}(.*)",
+		"{:foo}{bar(.*)}": ":foo{bar*}",
+		"� �":             "%EF%BF%BD%20%EF%BF%BD",
+	},
+	"search": {
+		"?bar":   "bar",
+		"q=café": "q=caf%C3%A9",
+	},
+	"hash": {
+		"#baz": "baz",
+		"café": "caf%C3%A9",
+	},
+}
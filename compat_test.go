@@ -0,0 +1,37 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+	"github.com/dunglas/go-urlpattern/urlpatterntest"
+)
+
+func TestStrictCompatConformance(t *testing.T) {
+	urlpatterntest.Run(t, urlpatterntest.Entries(), urlpatterntest.Config{
+		Options: []urlpattern.Option{urlpattern.WithStrictCompat(nil)},
+	})
+}
+
+func TestStrictCompat(t *testing.T) {
+	hostname := "café.com"
+	init := &urlpattern.URLPatternInit{Hostname: &hostname}
+
+	_, err := urlpattern.Compile(init, urlpattern.WithIDNAMode(urlpattern.IDNAUnicode), urlpattern.WithStrictCompat(nil))
+	if !errors.Is(err, urlpattern.ErrCompatDivergence) || !errors.Is(err, urlpattern.ErrTypeError) {
+		t.Errorf("want ErrCompatDivergence; got %v", err)
+	}
+
+	var divergences []urlpattern.CompatDivergence
+	if _, err := urlpattern.Compile(init, urlpattern.WithIDNAMode(urlpattern.IDNAUnicode), urlpattern.WithStrictCompat(func(d urlpattern.CompatDivergence) {
+		divergences = append(divergences, d)
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	want := urlpattern.CompatDivergence{Component: "hostname", Input: "café.com", Got: "café.com", Want: "xn--caf-dma.com"}
+	if len(divergences) != 1 || divergences[0] != want {
+		t.Errorf("unexpected divergences %#v", divergences)
+	}
+}
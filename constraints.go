@@ -0,0 +1,172 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownConstraint is returned when a pattern uses a constraint that
+	// has not been registered.
+	ErrUnknownConstraint = errors.New("unknown constraint")
+	// ErrInvalidConstraint is returned by RegisterConstraint when the name or
+	// the regular expression of a constraint is invalid.
+	ErrInvalidConstraint = errors.New("invalid constraint")
+)
+
+var (
+	constraintRegexpsMu sync.RWMutex
+	constraintRegexps   = map[string]string{
+		"int":   `\d+`,
+		"alpha": `[a-zA-Z]+`,
+		"alnum": `[a-zA-Z0-9]+`,
+		"hex":   `[0-9a-fA-F]+`,
+		"slug":  `[a-z0-9]+(?:-[a-z0-9]+)*`,
+		"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+	}
+	// constraintMarkers holds, for the constraints which cannot be expressed
+	// with a regexp alone, the function inserting constraintMarker in the
+	// matched inputs before the position where the constraint is satisfied.
+	// The regexp of these constraints starts with constraintMarker.
+	constraintMarkers = map[string]func(input string) string{}
+)
+
+// constraintMarker is inserted in inputs by constraintMarkers. It cannot be
+// part of canonical URLs.
+const constraintMarker = "\x00"
+
+// WithConstraints enables the constraints syntax in pattern strings: a named
+// group followed by a constraint name between angle brackets, such as
+// ":id<int>", is translated to the regexp group registered for the
+// constraint, here ":id(\d+)".
+//
+// The "int", "alpha", "alnum", "hex", "slug" and "uuid" constraints are
+// built in, others can be added with RegisterConstraint. When building with
+// the "publicsuffix" tag, the "registrable" constraint matches exactly the
+// registrable domain ending a hostname, such as "example.co.uk" in
+// "https://{*.}?:domain<registrable>". Pattern strings
+// returned by the URLPattern contain the translated regexp groups.
+func WithConstraints() Option {
+	return func(c *config) {
+		c.constraints = true
+	}
+}
+
+// RegisterConstraint registers a constraint usable in patterns compiled with
+// WithConstraints, replacing any existing constraint with the same name.
+//
+// The name must only contain ASCII letters, digits, "-" and "_". The regular
+// expression follows the rules of regexp groups in pattern strings: it must
+// only contain ASCII code points and no capturing groups.
+func RegisterConstraint(name, re string) error {
+	if name == "" || strings.IndexFunc(name, func(r rune) bool { return !isConstraintNameCodePoint(r) }) != -1 {
+		return fmt.Errorf("%w: invalid name %q", ErrInvalidConstraint, name)
+	}
+
+	tl, err := tokenize("("+re+")", tokenizePolicyStrict)
+	if err != nil || len(tl) != 2 || tl[0].tType != tokenRegexp {
+		return fmt.Errorf("%w: invalid regexp %q", ErrInvalidConstraint, re)
+	}
+
+	if _, err := regexp.Compile(re); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConstraint, err)
+	}
+
+	constraintRegexpsMu.Lock()
+	defer constraintRegexpsMu.Unlock()
+
+	constraintRegexps[name] = re
+
+	return nil
+}
+
+func isConstraintNameCodePoint(r rune) bool {
+	return r == '-' || r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// withConstraints wraps compile, translating the constraints used in the
+// pattern string to regexp groups first.
+func withConstraints(compile compileFunc) compileFunc {
+	return func(name, input string, encodingCallback encodingCallback, options options) (*component, error) {
+		input, markers, err := expandConstraints(input)
+		if err != nil {
+			return nil, err
+		}
+
+		c, err := compile(name, input, encodingCallback, options)
+		if err != nil || len(markers) == 0 {
+			return c, err
+		}
+
+		c.markInput = func(input string) string {
+			for _, mark := range markers {
+				input = mark(input)
+			}
+
+			return input
+		}
+
+		return c, nil
+	}
+}
+
+// expandConstraints replaces the constraints following named groups in the
+// pattern string input by the corresponding regexp groups. It also returns the
+// markers of the constraints used, see constraintMarkers.
+func expandConstraints(input string) (string, []func(string) string, error) {
+	tl, err := tokenize(input, tokenizePolicyLenient)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		result  strings.Builder
+		markers []func(string) string
+		last    int
+	)
+
+	for i := 0; i < len(tl)-1; i++ {
+		if tl[i].tType != tokenName || tl[i+1].tType != tokenChar || tl[i+1].value != "<" {
+			continue
+		}
+
+		end := i + 2
+		for end < len(tl) && tl[end].tType == tokenChar && tl[end].value != ">" {
+			end++
+		}
+
+		if end == i+2 || end == len(tl) || tl[end].tType != tokenChar {
+			continue
+		}
+
+		constraint := input[tl[i+2].index:tl[end].index]
+
+		constraintRegexpsMu.RLock()
+		re, ok := constraintRegexps[constraint]
+		mark := constraintMarkers[constraint]
+		constraintRegexpsMu.RUnlock()
+
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %q", ErrUnknownConstraint, constraint)
+		}
+		if mark != nil {
+			markers = append(markers, mark)
+		}
+
+		result.WriteString(input[last:tl[i+1].index])
+		result.WriteString("(" + re + ")")
+		last = tl[end].index + 1
+		i = end
+	}
+
+	if last == 0 {
+		return input, nil, nil
+	}
+
+	result.WriteString(input[last:])
+
+	return result.String(), markers, nil
+}
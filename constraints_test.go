@@ -0,0 +1,71 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func ExampleWithConstraints() {
+	pattern, err := urlpattern.Compile("https://example.com/users/:id<int>/posts/:slug<slug>", urlpattern.WithConstraints())
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(pattern.Pathname())
+	fmt.Println(pattern.Test("https://example.com/users/42/posts/hello-world", ""))
+	fmt.Println(pattern.Test("https://example.com/users/john/posts/hello-world", ""))
+	// Output:
+	// /users/:id(\d+)/posts/:slug([a-z0-9]+(?:-[a-z0-9]+)*)
+	// true
+	// false
+}
+
+func TestRegisterConstraint(t *testing.T) {
+	if err := urlpattern.RegisterConstraint("year", `\d{4}`); err != nil {
+		t.Fatal(err)
+	}
+
+	pattern, err := urlpattern.Compile("https://example.com/archives/:year<year>{/:month<int>}?", urlpattern.WithConstraints())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := pattern.Exec("https://example.com/archives/2024/05", "")
+	if r == nil || r.Pathname.Groups["year"] != "2024" || r.Pathname.Groups["month"] != "05" {
+		t.Errorf("unexpected result %#v", r)
+	}
+
+	if pattern.Test("https://example.com/archives/24", "") {
+		t.Error("pattern must not match")
+	}
+
+	for _, re := range []string{`(\d+)`, "é+", "[a-z"} {
+		if err := urlpattern.RegisterConstraint("invalid", re); !errors.Is(err, urlpattern.ErrInvalidConstraint) {
+			t.Errorf("%q: want ErrInvalidConstraint; got %v", re, err)
+		}
+	}
+
+	if err := urlpattern.RegisterConstraint("in valid", `\d+`); !errors.Is(err, urlpattern.ErrInvalidConstraint) {
+		t.Errorf("want ErrInvalidConstraint; got %v", err)
+	}
+}
+
+func TestUnknownConstraint(t *testing.T) {
+	_, err := urlpattern.Compile("https://example.com/users/:id<unknown>", urlpattern.WithConstraints())
+	if !errors.Is(err, urlpattern.ErrUnknownConstraint) || !errors.Is(err, urlpattern.ErrTypeError) {
+		t.Errorf("want ErrUnknownConstraint; got %v", err)
+	}
+
+	// Without WithConstraints, the angle brackets are fixed text.
+	pattern, err := urlpattern.Compile("https://example.com/users/:id<unknown>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !pattern.Test("https://example.com/users/42<unknown>", "") {
+		t.Error("pattern must match")
+	}
+}
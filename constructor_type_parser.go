@@ -1,9 +1,9 @@
 package urlpattern
 
 import (
-	"regexp"
-
-	"golang.org/x/exp/utf8string"
+	"fmt"
+	"strings"
+	"sync"
 )
 
 type state uint8
@@ -11,7 +11,8 @@ type state uint8
 // https://urlpattern.spec.whatwg.org/#constructor-string-parsing
 
 type constructorTypeParser struct {
-	input                         utf8string.String
+	input                         string
+	urlParser                     URLParser
 	tokenList                     []token
 	result                        URLPatternInit
 	componentStart                int
@@ -40,9 +41,10 @@ const (
 )
 
 // https://urlpattern.spec.whatwg.org/#parse-a-constructor-string
-func newConstructorTypeParser(input string, tokenList []token) constructorTypeParser {
+func newConstructorTypeParser(input string, urlParser URLParser, tokenList []token) constructorTypeParser {
 	return constructorTypeParser{
-		input:          *utf8string.NewString(input),
+		input:          input,
+		urlParser:      urlParser,
 		tokenList:      tokenList,
 		result:         URLPatternInit{},
 		tokenIncrement: 1,
@@ -51,13 +53,15 @@ func newConstructorTypeParser(input string, tokenList []token) constructorTypePa
 }
 
 // https://urlpattern.spec.whatwg.org/#constructor-string-parsing
-func parseConstructorString(input string) (*URLPatternInit, error) {
+//
+// The protocol is canonicalized with urlParser.
+func parseConstructorString(input string, urlParser URLParser) (*URLPatternInit, error) {
 	tl, err := tokenize(input, tokenizePolicyLenient)
 	if err != nil {
 		return nil, err
 	}
 
-	p := newConstructorTypeParser(input, tl)
+	p := newConstructorTypeParser(input, urlParser, tl)
 
 	tlLen := len(p.tokenList)
 
@@ -294,6 +298,10 @@ func (p *constructorTypeParser) getSafeToken(index int) token {
 	}
 
 	// Assert: parser's token list's size is greater than or equal to 1.
+	// The tokenizer always ends the list with an end token.
+	if len == 0 {
+		return token{tType: tokenEnd}
+	}
 
 	return p.tokenList[len-1]
 }
@@ -363,7 +371,7 @@ func (p *constructorTypeParser) makeComponentString() string {
 	componentStartInputIndex := componentStartToken.index
 	endIndex := token.index
 
-	return p.input.Slice(componentStartInputIndex, endIndex)
+	return p.input[componentStartInputIndex:endIndex]
 }
 
 // https://urlpattern.spec.whatwg.org/#is-a-protocol-suffix
@@ -374,7 +382,15 @@ func (p *constructorTypeParser) isProtocolSuffix() bool {
 // https://urlpattern.spec.whatwg.org/#compute-protocol-matches-a-special-scheme-flag
 func (p *constructorTypeParser) computeProtocolMatchesSpecialSchemeFlag() error {
 	protocol := p.makeComponentString()
-	protocolComponent, err := compileComponent(protocol, canonicalizeProtocol, options{})
+
+	// Fast path for literal schemes, which don't need to be compiled.
+	if isPlainScheme(protocol) {
+		_, p.protocolMatchesASpecialScheme = specialSchemeSet[strings.ToLower(protocol)]
+
+		return nil
+	}
+
+	protocolComponent, err := compileComponent("protocol", protocol, withParser(p.urlParser, canonicalizeProtocol), options{})
 	if err != nil {
 		return err
 	}
@@ -386,6 +402,28 @@ func (p *constructorTypeParser) computeProtocolMatchesSpecialSchemeFlag() error
 	return nil
 }
 
+// isPlainScheme reports whether s is a valid scheme without pattern syntax
+// ("+" being a modifier in patterns).
+//
+// https://url.spec.whatwg.org/#scheme-state
+func isPlainScheme(s string) bool {
+	if s == "" || !isASCIIAlpha(s[0]) {
+		return false
+	}
+
+	for i := 1; i < len(s); i++ {
+		if c := s[i]; !isASCIIAlpha(c) && (c < '0' || c > '9') && c != '-' && c != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isASCIIAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
 // https://urlpattern.spec.whatwg.org/#next-is-authority-slashes
 func (p *constructorTypeParser) nextIsAuthoritySlashes() bool {
 	return p.isNonSpecialPatternChar(p.tokenIndex+1, "/") && p.isNonSpecialPatternChar(p.tokenIndex+2, "/")
@@ -421,20 +459,70 @@ func (p *constructorTypeParser) isIPV6Close() bool {
 	return p.isNonSpecialPatternChar(p.tokenIndex, "]")
 }
 
+// compileFunc is the signature of compileComponent, which can be wrapped to
+// support syntax extensions.
+type compileFunc func(name, input string, encodingCallback encodingCallback, options options) (*component, error)
+
 // https://urlpattern.spec.whatwg.org/#compile-a-component
-func compileComponent(input string, encodencodingCallback encodingCallback, options options) (*component, error) {
+//
+// name is the name of the compiled component (e.g. "pathname"), used to
+// report errors.
+func compileComponent(name, input string, encodencodingCallback encodingCallback, options options) (*component, error) {
+	if input == "*" {
+		return wildcardComponent(name, options)
+	}
+
 	partList, err := parsePatternString(input, options, encodencodingCallback)
 	if err != nil {
 		return nil, err
 	}
 
+	return compilePartList(name, partList, options)
+}
+
+// wildcardComponents holds the *component compiled from the "*" pattern
+// string for each options value. Most patterns use the wildcard for several
+// components: sharing it cuts the memory used by large route tables.
+// Components are immutable once compiled.
+var wildcardComponents sync.Map
+
+// wildcardComponent returns the shared component compiled from "*".
+func wildcardComponent(name string, options options) (*component, error) {
+	if c, ok := wildcardComponents.Load(options); ok {
+		return c.(*component), nil
+	}
+
+	// The wildcard contains no fixed text to encode.
+	partList, err := parsePatternString("*", options, func(s string) (string, error) { return s, nil })
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := compilePartList(name, partList, options)
+	if err != nil {
+		return nil, err
+	}
+	c.shared = true
+
+	actual, _ := wildcardComponents.LoadOrStore(options, c)
+
+	return actual.(*component), nil
+}
+
+// compilePartList runs the steps of compile a component following the
+// parsing of the pattern string.
+func compilePartList(name string, partList partList, options options) (*component, error) {
+	if err := checkRegexpFeatures(name, partList); err != nil {
+		return nil, err
+	}
+
 	// Let (regular expression string, name list) be the result of running generate a regular expression and name list given part list and options.
 	regularExpressionString, nameList, err := partList.generateRegularExpressionAndNameList(options)
 	if err != nil {
 		return nil, err
 	}
 
-	regularExpression, err := regexp.Compile(regularExpressionString)
+	regularExpression, err := compileRegexp(regularExpressionString)
 	if err != nil {
 		return nil, err
 	}
@@ -444,14 +532,65 @@ func compileComponent(input string, encodencodingCallback encodingCallback, opti
 		return nil, err
 	}
 
-	var hasRegexpGroups bool
+	var (
+		hasRegexpGroups  bool
+		optionalGroups   map[string]bool
+		repeatSeparators map[string]string
+	)
 	for _, part := range partList {
 		if part.pType == partRegexp {
 			hasRegexpGroups = true
+		}
 
-			break
+		if part.pType != partFixedText && (part.modifier == partModifierOptional || part.modifier == partModifierZeroOrMore) {
+			if optionalGroups == nil {
+				optionalGroups = make(map[string]bool)
+			}
+			optionalGroups[part.name] = true
 		}
+
+		if part.pType != partFixedText && (part.modifier == partModifierOneOrMore || part.modifier == partModifierZeroOrMore) && part.suffix+part.prefix != "" {
+			if repeatSeparators == nil {
+				repeatSeparators = make(map[string]string)
+			}
+			repeatSeparators[part.name] = part.suffix + part.prefix
+		}
+	}
+
+	groupNames := make([]string, regularExpression.NumSubexp()+1)
+	groupIndex := make(map[string]int, len(nameList))
+	reserved := partList.customCaptureNames()
+	var subexpIndexes []int
+	for i, name := range nameList {
+		var index int
+		if isCaptureName(name, reserved) {
+			index = regularExpression.SubexpIndex(name)
+		} else {
+			if subexpIndexes == nil {
+				subexpIndexes = partList.subexpIndexes()
+			}
+			index = subexpIndexes[i]
+		}
+
+		// Regexp parts are checked to be valid on their own, so the group
+		// of each part can always be found.
+		if index <= 0 || index >= len(groupNames) {
+			return nil, fmt.Errorf("%w: group %q not found in %q", ErrInternal, name, regularExpressionString)
+		}
+
+		groupNames[index] = name
+		groupIndex[name] = index
 	}
 
-	return &component{patternString, regularExpression, nameList, hasRegexpGroups}, nil
+	return &component{
+		patternString:     patternString,
+		regularExpression: regularExpression,
+		groupNames:        groupNames,
+		groupIndex:        groupIndex,
+		optionalGroups:    optionalGroups,
+		repeatSeparators:  repeatSeparators,
+		hasRegexpGroups:   hasRegexpGroups,
+		partList:          partList,
+		options:           options,
+	}, nil
 }
@@ -0,0 +1,64 @@
+package urlpattern
+
+import "context"
+
+// contextKey is the key of the results stored in contexts by NewContext.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying result, typically the result of
+// the pattern matching the URL of an HTTP request, so that handlers can read
+// the matched groups with ParamsFromContext and Param:
+//
+//	r = r.WithContext(urlpattern.NewContext(r.Context(), result))
+func NewContext(ctx context.Context, result *URLPatternResult) context.Context {
+	return context.WithValue(ctx, contextKey{}, result)
+}
+
+// ParamsFromContext returns the groups of all the components of the result
+// carried by ctx, see NewContext. The groups of the hostname are params as
+// the groups of the pathname are, e.g. to extract tenants from subdomains:
+// with the pattern "https://:tenant.example.com/books/:id", the params of
+// "https://acme.example.com/books/1" are "tenant" (acme) and "id" (1).
+//
+// When several components have a group with the same name, the value of the
+// first one in the order of the specification (protocol, username, password,
+// hostname, port, pathname, search, hash) is used: hostname groups take
+// precedence over pathname groups. Groups which didn't participate in the
+// match (see URLPatternComponentResult.Has) are omitted. It returns nil if ctx
+// carries no result.
+func ParamsFromContext(ctx context.Context) map[string]string {
+	result, _ := ctx.Value(contextKey{}).(*URLPatternResult)
+	if result == nil {
+		return nil
+	}
+
+	params := make(map[string]string)
+	for _, c := range componentNames {
+		r := result.component(c)
+		for _, name := range r.groupNames {
+			if _, ok := params[name]; !ok && r.Has(name) {
+				params[name] = r.Groups[name]
+			}
+		}
+	}
+
+	return params
+}
+
+// Param returns the value of the group named name of the result carried by
+// ctx, with the precedence of ParamsFromContext, or an empty string if there
+// is no such group.
+func Param(ctx context.Context, name string) string {
+	result, _ := ctx.Value(contextKey{}).(*URLPatternResult)
+	if result == nil {
+		return ""
+	}
+
+	for _, c := range componentNames {
+		if r := result.component(c); r.Has(name) {
+			return r.Groups[name]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,50 @@
+package urlpattern_test
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestParamsFromContext(t *testing.T) {
+	if params := urlpattern.ParamsFromContext(context.Background()); params != nil {
+		t.Errorf("want nil; got %v", params)
+	}
+	if p := urlpattern.Param(context.Background(), "id"); p != "" {
+		t.Errorf("want empty string; got %q", p)
+	}
+
+	u, err := urlpattern.Compile("https://:id.example.com/books/:id/:format?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := urlpattern.NewContext(context.Background(), u.Exec("https://shop.example.com/books/42", ""))
+
+	if params, want := urlpattern.ParamsFromContext(ctx), map[string]string{"id": "shop", "0": ""}; !maps.Equal(params, want) {
+		t.Errorf("got %v; want %v", params, want)
+	}
+	if p := urlpattern.Param(ctx, "id"); p != "shop" {
+		t.Errorf("got %q; want shop", p)
+	}
+	if p := urlpattern.Param(ctx, "format"); p != "" {
+		t.Errorf("got %q; want empty string", p)
+	}
+}
+
+func TestParamFromSubdomain(t *testing.T) {
+	u, err := urlpattern.Compile("https://:tenant.:region.example.com/:region/books/:id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := urlpattern.NewContext(context.Background(), u.Exec("https://acme.eu.example.com/us/books/1", ""))
+
+	for name, expected := range map[string]string{"tenant": "acme", "region": "eu", "id": "1"} {
+		if p := urlpattern.Param(ctx, name); p != expected {
+			t.Errorf("%s: got %q; want %q", name, p, expected)
+		}
+	}
+}
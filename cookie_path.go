@@ -0,0 +1,27 @@
+package urlpattern
+
+import "strings"
+
+// MatchesCookiePath reports whether requestPath path-matches patternPath, the
+// Path attribute of a cookie, as defined by RFC 6265 section 5.1.4: the paths
+// are identical, or patternPath is a prefix of requestPath ending at a "/".
+// "/docs" matches "/docs", "/docs/" and "/docs/web", but not "/docsets".
+//
+// The paths are compared as is, without canonicalization, and an empty
+// requestPath is treated as "/". patternPath must start with "/": otherwise
+// the cookie uses the default path (see RFC 6265 section 5.1.4), and
+// MatchesCookiePath returns false.
+func MatchesCookiePath(patternPath, requestPath string) bool {
+	if !strings.HasPrefix(patternPath, "/") {
+		return false
+	}
+	if requestPath == "" {
+		requestPath = "/"
+	}
+
+	if !strings.HasPrefix(requestPath, patternPath) {
+		return false
+	}
+
+	return len(requestPath) == len(patternPath) || strings.HasSuffix(patternPath, "/") || requestPath[len(patternPath)] == '/'
+}
@@ -0,0 +1,36 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestMatchesCookiePath(t *testing.T) {
+	for _, tt := range []struct {
+		cookiePath, requestPath string
+		expected                bool
+	}{
+		{"/", "/", true},
+		{"/", "", true},
+		{"/", "/docs/web", true},
+		{"/docs", "/docs", true},
+		{"/docs", "/docs/", true},
+		{"/docs", "/docs/web/http", true},
+		{"/docs", "/docsets", false},
+		{"/docs", "/Docs", false},
+		{"/docs", "/", false},
+		{"/docs/", "/docs/", true},
+		{"/docs/", "/docs/web", true},
+		{"/docs/", "/docs", false},
+		{"/a+b(c)*", "/a+b(c)*/d", true},
+		{"/a+b(c)*", "/aab(c)", false},
+		{"/docs", "/docs/../admin", true},
+		{"docs", "docs", false},
+		{"", "/", false},
+	} {
+		if got := urlpattern.MatchesCookiePath(tt.cookiePath, tt.requestPath); got != tt.expected {
+			t.Errorf("MatchesCookiePath(%q, %q) = %v, want %v", tt.cookiePath, tt.requestPath, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package urlpattern
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// CoverageReport tells how a list of patterns covers a corpus of URLs, see
+// Coverage.
+type CoverageReport struct {
+	// Routes holds the coverage of each route of the list, in insertion
+	// order (see URLPatternList.Routes).
+	Routes []RouteCoverage
+	// Unmatched holds the URLs matched by no route, without duplicates, in
+	// the order of the corpus.
+	Unmatched []string
+	// Total is the number of URLs of the corpus, duplicates included.
+	Total int
+}
+
+// RouteCoverage is the coverage of a route, see CoverageReport.
+type RouteCoverage struct {
+	Route Route
+	// Matches is the number of URLs of the corpus dispatched to the route.
+	Matches int
+}
+
+// Unused returns the routes which matched no URL of the corpus, in insertion
+// order.
+func (r CoverageReport) Unused() []Route {
+	var routes []Route
+	for _, c := range r.Routes {
+		if c.Matches == 0 {
+			routes = append(routes, c.Route)
+		}
+	}
+
+	return routes
+}
+
+// Coverage matches the URLs read from urls, one absolute URL per line,
+// against list, and reports which routes never matched and which URLs matched
+// no route, so that operators can prune dead routes and find gaps in the
+// routing from access logs. Blank lines and lines starting with "#" are
+// ignored, and spaces around URLs are trimmed.
+//
+// Each URL is counted for the route returned by URLPatternList.Exec only: a
+// route always shadowed by routes matched before it is reported as unused.
+func Coverage(list *URLPatternList, urls io.Reader) (CoverageReport, error) {
+	routes := list.Routes()
+	report := CoverageReport{Routes: make([]RouteCoverage, len(routes))}
+	for i, r := range routes {
+		report.Routes[i].Route = r
+	}
+
+	unmatched := make(map[string]bool)
+
+	s := bufio.NewScanner(urls)
+	for s.Scan() {
+		input := strings.TrimSpace(s.Text())
+		if input == "" || strings.HasPrefix(input, "#") {
+			continue
+		}
+
+		report.Total++
+
+		// The corpus isn't recorded in the metrics of the list.
+		if index, _, _ := list.match(input); index != -1 {
+			report.Routes[index].Matches++
+
+			continue
+		}
+
+		if !unmatched[input] {
+			unmatched[input] = true
+			report.Unmatched = append(report.Unmatched, input)
+		}
+	}
+
+	return report, s.Err()
+}
@@ -0,0 +1,60 @@
+package urlpattern_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+	"github.com/dunglas/go-urlpattern/routes"
+)
+
+func TestCoverage(t *testing.T) {
+	list, err := routes.Load(strings.NewReader(`
+routes:
+  - name: book
+    pattern: https://example.com/books/:id
+  - name: books
+    pattern: https://example.com/books/*
+  - name: shadowed
+    pattern: https://example.com/books/:id/reviews
+  - name: author
+    pattern: https://example.com/authors/:id
+`), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := urlpattern.Coverage(list, strings.NewReader(`# access log
+https://example.com/books/1
+https://example.com/books/2
+  https://example.com/books/1/reviews
+
+https://example.com/publishers/1
+https://example.com/publishers/1
+https://example.com/
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Total != 6 {
+		t.Errorf("unexpected total %d", report.Total)
+	}
+
+	matches := make([]int, len(report.Routes))
+	for i, r := range report.Routes {
+		matches[i] = r.Matches
+	}
+	if len(matches) != 4 || matches[0] != 2 || matches[1] != 1 || matches[2] != 0 || matches[3] != 0 {
+		t.Errorf("unexpected matches %v", matches)
+	}
+
+	unused := report.Unused()
+	if len(unused) != 2 || unused[0].Name != "shadowed" || unused[1].Name != "author" {
+		t.Errorf("unexpected unused routes %v", unused)
+	}
+
+	if len(report.Unmatched) != 2 || report.Unmatched[0] != "https://example.com/publishers/1" || report.Unmatched[1] != "https://example.com/" {
+		t.Errorf("unexpected unmatched URLs %q", report.Unmatched)
+	}
+}
@@ -0,0 +1,236 @@
+package urlpattern
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidCSPSource is returned by CompileCSPSource and ParseCSPSourceList
+// for invalid Content Security Policy source expressions.
+var ErrInvalidCSPSource = errors.New("invalid CSP source expression")
+
+// cspSchemes lists, for each scheme of a source expression, the schemes of
+// the URLs it matches, see the "scheme-part match" algorithm of CSP.
+var cspSchemes = map[string][]string{
+	"http":  {"http", "https"},
+	"https": {"https"},
+	"ws":    {"ws", "wss", "http", "https"},
+	"wss":   {"wss", "https"},
+}
+
+// CompileCSPSource compiles a Content Security Policy source expression, such
+// as "https:", "*.example.com:443", "https://example.com/js/" or "'self'", to
+// a URLPattern matching the URLs allowed by the expression.
+//
+// self is the origin of the protected resource, such as
+// "https://example.com". It is required by the 'self' keyword and, if set,
+// restricts the schemes allowed by expressions without a scheme to the one of
+// self, as browsers do; otherwise they allow http and https. Schemes are
+// upgraded as in CSP: "http:" also allows https, "ws:" wss, http and https.
+//
+// Paths ending with "/" match all the paths below them, other paths match
+// exactly.
+func CompileCSPSource(expr, self string, opts ...Option) (*URLPattern, error) {
+	var selfScheme string
+	if self != "" {
+		u, err := urlParser.Parse(self)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid origin %q: %w", ErrInvalidCSPSource, self, err)
+		}
+		selfScheme = u.Scheme()
+
+		if strings.EqualFold(expr, "'self'") {
+			protocol, hostname, port := cspProtocol(cspSchemeList(selfScheme)), EscapePatternString(u.Hostname()), EscapePatternString(u.Port())
+
+			return Compile(&URLPatternInit{Protocol: &protocol, Hostname: &hostname, Port: &port}, opts...)
+		}
+	}
+
+	if strings.EqualFold(expr, "'self'") {
+		return nil, fmt.Errorf("%w: 'self' requires an origin", ErrInvalidCSPSource)
+	}
+	if strings.HasPrefix(expr, "'") {
+		return nil, fmt.Errorf("%w: unsupported keyword %s", ErrInvalidCSPSource, expr)
+	}
+
+	rest := expr
+
+	scheme, afterScheme, hasScheme := strings.Cut(rest, ":")
+	if hasScheme && isCSPScheme(scheme) && (afterScheme == "" || strings.HasPrefix(afterScheme, "//")) {
+		scheme = strings.ToLower(scheme)
+		if afterScheme == "" {
+			// scheme-source
+			protocol := cspProtocol(cspSchemeList(scheme))
+
+			return Compile(&URLPatternInit{Protocol: &protocol}, opts...)
+		}
+
+		rest = afterScheme[2:]
+	} else {
+		scheme = ""
+	}
+
+	host, path := rest, "*"
+	if i := strings.IndexByte(rest, '/'); i != -1 {
+		host, path = rest[:i], EscapePatternString(rest[i:])
+		if strings.HasSuffix(path, "/") {
+			path += "*"
+		}
+	}
+
+	var port string
+	if i := strings.LastIndexByte(host, ':'); i != -1 {
+		host, port = host[:i], host[i+1:]
+		if port != "*" && (port == "" || strings.Trim(port, "0123456789") != "") {
+			return nil, fmt.Errorf("%w: invalid port in %q", ErrInvalidCSPSource, expr)
+		}
+	}
+
+	var hostname string
+	switch {
+	case host == "*":
+		hostname = "*"
+	case isCSPHost(strings.TrimPrefix(host, "*.")):
+		hostname = EscapePatternString(host)
+		if strings.HasPrefix(host, "*.") {
+			hostname = "*" + hostname[len(`\*`):]
+		}
+	default:
+		return nil, fmt.Errorf("%w: invalid host in %q", ErrInvalidCSPSource, expr)
+	}
+
+	var schemes []string
+	switch {
+	case scheme != "":
+		schemes = cspSchemeList(scheme)
+	case host == "*":
+		// "*" matches the network schemes and the one of self.
+		schemes = []string{"http", "https", "ws", "wss"}
+		if selfScheme != "" && !slices.Contains(schemes, selfScheme) {
+			schemes = append(schemes, selfScheme)
+		}
+	default:
+		schemes = cspSchemeList(cmp.Or(selfScheme, "http"))
+	}
+
+	// Without port, only the default ports are allowed, and ports omitted in
+	// URLs are default ports.
+	for _, s := range schemes {
+		if n, ok := DefaultPort(s); ok && n == port {
+			port = "{" + port + "}?"
+
+			break
+		}
+	}
+
+	protocol := cspProtocol(schemes)
+
+	return Compile(&URLPatternInit{Protocol: &protocol, Hostname: &hostname, Port: &port, Pathname: &path}, opts...)
+}
+
+// cspSchemeList returns the schemes of the URLs matched by scheme.
+func cspSchemeList(scheme string) []string {
+	if schemes, ok := cspSchemes[scheme]; ok {
+		return schemes
+	}
+
+	return []string{scheme}
+}
+
+// cspProtocol returns the protocol pattern matching schemes.
+func cspProtocol(schemes []string) string {
+	if len(schemes) == 1 {
+		return EscapePatternString(schemes[0])
+	}
+
+	escaped := make([]string, len(schemes))
+	for i, s := range schemes {
+		escaped[i] = EscapeRegexpString(s)
+	}
+
+	return "(" + strings.Join(escaped, "|") + ")"
+}
+
+// isCSPScheme reports whether s is a valid scheme-part.
+func isCSPScheme(s string) bool {
+	if s == "" || !isASCIIAlpha(s[0]) {
+		return false
+	}
+
+	for i := 1; i < len(s); i++ {
+		if c := s[i]; !isASCIIAlpha(c) && !(c >= '0' && c <= '9') && c != '+' && c != '-' && c != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isCSPHost reports whether s is a valid host-part without wildcard: labels of
+// alphanumeric characters and hyphens separated by dots.
+func isCSPHost(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for label := range strings.SplitSeq(s, ".") {
+		if label == "" {
+			return false
+		}
+
+		for i := range len(label) {
+			if c := label[i]; !isASCIIAlpha(c) && !(c >= '0' && c <= '9') && c != '-' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// CSPSourceList is a Content Security Policy source list, such as the value
+// of a script-src directive, whose expressions are compiled to URLPatterns.
+type CSPSourceList struct {
+	patterns []*URLPattern
+}
+
+// ParseCSPSourceList parses sources, a list of source expressions separated by
+// whitespace, see CompileCSPSource. Keywords which don't match URLs, such as
+// 'none', 'unsafe-inline', nonces and hashes, are ignored.
+func ParseCSPSourceList(sources, self string, opts ...Option) (*CSPSourceList, error) {
+	l := &CSPSourceList{}
+
+	for _, expr := range strings.Fields(sources) {
+		if strings.HasPrefix(expr, "'") && !strings.EqualFold(expr, "'self'") {
+			continue
+		}
+
+		u, err := CompileCSPSource(expr, self, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		l.patterns = append(l.patterns, u)
+	}
+
+	return l, nil
+}
+
+// Patterns returns the patterns of the expressions of the list.
+func (l *CSPSourceList) Patterns() []*URLPattern {
+	return l.patterns
+}
+
+// Matches reports whether the URL input is allowed by the list.
+func (l *CSPSourceList) Matches(input string) bool {
+	for _, u := range l.patterns {
+		if u.Test(input, "") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestCompileCSPSource(t *testing.T) {
+	for _, tc := range []struct {
+		expr    string
+		self    string
+		match   []string
+		noMatch []string
+	}{
+		{"https:", "", []string{"https://example.com/", "https://a.example.org:8443/x"}, []string{"http://example.com/"}},
+		{"http:", "", []string{"http://example.com/", "https://example.com/"}, []string{"ws://example.com/"}},
+		{"*.example.com", "", []string{"https://a.example.com/", "http://a.b.example.com/x"}, []string{"https://example.com/", "https://a.example.com:8443/", "ftp://a.example.com/"}},
+		{"*.example.com:443", "", []string{"https://a.example.com/", "https://a.example.com:443/"}, []string{"https://a.example.com:8443/"}},
+		{"example.com:*", "https://example.org", []string{"https://example.com:8443/"}, []string{"http://example.com/"}},
+		{"https://example.com/js/", "", []string{"https://example.com/js/", "https://example.com/js/app.js"}, []string{"https://example.com/js", "https://example.com/css/app.css"}},
+		{"https://example.com/app.js", "", []string{"https://example.com/app.js"}, []string{"https://example.com/app.js/x"}},
+		{"wss://example.com", "", []string{"wss://example.com/", "https://example.com/"}, []string{"ws://example.com/"}},
+		{"*", "https://example.com", []string{"https://example.org/", "ws://example.org/"}, []string{"data:text/plain,a", "https://example.org:8443/"}},
+		{"'self'", "https://example.com:8443", []string{"https://example.com:8443/a"}, []string{"https://example.com/a", "http://example.com:8443/a"}},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			u, err := urlpattern.CompileCSPSource(tc.expr, tc.self)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, input := range tc.match {
+				if !u.Test(input, "") {
+					t.Errorf("want %q to match", input)
+				}
+			}
+			for _, input := range tc.noMatch {
+				if u.Test(input, "") {
+					t.Errorf("want %q not to match", input)
+				}
+			}
+		})
+	}
+
+	for _, expr := range []string{"'self'", "'unsafe-inline'", "exa_mple.com", "example.com:x/", "example.com:8a", "*.", "a..b"} {
+		if _, err := urlpattern.CompileCSPSource(expr, ""); !errors.Is(err, urlpattern.ErrInvalidCSPSource) {
+			t.Errorf("%s: want ErrInvalidCSPSource; got %v", expr, err)
+		}
+	}
+}
+
+func TestCSPSourceList(t *testing.T) {
+	l, err := urlpattern.ParseCSPSourceList("'self' 'unsafe-inline' 'nonce-abc' https://cdn.example.net data:", "https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(l.Patterns()) != 3 {
+		t.Errorf("want 3 patterns; got %d", len(l.Patterns()))
+	}
+
+	for _, input := range []string{"https://example.com/app.js", "https://cdn.example.net/lib.js", "data:image/png;base64,AAAA"} {
+		if !l.Matches(input) {
+			t.Errorf("want %q to match", input)
+		}
+	}
+	for _, input := range []string{"https://evil.example/app.js", "http://cdn.example.net/lib.js"} {
+		if l.Matches(input) {
+			t.Errorf("want %q not to match", input)
+		}
+	}
+
+	l, err = urlpattern.ParseCSPSourceList("'none'", "https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Matches("https://example.com/") {
+		t.Error("want 'none' to match nothing")
+	}
+}
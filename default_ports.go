@@ -0,0 +1,67 @@
+package urlpattern
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrSpecialSchemeDefaultPort is returned by RegisterDefaultPort for the
+// special schemes, whose default ports are defined by the URL Standard.
+var ErrSpecialSchemeDefaultPort = errors.New("the default port of special schemes cannot be changed")
+
+// defaultPorts maps a protocol scheme to its default port string.
+//
+// TODO: there is nothing in the Go stdlib to find the default port
+// associated with a protocol. Only the specialSchemeSet entries are
+// populated for now; the list can be completed using
+// https://en.wikipedia.org/wiki/List_of_TCP_and_UDP_port_numbers.
+var defaultPorts = struct {
+	sync.RWMutex
+	ports map[string]string
+}{
+	ports: map[string]string{
+		"http":  "80",
+		"https": "443",
+		"ws":    "80",
+		"wss":   "443",
+		"ftp":   "21",
+	},
+}
+
+// DefaultPort returns the default port of a protocol scheme, and whether one
+// is known. It is safe for concurrent use.
+func DefaultPort(scheme string) (string, bool) {
+	defaultPorts.RLock()
+	defer defaultPorts.RUnlock()
+
+	port, ok := defaultPorts.ports[scheme]
+
+	return port, ok
+}
+
+// RegisterDefaultPort sets the default port of a protocol scheme, used by
+// WithPortRanges and WithStdlibURL. It is safe to call concurrently with the
+// compilation and the matching of patterns.
+//
+// The scheme is lowercased. ErrInvalidPort is returned if port isn't a number
+// between 0 and 65535, and ErrSpecialSchemeDefaultPort if scheme is a special
+// scheme.
+func RegisterDefaultPort(scheme, port string) error {
+	scheme = strings.ToLower(scheme)
+	if _, special := specialSchemeSet[scheme]; special {
+		return ErrSpecialSchemeDefaultPort
+	}
+
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || strconv.FormatUint(n, 10) != port {
+		return ErrInvalidPort
+	}
+
+	defaultPorts.Lock()
+	defer defaultPorts.Unlock()
+
+	defaultPorts.ports[scheme] = port
+
+	return nil
+}
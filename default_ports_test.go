@@ -0,0 +1,43 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestRegisterDefaultPort(t *testing.T) {
+	pattern, err := urlpattern.Compile("redis://example.com:{6379,6380}/*", urlpattern.WithPortRanges())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			pattern.Test("redis://example.com/0", "")
+		})
+	}
+	if err := urlpattern.RegisterDefaultPort("REDIS", "6379"); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	if port, ok := urlpattern.DefaultPort("redis"); !ok || port != "6379" {
+		t.Errorf("want 6379; got %q", port)
+	}
+	if !pattern.Test("redis://example.com/0", "") {
+		t.Error("want match")
+	}
+
+	if err := urlpattern.RegisterDefaultPort("https", "8443"); !errors.Is(err, urlpattern.ErrSpecialSchemeDefaultPort) {
+		t.Errorf("want ErrSpecialSchemeDefaultPort; got %v", err)
+	}
+	for _, port := range []string{"", "70000", "+80", "080"} {
+		if err := urlpattern.RegisterDefaultPort("redis", port); !errors.Is(err, urlpattern.ErrInvalidPort) {
+			t.Errorf("%q: want ErrInvalidPort; got %v", port, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package urlpattern
+
+// TableDiff reports the differences between two route tables, see DiffTables.
+type TableDiff struct {
+	// Added holds the routes of the new table missing from the old one.
+	Added []Route
+	// Removed holds the routes of the old table missing from the new one.
+	Removed []Route
+	// Changed holds the named routes present in both tables whose pattern
+	// has a different canonical form or whose priority changed.
+	Changed []RouteChange
+}
+
+// RouteChange is a route changed between two tables.
+type RouteChange struct {
+	Old, New Route
+}
+
+// Empty reports whether the tables are equivalent.
+func (d TableDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffTables compares two route tables, e.g. to gate risky routing changes in
+// deployment pipelines. Patterns are compared by their canonical form: the
+// pattern strings of their components (as returned by Protocol, Pathname...)
+// and whether they ignore case. Named routes are identified by their name,
+// and unnamed routes by the canonical form of their pattern.
+//
+// Routes are reported in the order of their table.
+func DiffTables(oldList, newList *URLPatternList) TableDiff {
+	var d TableDiff
+
+	oldNamed := make(map[string]Route)
+	oldUnnamed := make(map[canonicalPattern]int)
+	for _, r := range oldList.routes {
+		if r.Name == "" {
+			oldUnnamed[canonicalPatternOf(r.Pattern)]++
+		} else {
+			oldNamed[r.Name] = r
+		}
+	}
+
+	newNamed := make(map[string]struct{})
+	newUnnamed := make(map[canonicalPattern]int)
+	for _, r := range newList.routes {
+		if r.Name == "" {
+			key := canonicalPatternOf(r.Pattern)
+			if oldUnnamed[key] > newUnnamed[key] {
+				newUnnamed[key]++
+			} else {
+				d.Added = append(d.Added, r)
+			}
+
+			continue
+		}
+
+		newNamed[r.Name] = struct{}{}
+
+		old, ok := oldNamed[r.Name]
+		if !ok {
+			d.Added = append(d.Added, r)
+
+			continue
+		}
+
+		if canonicalPatternOf(old.Pattern) != canonicalPatternOf(r.Pattern) || old.Priority != r.Priority {
+			d.Changed = append(d.Changed, RouteChange{Old: old, New: r})
+		}
+	}
+
+	for _, r := range oldList.routes {
+		if r.Name != "" {
+			if _, ok := newNamed[r.Name]; !ok {
+				d.Removed = append(d.Removed, r)
+			}
+
+			continue
+		}
+
+		// Routes found in the new table are consumed first.
+		key := canonicalPatternOf(r.Pattern)
+		if newUnnamed[key] > 0 {
+			newUnnamed[key]--
+		} else {
+			d.Removed = append(d.Removed, r)
+		}
+	}
+
+	return d
+}
+
+// canonicalPattern is the canonical form of a URLPattern.
+type canonicalPattern struct {
+	components [len(componentNames)]string
+	ignoreCase bool
+}
+
+func canonicalPatternOf(u *URLPattern) canonicalPattern {
+	var c canonicalPattern
+	for i, name := range componentNames {
+		c.components[i] = u.component(name).patternString
+	}
+	c.ignoreCase = u.config.ignoreCase
+
+	return c
+}
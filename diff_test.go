@@ -0,0 +1,55 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestDiffTables(t *testing.T) {
+	newList := func(routes ...urlpattern.Route) *urlpattern.URLPatternList {
+		var l urlpattern.URLPatternList
+		for _, r := range routes {
+			l.AddRoute(r)
+		}
+
+		return &l
+	}
+	mustCompile := func(pattern string, opts ...urlpattern.Option) *urlpattern.URLPattern {
+		p, err := urlpattern.Compile(pattern, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return p
+	}
+
+	oldList := newList(
+		urlpattern.Route{Pattern: mustCompile("https://example.com/books/:id"), Name: "book"},
+		urlpattern.Route{Pattern: mustCompile("https://example.com/authors/:id"), Name: "author"},
+		urlpattern.Route{Pattern: mustCompile("https://example.com/about")},
+		urlpattern.Route{Pattern: mustCompile("https://example.com/legal")},
+	)
+	newTable := newList(
+		urlpattern.Route{Pattern: mustCompile("https://example.com/books/:id", urlpattern.WithIgnoreCase()), Name: "book"},
+		urlpattern.Route{Pattern: mustCompile("https://EXAMPLE.com/about")},
+		urlpattern.Route{Pattern: mustCompile("https://example.com/contact")},
+		urlpattern.Route{Pattern: mustCompile("https://example.com/authors/:id"), Name: "writer"},
+	)
+
+	d := urlpattern.DiffTables(oldList, newTable)
+
+	if len(d.Changed) != 1 || d.Changed[0].Old.Name != "book" {
+		t.Errorf("unexpected changed routes %#v", d.Changed)
+	}
+	if len(d.Added) != 2 || d.Added[0].Pattern.Pathname() != "/contact" || d.Added[1].Name != "writer" {
+		t.Errorf("unexpected added routes %#v", d.Added)
+	}
+	if len(d.Removed) != 2 || d.Removed[0].Name != "author" || d.Removed[1].Pattern.Pathname() != "/legal" {
+		t.Errorf("unexpected removed routes %#v", d.Removed)
+	}
+
+	if d := urlpattern.DiffTables(oldList, oldList); !d.Empty() {
+		t.Errorf("want no difference; got %#v", d)
+	}
+}
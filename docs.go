@@ -0,0 +1,292 @@
+package urlpattern
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownDocsFormat is returned by GenerateDocs for unknown formats.
+var ErrUnknownDocsFormat = errors.New("unknown docs format")
+
+// DocsFormat is the output format of GenerateDocs.
+type DocsFormat uint8
+
+const (
+	// DocsMarkdown generates a Markdown document. This is the default.
+	DocsMarkdown DocsFormat = iota
+	// DocsHTML generates an HTML fragment, to be embedded in a page.
+	DocsHTML
+)
+
+// DocsOptions configures GenerateDocs.
+type DocsOptions struct {
+	Format DocsFormat
+	// Title is the title of the document, "Routes" if empty.
+	Title string
+}
+
+// GenerateDocs writes the documentation of the routes of list, typically
+// loaded with the Load or LoadFS functions of the routes subpackage, so that
+// the routing documentation can be published from the route tables. For each
+// route, in matching order, the document lists:
+//   - its name and priority, if any,
+//   - the pattern strings of its components, except those matching anything
+//     ("*"), and their groups,
+//   - an example of URL matched by the route, if one can be built from the
+//     group names,
+//   - its metadata, if any, encoded in JSON.
+func GenerateDocs(w io.Writer, list *URLPatternList, opts DocsOptions) error {
+	title := opts.Title
+	if title == "" {
+		title = "Routes"
+	}
+
+	var b bytes.Buffer
+	switch opts.Format {
+	case DocsMarkdown:
+		writeMarkdownDocs(&b, title, list.Routes())
+	case DocsHTML:
+		writeHTMLDocs(&b, title, list.Routes())
+	default:
+		return fmt.Errorf("%w: %d", ErrUnknownDocsFormat, opts.Format)
+	}
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+}
+
+// routeDoc holds the documentation of a route, see GenerateDocs.
+type routeDoc struct {
+	title      string
+	priority   int
+	components []componentDoc
+	example    string
+	metadata   string
+}
+
+// componentDoc holds the documentation of a component of a route.
+type componentDoc struct {
+	name    string
+	pattern string
+	groups  []string
+}
+
+func newRouteDoc(i int, r Route) routeDoc {
+	d := routeDoc{title: r.Name, priority: r.Priority, example: r.Pattern.exampleURL()}
+	if d.title == "" {
+		d.title = "Route " + strconv.Itoa(i)
+	}
+
+	for _, name := range componentNames {
+		c := r.Pattern.component(name)
+		if c.patternString == "*" {
+			continue
+		}
+
+		// The empty pattern, which only matches empty inputs, is quoted to
+		// be visible.
+		cd := componentDoc{name: name, pattern: c.patternString}
+		if cd.pattern == "" {
+			cd.pattern = `""`
+		}
+		for _, group := range c.groupNames {
+			if group != "" {
+				cd.groups = append(cd.groups, group)
+			}
+		}
+		d.components = append(d.components, cd)
+	}
+
+	if r.Metadata != nil {
+		if metadata, err := json.Marshal(r.Metadata); err == nil {
+			d.metadata = string(metadata)
+		} else {
+			d.metadata = fmt.Sprint(r.Metadata)
+		}
+	}
+
+	return d
+}
+
+func writeMarkdownDocs(b *bytes.Buffer, title string, routes []Route) {
+	b.WriteString("# " + title + "\n")
+
+	for i, r := range routes {
+		d := newRouteDoc(i, r)
+
+		b.WriteString("\n## " + d.title + "\n\n")
+		if d.priority != 0 {
+			fmt.Fprintf(b, "Priority: %d\n\n", d.priority)
+		}
+
+		b.WriteString("| Component | Pattern | Groups |\n| --- | --- | --- |\n")
+		for _, c := range d.components {
+			groups := make([]string, len(c.groups))
+			for j, g := range c.groups {
+				groups[j] = markdownCode(g)
+			}
+
+			fmt.Fprintf(b, "| %s | %s | %s |\n", c.name, markdownCode(c.pattern), strings.Join(groups, ", "))
+		}
+
+		if d.example != "" {
+			b.WriteString("\nExample: " + markdownCode(d.example) + "\n")
+		}
+		if d.metadata != "" {
+			b.WriteString("\nMetadata: " + markdownCode(d.metadata) + "\n")
+		}
+	}
+}
+
+// markdownCode returns s as inline code usable in a table cell.
+func markdownCode(s string) string {
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+
+	s = strings.ReplaceAll(s, "|", `\|`)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+
+	return fence + s + fence
+}
+
+func writeHTMLDocs(b *bytes.Buffer, title string, routes []Route) {
+	b.WriteString("<h1>" + html.EscapeString(title) + "</h1>\n")
+
+	for i, r := range routes {
+		d := newRouteDoc(i, r)
+
+		b.WriteString("<section>\n<h2>" + html.EscapeString(d.title) + "</h2>\n")
+		if d.priority != 0 {
+			fmt.Fprintf(b, "<p>Priority: %d</p>\n", d.priority)
+		}
+
+		b.WriteString("<table>\n<thead><tr><th>Component</th><th>Pattern</th><th>Groups</th></tr></thead>\n<tbody>\n")
+		for _, c := range d.components {
+			groups := make([]string, len(c.groups))
+			for j, g := range c.groups {
+				groups[j] = "<code>" + html.EscapeString(g) + "</code>"
+			}
+
+			fmt.Fprintf(b, "<tr><td>%s</td><td><code>%s</code></td><td>%s</td></tr>\n", c.name, html.EscapeString(c.pattern), strings.Join(groups, ", "))
+		}
+		b.WriteString("</tbody>\n</table>\n")
+
+		if d.example != "" {
+			b.WriteString("<p>Example: <code>" + html.EscapeString(d.example) + "</code></p>\n")
+		}
+		if d.metadata != "" {
+			b.WriteString("<p>Metadata: <code>" + html.EscapeString(d.metadata) + "</code></p>\n")
+		}
+		b.WriteString("</section>\n")
+	}
+}
+
+// exampleValues are the values tried, after the name of the group, for the
+// groups with a custom regexp.
+var exampleValues = [...]string{"example", "1", "https", "en", "a"}
+
+// exampleURL returns a URL matched by u, built by replacing the groups by
+// their names, or an empty string if this URL doesn't match.
+func (u *URLPattern) exampleURL() string {
+	values := make(map[string]string, len(componentNames))
+	for _, name := range componentNames {
+		values[name] = u.component(name).example(exampleDefaults[name])
+	}
+
+	example := assembleURL(values)
+	if !u.Test(example, "") {
+		return ""
+	}
+
+	return example
+}
+
+// exampleDefaults holds the example values of the components matching
+// anything.
+var exampleDefaults = map[string]string{"protocol": "https", "hostname": "example.com"}
+
+// assembleURL returns the URL string made of the values of its components,
+// keyed by name.
+func assembleURL(values map[string]string) string {
+	var b strings.Builder
+	b.WriteString(values["protocol"] + ":")
+
+	if _, special := specialSchemeSet[values["protocol"]]; special || values["hostname"] != "" {
+		b.WriteString("//")
+		if values["username"] != "" || values["password"] != "" {
+			b.WriteString(values["username"])
+			if values["password"] != "" {
+				b.WriteString(":" + values["password"])
+			}
+			b.WriteByte('@')
+		}
+		b.WriteString(values["hostname"])
+		if values["port"] != "" {
+			b.WriteString(":" + values["port"])
+		}
+	}
+	b.WriteString(values["pathname"])
+	if values["search"] != "" {
+		b.WriteString("?" + values["search"])
+	}
+	if values["hash"] != "" {
+		b.WriteString("#" + values["hash"])
+	}
+
+	return b.String()
+}
+
+// example returns a value matched by c, using def for "*".
+func (c *component) example(def string) string {
+	if c.portRanges != nil {
+		return strconv.Itoa(int(c.portRanges[0].min))
+	}
+	if c.patternString == "*" {
+		return def
+	}
+
+	var b strings.Builder
+	for _, p := range c.partList {
+		b.WriteString(p.prefix)
+
+		switch p.pType {
+		case partFixedText:
+			b.WriteString(p.value)
+		case partSegmentWildcard, partFullWildcard:
+			if IsAnonymousGroupName(p.name) {
+				b.WriteString(exampleValues[0])
+			} else {
+				b.WriteString(p.name)
+			}
+		case partRegexp:
+			re, err := regexp.Compile("^(?:" + p.value + ")$")
+			if err != nil {
+				break
+			}
+
+			for _, v := range append([]string{p.name}, exampleValues[:]...) {
+				if re.MatchString(v) {
+					b.WriteString(v)
+
+					break
+				}
+			}
+		}
+
+		b.WriteString(p.suffix)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+	"github.com/dunglas/go-urlpattern/routes"
+)
+
+func TestGenerateDocs(t *testing.T) {
+	list, err := routes.Load(strings.NewReader(`
+routes:
+  - name: book
+    pattern: https://example.com/books/:id(\d+)
+    priority: 2
+    metadata:
+      owner: catalog
+  - pattern: https://:tenant.example.com/files/*
+  - name: odd
+    pattern: https://example.com/:code([A-Z]{3})|a
+`), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := urlpattern.GenerateDocs(&b, list, urlpattern.DocsOptions{Title: "API"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "# API\n" +
+		"\n## book\n\nPriority: 2\n\n" +
+		"| Component | Pattern | Groups |\n| --- | --- | --- |\n" +
+		"| protocol | `https` |  |\n| hostname | `example.com` |  |\n| port | `\"\"` |  |\n| pathname | `/books/:id(\\d+)` | `id` |\n" +
+		"\nExample: `https://example.com/books/1`\n" +
+		"\nMetadata: `{\"owner\":\"catalog\"}`\n" +
+		"\n## Route 1\n\n" +
+		"| Component | Pattern | Groups |\n| --- | --- | --- |\n" +
+		"| protocol | `https` |  |\n| hostname | `:tenant.example.com` | `tenant` |\n| port | `\"\"` |  |\n| pathname | `/files/*` | `0` |\n" +
+		"\nExample: `https://tenant.example.com/files/example`\n" +
+		"\n## odd\n\n" +
+		"| Component | Pattern | Groups |\n| --- | --- | --- |\n" +
+		"| protocol | `https` |  |\n| hostname | `example.com` |  |\n| port | `\"\"` |  |\n| pathname | `/:code([A-Z]{3})\\|a` | `code` |\n"
+	if b.String() != expected {
+		t.Errorf("unexpected Markdown:\n%s\nwant:\n%s", b.String(), expected)
+	}
+
+	b.Reset()
+	if err := urlpattern.GenerateDocs(&b, list, urlpattern.DocsOptions{Format: urlpattern.DocsHTML}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"<h1>Routes</h1>\n",
+		"<section>\n<h2>book</h2>\n<p>Priority: 2</p>\n",
+		"<tr><td>pathname</td><td><code>/books/:id(\\d+)</code></td><td><code>id</code></td></tr>\n",
+		"<p>Example: <code>https://example.com/books/1</code></p>\n",
+		"<p>Metadata: <code>{&#34;owner&#34;:&#34;catalog&#34;}</code></p>\n",
+	} {
+		if !strings.Contains(b.String(), s) {
+			t.Errorf("HTML must contain %q, got:\n%s", s, b.String())
+		}
+	}
+	if err := urlpattern.GenerateDocs(&b, list, urlpattern.DocsOptions{Format: 42}); !errors.Is(err, urlpattern.ErrUnknownDocsFormat) {
+		t.Errorf("want ErrUnknownDocsFormat; got %v", err)
+	}
+}
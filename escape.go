@@ -31,8 +31,11 @@ func init() {
 	}
 }
 
+// EscapeRegexpString escapes s so it matches literally when used in a regexp
+// group of a pattern, e.g. "(" + EscapeRegexpString(s) + ")".
+//
 // https://urlpattern.spec.whatwg.org/#escape-a-regexp-string
-func escapeRegexpString(s string) string {
+func EscapeRegexpString(s string) string {
 	// A byte loop is correct because all metacharacters are ASCII.
 	var i int
 	for i = 0; i < len(s); i++ { //nolint:intrange // i is reused below
@@ -59,8 +62,12 @@ func escapeRegexpString(s string) string {
 	return string(b[:j])
 }
 
+// EscapePatternString escapes the characters of s having a special meaning
+// in patterns (such as ":" or "{"), so that untrusted fragments can be
+// interpolated in a pattern string as fixed text.
+//
 // https://urlpattern.spec.whatwg.org/#escape-a-pattern-string
-func escapePatternString(s string) string {
+func EscapePatternString(s string) string {
 	// A byte loop is correct because all metacharacters are ASCII.
 	var i int
 	for i = 0; i < len(s); i++ { //nolint:intrange // i is reused below
@@ -0,0 +1,32 @@
+package urlpattern_test
+
+import (
+	"fmt"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func ExampleEscapePatternString() {
+	pattern := urlpattern.NewBuilder().
+		Pathname("/files/" + urlpattern.EscapePatternString("a:b{1}*") + "/:name").
+		MustBuild()
+
+	fmt.Println(pattern.Pathname())
+	fmt.Println(pattern.Exec("https://example.com/files/a:b%7B1%7D*/report", "").Pathname.Groups["name"])
+
+	// Output: /files/a\:b%7B1%7D\*/:name
+	// report
+}
+
+func ExampleEscapeRegexpString() {
+	pattern, err := urlpattern.New("/(v1|"+urlpattern.EscapeRegexpString("v2.0")+")/*", "https://example.com", nil)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(pattern.Test("https://example.com/v2.0/users", ""))
+	fmt.Println(pattern.Test("https://example.com/v2x0/users", ""))
+
+	// Output: true
+	// false
+}
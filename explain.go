@@ -0,0 +1,103 @@
+package urlpattern
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MatchReport explains the result of matching a URL against a URLPattern.
+type MatchReport struct {
+	// Matched reports whether all the components matched.
+	Matched bool
+	// Err is the error returned when parsing the URL, in which case
+	// Components is empty.
+	Err error
+	// Components holds the report of each component, in the order of the
+	// specification.
+	Components []ComponentReport
+	// SearchParamsMatched reports whether the search matched the patterns
+	// added with WithSearchParams. It is true for patterns without them.
+	SearchParamsMatched bool
+	// GroupsValid reports whether the groups satisfied the validators added
+	// with WithGroupValidator and the mappers added with WithResultMapper.
+	// They are only evaluated when all the components and the search
+	// parameters matched: GroupsValid is true otherwise.
+	GroupsValid bool
+}
+
+// ComponentReport explains the result of matching a component.
+type ComponentReport struct {
+	// Component is the name of the component, e.g. "pathname".
+	Component string
+	// Pattern is the pattern string of the component.
+	Pattern string
+	// Regexp is the regular expression of the component, empty if the
+	// component doesn't use one (see WithPortRanges).
+	Regexp string
+	// Input is the canonicalized value matched against the component.
+	Input   string
+	Matched bool
+}
+
+// Explain reports why input, relative to baseURL if not empty, matches the
+// pattern or not: the canonicalized value of each component of the URL, the
+// regular expression it is matched against, and whether it matches, then
+// whether the search parameters and the groups of the match are valid. The
+// URL is matched exactly as by Exec.
+func (u *URLPattern) Explain(input, baseURL string) MatchReport {
+	c, err := u.parseURL(input, baseURL)
+	if err != nil {
+		return MatchReport{Err: err}
+	}
+
+	report := MatchReport{GroupsValid: true}
+	result := u.matchAndReport(c.Protocol, c.Username, c.Password, c.Hostname, c.Port, c.Pathname, c.Search, c.Hash, &report)
+	report.Matched = result != nil
+
+	return report
+}
+
+// addComponents records the report of each component of u, given the values
+// matched against them and whether they matched, in the order of
+// componentNames.
+func (r *MatchReport) addComponents(u *URLPattern, values [len(componentNames)]string, matched [len(componentNames)]bool) {
+	r.Components = make([]ComponentReport, len(componentNames))
+	for i, name := range componentNames {
+		c := u.component(name)
+
+		r.Components[i] = ComponentReport{
+			Component: name,
+			Pattern:   c.patternString,
+			Input:     values[i],
+			Matched:   matched[i],
+		}
+		if c.regularExpression != nil {
+			r.Components[i].Regexp = c.regularExpression.String()
+		}
+	}
+}
+
+// String formats the report, one component per line.
+func (r MatchReport) String() string {
+	if r.Err != nil {
+		return "invalid URL: " + r.Err.Error()
+	}
+
+	var b strings.Builder
+	for _, c := range r.Components {
+		status := "ok"
+		if !c.Matched {
+			status = "FAIL"
+		}
+
+		fmt.Fprintf(&b, "%-4s %-8s input %q, pattern %q, regexp %s\n", status, c.Component, c.Input, c.Pattern, c.Regexp)
+	}
+	if !r.SearchParamsMatched {
+		b.WriteString("FAIL search parameters\n")
+	}
+	if !r.GroupsValid {
+		b.WriteString("FAIL group validators or mappers\n")
+	}
+
+	return b.String()
+}
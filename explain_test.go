@@ -0,0 +1,74 @@
+package urlpattern_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func ExampleURLPattern_Explain() {
+	pattern, err := urlpattern.Compile("https://example.com/books/:id")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(pattern.Explain("https://EXAMPLE.com/Books/42", ""))
+	// Output:
+	// ok   protocol input "https", pattern "https", regexp \A(?:https)\z
+	// ok   username input "", pattern "*", regexp \A(?:(?P<0>.*))\z
+	// ok   password input "", pattern "*", regexp \A(?:(?P<0>.*))\z
+	// ok   hostname input "example.com", pattern "example.com", regexp \A(?:example\.com)\z
+	// ok   port     input "", pattern "", regexp \A(?:)\z
+	// FAIL pathname input "/Books/42", pattern "/books/:id", regexp \A(?:\/books(?:\/(?P<id>[^\/]+?)))\z
+	// ok   search   input "", pattern "*", regexp \A(?:(?P<0>.*))\z
+	// ok   hash     input "", pattern "*", regexp \A(?:(?P<0>.*))\z
+}
+
+func TestExplain(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://example.com/books/:id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r := pattern.Explain("https://example.com/books/42", ""); !r.Matched || len(r.Components) != 8 {
+		t.Errorf("unexpected report %#v", r)
+	}
+
+	if r := pattern.Explain("/books/42", "https://example.com/"); !r.Matched {
+		t.Errorf("unexpected report %#v", r)
+	}
+
+	if r := pattern.Explain("https://exa mple.com", ""); r.Matched || r.Err == nil || r.Components != nil {
+		t.Errorf("unexpected report %#v", r)
+	}
+
+	params, err := urlpattern.NewSearchParamsPattern(map[string]string{"page": `(\\d+)`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Explain must agree with Exec whatever the options.
+	for _, tt := range []struct {
+		pattern string
+		option  urlpattern.Option
+		input   string
+	}{
+		{"https://example.com/books/:id", urlpattern.WithGroupValidator("id", func(id string) bool { return id != "2" }), "https://example.com/books/2"},
+		{"https://example.com/books/:id", urlpattern.WithGroupValidator("id", func(id string) bool { return id != "2" }), "https://example.com/books/1"},
+		{"https://127.0.0.1/*", urlpattern.WithIPHostnames(), "https://[::ffff:127.0.0.1]/"},
+		{"https://example.com/books", urlpattern.WithSearchParams(params), "https://example.com/books?page=a"},
+		{"https://example.com/books", urlpattern.WithSearchParams(params), "https://example.com/books?page=1"},
+		{"https://example.com/books/:id", urlpattern.WithResultMapper("id", urlpattern.MapAtoi), "https://example.com/books/a"},
+	} {
+		pattern, err := urlpattern.Compile(tt.pattern, tt.option)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := pattern.Explain(tt.input, "")
+		if r.Matched != pattern.Test(tt.input, "") {
+			t.Errorf("%s: Explain and Exec disagree for %s:\n%s", tt.pattern, tt.input, r)
+		}
+	}
+}
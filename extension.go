@@ -0,0 +1,106 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidExtensionMatchPattern is returned by FromExtensionMatchPattern for
+// invalid match patterns.
+var ErrInvalidExtensionMatchPattern = errors.New("invalid extension match pattern")
+
+// extensionSchemes lists the schemes supported by match patterns.
+var extensionSchemes = []string{"http", "https", "ws", "wss", "ftp", "data", "file"}
+
+// FromExtensionMatchPattern converts a match pattern of browser extensions,
+// such as "*://*.mozilla.org/*", to an equivalent URLPattern, for tooling
+// validating or simulating extension manifests.
+//
+// "<all_urls>" matches all the URLs whose scheme is supported by extensions,
+// "*" as scheme matches http, https, ws and wss, and "*.example.com" as host
+// matches example.com and all its subdomains. The port, if any, is matched
+// only if the host contains one.
+//
+// Match patterns are matched against the path followed by the query string:
+// a "?" in the path starts the search pattern, a final "*" matches any query
+// string, and otherwise URLs must have no query string.
+func FromExtensionMatchPattern(pattern string, opts ...Option) (*URLPattern, error) {
+	if pattern == "<all_urls>" {
+		protocol := "(" + strings.Join(extensionSchemes, "|") + ")"
+
+		return Compile(&URLPatternInit{Protocol: &protocol}, opts...)
+	}
+
+	scheme, rest, ok := strings.Cut(pattern, "://")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing scheme in %q", ErrInvalidExtensionMatchPattern, pattern)
+	}
+
+	var protocol string
+	switch {
+	case scheme == "*":
+		protocol = "(http|https|ws|wss)"
+	case slices.Contains(extensionSchemes, scheme):
+		protocol = scheme
+	default:
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrInvalidExtensionMatchPattern, scheme)
+	}
+
+	i := strings.IndexByte(rest, '/')
+	if i == -1 {
+		return nil, fmt.Errorf("%w: missing path in %q", ErrInvalidExtensionMatchPattern, pattern)
+	}
+	host, path := rest[:i], rest[i:]
+
+	port := "*"
+	if j := strings.LastIndexByte(host, ':'); j != -1 && !strings.HasSuffix(host, "]") {
+		host, port = host[:j], host[j+1:]
+	}
+
+	var hostname string
+	switch {
+	case host == "" && scheme == "file":
+	case host == "":
+		return nil, fmt.Errorf("%w: missing host in %q", ErrInvalidExtensionMatchPattern, pattern)
+	case host == "*":
+		hostname = "*"
+	case strings.HasPrefix(host, "*."):
+		hostname = "{*.}?" + EscapePatternString(host[len("*."):])
+	case strings.Contains(host, "*"):
+		return nil, fmt.Errorf("%w: invalid host %q", ErrInvalidExtensionMatchPattern, host)
+	default:
+		hostname = EscapePatternString(host)
+	}
+
+	var search string
+	if path, search, ok = strings.Cut(path, "?"); ok {
+		search = extensionWildcards(search)
+	} else if strings.HasSuffix(path, "*") {
+		search = "*"
+	}
+	pathname := extensionWildcards(path)
+
+	return Compile(&URLPatternInit{
+		Protocol: &protocol,
+		Hostname: &hostname,
+		Port:     &port,
+		Pathname: &pathname,
+		Search:   &search,
+	}, opts...)
+}
+
+// extensionWildcards converts s, fixed text in which "*" matches any
+// characters, to a pattern.
+func extensionWildcards(s string) string {
+	var b strings.Builder
+	for i, text := range strings.Split(s, "*") {
+		if i > 0 {
+			b.WriteByte('*')
+		}
+		b.WriteString(EscapePatternString(text))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestFromExtensionMatchPattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{"<all_urls>", []string{"https://example.com/", "file:///etc/hosts", "ws://example.com:8080/"}, []string{"chrome://extensions/", "mailto:a@example.com"}},
+		{"*://*.mozilla.org/*", []string{"https://mozilla.org/", "http://a.b.mozilla.org/x?y=z", "wss://developer.mozilla.org/"}, []string{"ftp://mozilla.org/", "https://mozilla.com/", "https://notmozilla.org/"}},
+		{"https://example.com/api/*", []string{"https://example.com/api/", "https://example.com/api/users?page=2"}, []string{"https://example.com/apis", "http://example.com/api/"}},
+		{"https://example.com/", []string{"https://example.com/", "https://example.com:8443/"}, []string{"https://example.com/?a", "https://example.com/a"}},
+		{"https://example.com:8443/*", []string{"https://example.com:8443/a"}, []string{"https://example.com/a"}},
+		{"https://example.com/*?q=*", []string{"https://example.com/search?q=go"}, []string{"https://example.com/search?p=1"}},
+		{"file:///home/*", []string{"file:///home/user/a.txt"}, []string{"file:///etc/hosts"}},
+	} {
+		t.Run(tc.pattern, func(t *testing.T) {
+			u, err := urlpattern.FromExtensionMatchPattern(tc.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, input := range tc.match {
+				if !u.Test(input, "") {
+					t.Errorf("want %q to match", input)
+				}
+			}
+			for _, input := range tc.noMatch {
+				if u.Test(input, "") {
+					t.Errorf("want %q not to match", input)
+				}
+			}
+		})
+	}
+
+	for _, p := range []string{"example.com/*", "chrome://extensions/", "https://example.com", "https:///*", "https://a*.example.com/*"} {
+		if _, err := urlpattern.FromExtensionMatchPattern(p); !errors.Is(err, urlpattern.ErrInvalidExtensionMatchPattern) {
+			t.Errorf("%s: want ErrInvalidExtensionMatchPattern; got %v", p, err)
+		}
+	}
+}
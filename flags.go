@@ -0,0 +1,66 @@
+package urlpattern
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// UnknownOptionError is returned when a pattern flag (see WithFlags) or a key
+// of a URLPatternOptions dictionary (see Options.UnmarshalJSON) isn't
+// supported.
+type UnknownOptionError struct {
+	// Option is the unknown flag or key.
+	Option string
+}
+
+func (e *UnknownOptionError) Error() string {
+	return fmt.Sprintf("unknown option %q", e.Option)
+}
+
+// WithFlags configures the pattern from a string of single-letter flags, in
+// the manner of the flags of a JavaScript regular expression. The only
+// supported flag is "i", equivalent to WithIgnoreCase.
+//
+// Compile returns an *UnknownOptionError wrapped in ErrTypeError if flags
+// contains another character.
+func WithFlags(flags string) Option {
+	return func(c *config) {
+		for _, f := range flags {
+			switch f {
+			case 'i':
+				c.ignoreCase = true
+			default:
+				c.setErr(&UnknownOptionError{Option: string(f)})
+			}
+		}
+	}
+}
+
+// UnmarshalJSON decodes a URLPatternOptions dictionary such as
+// {"ignoreCase": true}, allowing options to be stored in configuration files
+// with the same syntax as in browsers. Unknown keys are rejected with an
+// *UnknownOptionError.
+//
+// https://urlpattern.spec.whatwg.org/#dictdef-urlpatternoptions
+func (o *Options) UnmarshalJSON(data []byte) error {
+	var dict map[string]json.RawMessage
+	if err := json.Unmarshal(data, &dict); err != nil {
+		return err
+	}
+
+	var options Options
+	for key, value := range dict {
+		switch key {
+		case "ignoreCase":
+			if err := json.Unmarshal(value, &options.IgnoreCase); err != nil {
+				return fmt.Errorf("ignoreCase: %w", err)
+			}
+		default:
+			return &UnknownOptionError{Option: key}
+		}
+	}
+
+	*o = options
+
+	return nil
+}
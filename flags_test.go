@@ -0,0 +1,50 @@
+package urlpattern_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestWithFlags(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://example.com/books/:id", urlpattern.WithFlags("i"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pattern.Test("https://example.com/BOOKS/1", "") {
+		t.Error("want match")
+	}
+
+	_, err = urlpattern.Compile("https://example.com/books/:id", urlpattern.WithFlags("iv"))
+
+	var unknownErr *urlpattern.UnknownOptionError
+	if !errors.As(err, &unknownErr) || unknownErr.Option != "v" || !errors.Is(err, urlpattern.ErrTypeError) {
+		t.Errorf("want UnknownOptionError; got %v", err)
+	}
+}
+
+func TestOptionsUnmarshalJSON(t *testing.T) {
+	var options urlpattern.Options
+	if err := json.Unmarshal([]byte(`{"ignoreCase": true}`), &options); err != nil {
+		t.Fatal(err)
+	}
+
+	pattern, err := urlpattern.New("https://example.com/books/:id", "", &options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pattern.Test("https://example.com/BOOKS/1", "") {
+		t.Error("want match")
+	}
+
+	var unknownErr *urlpattern.UnknownOptionError
+	if err := json.Unmarshal([]byte(`{"ignorecase": true}`), &options); !errors.As(err, &unknownErr) || unknownErr.Option != "ignorecase" {
+		t.Errorf("want UnknownOptionError; got %v", err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"ignoreCase": "yes"}`), &options); err == nil {
+		t.Error("want error")
+	}
+}
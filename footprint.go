@@ -0,0 +1,146 @@
+package urlpattern
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"unsafe"
+)
+
+// Approximate sizes used to estimate memory footprints.
+const (
+	stringHeaderSize = int(unsafe.Sizeof(""))
+	mapEntrySize     = 48
+	mapHeaderSize    = 48
+)
+
+// MemoryFootprint returns an approximation of the number of bytes used by the
+// compiled pattern, including its regular expressions, for capacity
+// planning. The wildcard components shared between patterns (see
+// ListStats) are not counted, but the regular expressions shared with other
+// patterns are.
+func (u *URLPattern) MemoryFootprint() int {
+	size := int(unsafe.Sizeof(*u))
+	for _, name := range componentNames {
+		if c := u.component(name); !c.shared {
+			size += c.memoryFootprint()
+		}
+	}
+
+	return size
+}
+
+func (c *component) memoryFootprint() int {
+	size := int(unsafe.Sizeof(*c)) + len(c.patternString)
+
+	if c.regularExpression != nil {
+		size += regexpFootprint(c.regularExpression)
+	}
+
+	for _, n := range c.groupNames {
+		size += stringHeaderSize + len(n)
+	}
+
+	size += mapHeaderSize + len(c.groupIndex)*mapEntrySize
+
+	for _, p := range c.partList {
+		size += int(unsafe.Sizeof(p)) + len(p.value) + len(p.name) + len(p.prefix) + len(p.suffix)
+	}
+
+	size += len(c.portRanges) * int(unsafe.Sizeof(portRange{}))
+
+	return size
+}
+
+// regexpFootprint estimates the size of re from the size of its program.
+func regexpFootprint(re *regexp.Regexp) int {
+	expr := re.String()
+	size := int(unsafe.Sizeof(*re)) + len(expr)
+
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return size
+	}
+
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return size
+	}
+
+	for _, inst := range prog.Inst {
+		size += int(unsafe.Sizeof(inst)) + len(inst.Rune)*int(unsafe.Sizeof(rune(0)))
+	}
+
+	return size
+}
+
+// ListStats summarizes a URLPatternList for capacity planning.
+type ListStats struct {
+	// Patterns is the number of patterns in the list.
+	Patterns int
+	// FallbackPatterns is the number of patterns not indexed by the
+	// pathname prefix trie, and evaluated for every URL.
+	FallbackPatterns int
+	// SharedComponents is the number of distinct wildcard components shared
+	// between the patterns.
+	SharedComponents int
+	// MemoryFootprint is an approximation of the number of bytes used by the
+	// compiled patterns, the shared components and the trie.
+	MemoryFootprint int
+}
+
+// Stats returns statistics about the list.
+func (l *URLPatternList) Stats() ListStats {
+	stats := ListStats{
+		Patterns:         len(l.routes),
+		FallbackPatterns: len(l.root.prefix),
+		MemoryFootprint:  int(unsafe.Sizeof(*l)) + cap(l.routes)*int(unsafe.Sizeof(Route{})),
+	}
+
+	shared := make(map[*component]struct{})
+	regexps := make(map[*regexp.Regexp]struct{})
+	for _, r := range l.routes {
+		stats.MemoryFootprint += r.Pattern.MemoryFootprint()
+
+		for _, name := range componentNames {
+			c := r.Pattern.component(name)
+			if c.shared {
+				shared[c] = struct{}{}
+
+				continue
+			}
+
+			// Identical regular expressions are shared between
+			// components, see compileRegexp.
+			if re := c.regularExpression; re != nil {
+				if _, ok := regexps[re]; ok {
+					stats.MemoryFootprint -= regexpFootprint(re)
+				}
+				regexps[re] = struct{}{}
+			}
+		}
+	}
+
+	for c := range shared {
+		stats.MemoryFootprint += c.memoryFootprint()
+	}
+	stats.SharedComponents = len(shared)
+
+	stats.MemoryFootprint += l.root.memoryFootprint()
+
+	return stats
+}
+
+func (n *trieNode) memoryFootprint() int {
+	entrySize := int(unsafe.Sizeof(patternEntry{}))
+	size := int(unsafe.Sizeof(*n)) + (cap(n.exact)+cap(n.prefix))*entrySize
+
+	if n.children != nil {
+		size += mapHeaderSize
+	}
+
+	for label, child := range n.children {
+		size += mapEntrySize + len(label) + child.memoryFootprint()
+	}
+
+	return size
+}
@@ -0,0 +1,82 @@
+package urlpattern_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestMemoryFootprint(t *testing.T) {
+	simple, err := urlpattern.Compile("https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	named, err := urlpattern.Compile("https://:tenant.example.com/books/:id(\\d+)/:slug{-:version}?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s, c := simple.MemoryFootprint(), named.MemoryFootprint(); s <= 0 || c <= s {
+		t.Errorf("unexpected footprints %d and %d", s, c)
+	}
+}
+
+func TestListStats(t *testing.T) {
+	var list urlpattern.URLPatternList
+	for i := range 100 {
+		pattern, err := urlpattern.Compile(fmt.Sprintf("https://tenant%d.example.com/books/:id", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		list.Add(pattern)
+	}
+
+	fallback, err := urlpattern.Compile("https://example.com/*", urlpattern.WithIgnoreCase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Add(fallback)
+
+	stats := list.Stats()
+	if stats.Patterns != 101 || stats.FallbackPatterns != 1 {
+		t.Errorf("unexpected stats %#v", stats)
+	}
+
+	// The wildcard compiled with the default options (username, password,
+	// search and hash), and with ignoreCase (search and hash).
+	if stats.SharedComponents != 2 {
+		t.Errorf("unexpected number of shared components %d", stats.SharedComponents)
+	}
+
+	// The regular expressions of the pathnames, protocols and ports are
+	// shared, only the hostnames differ.
+	if single := list.Patterns()[0].MemoryFootprint(); stats.MemoryFootprint < 10*single || stats.MemoryFootprint > 100*single {
+		t.Errorf("unexpected footprint %d", stats.MemoryFootprint)
+	}
+}
+
+func TestSharedRegexps(t *testing.T) {
+	var list urlpattern.URLPatternList
+	for _, host := range []string{"example.com", "example.org"} {
+		pattern, err := urlpattern.Compile("https://" + host + "/books/:id")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		list.Add(pattern)
+	}
+
+	patterns := list.Patterns()
+	if stats := list.Stats(); stats.MemoryFootprint >= patterns[0].MemoryFootprint()+patterns[1].MemoryFootprint() {
+		t.Errorf("the regular expressions of the pathnames must be shared, got footprint %d", stats.MemoryFootprint)
+	}
+
+	for _, p := range patterns {
+		if !p.Test("https://"+p.Hostname()+"/books/1", "") {
+			t.Errorf("%s must match", p.Hostname())
+		}
+	}
+}
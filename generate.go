@@ -0,0 +1,274 @@
+package urlpattern
+
+import (
+	"math/rand/v2"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// generateAttempts is the number of URLs tried by GenerateMatching and
+// GenerateNonMatching before giving up.
+const generateAttempts = 100
+
+// GenerateMatching returns a random URL matched by pattern, for property-based
+// tests of code built on top of patterns:
+//
+//	rng := rand.New(rand.NewPCG(1, 2))
+//	for range 1000 {
+//		input := urlpattern.GenerateMatching(pattern, rng)
+//		// check the behavior of the router for input
+//	}
+//
+// The groups are replaced by random values: ASCII letters and digits for
+// named groups and wildcards, and strings generated from the regular
+// expression for custom regexps. Optional and repeated groups are included a
+// random number of times. For components ignoring case, the case of the
+// fixed text is randomized. The URLs are reproducible for a given seed of rng.
+//
+// It returns an empty string if no matching URL could be generated, for
+// instance because of a regexp that canonicalized URLs can't match.
+func GenerateMatching(pattern *URLPattern, rng *rand.Rand) string {
+	for range generateAttempts {
+		if input := assembleURL(pattern.generateValues(rng)); pattern.Test(input, "") {
+			return input
+		}
+	}
+
+	return ""
+}
+
+// GenerateNonMatching returns a random URL not matched by pattern, see
+// GenerateMatching. The URLs are near misses: a matching URL in which the
+// value of a random component not matching anything ("*") is altered.
+//
+// It returns an empty string if no such URL could be generated, for instance
+// for patterns matching any URL.
+func GenerateNonMatching(pattern *URLPattern, rng *rand.Rand) string {
+	var names []string
+	for _, name := range componentNames {
+		if pattern.component(name).patternString != "*" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+
+	for range generateAttempts {
+		values := pattern.generateValues(rng)
+
+		name := names[rng.IntN(len(names))]
+		values[name] = mutateValue(rng, values[name])
+
+		if input := assembleURL(values); !pattern.Test(input, "") {
+			return input
+		}
+	}
+
+	return ""
+}
+
+// generateValues returns random values matched by the components of u, keyed
+// by name.
+func (u *URLPattern) generateValues(rng *rand.Rand) map[string]string {
+	values := make(map[string]string, len(componentNames))
+	for _, name := range componentNames {
+		c := u.component(name)
+		if c.patternString != "*" {
+			values[name] = c.generate(rng)
+
+			continue
+		}
+
+		switch name {
+		case "protocol":
+			values[name] = [...]string{"http", "https"}[rng.IntN(2)]
+		case "hostname":
+			values[name] = randomString(rng, 1, 8) + ".example"
+		case "pathname":
+			values[name] = "/" + randomSegments(rng, '/')
+		case "search", "hash":
+			values[name] = randomString(rng, 0, 8)
+		}
+	}
+
+	return values
+}
+
+// generate returns a random value matched by c.
+func (c *component) generate(rng *rand.Rand) string {
+	if c.portRanges != nil {
+		r := c.portRanges[rng.IntN(len(c.portRanges))]
+
+		return strconv.Itoa(int(r.min) + rng.IntN(int(r.max)-int(r.min)+1))
+	}
+
+	var b strings.Builder
+	for _, p := range c.partList {
+		n := 1
+		switch p.modifier {
+		case partModifierOptional:
+			n = rng.IntN(2)
+		case partModifierZeroOrMore:
+			n = rng.IntN(4)
+		case partModifierOneOrMore:
+			n = 1 + rng.IntN(3)
+		}
+
+		for range n {
+			b.WriteString(p.prefix)
+
+			switch p.pType {
+			case partFixedText:
+				if c.options.ignoreCase {
+					b.WriteString(randomCase(rng, p.value))
+				} else {
+					b.WriteString(p.value)
+				}
+			case partSegmentWildcard:
+				b.WriteString(randomString(rng, 1, 8))
+			case partFullWildcard:
+				b.WriteString(randomSegments(rng, c.options.delimiterCodePoint))
+			case partRegexp:
+				if re, err := syntax.Parse(p.value, syntax.Perl); err == nil {
+					generateRegexp(rng, &b, re.Simplify())
+				}
+			}
+
+			b.WriteString(p.suffix)
+		}
+	}
+
+	return b.String()
+}
+
+// generatedCodePoints are the code points used in random values, which are
+// never changed by the canonicalization of URLs.
+const generatedCodePoints = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// randomString returns a random string of generatedCodePoints, of length
+// between minLen and maxLen.
+func randomString(rng *rand.Rand, minLen, maxLen int) string {
+	b := make([]byte, minLen+rng.IntN(maxLen-minLen+1))
+	for i := range b {
+		b[i] = generatedCodePoints[rng.IntN(len(generatedCodePoints))]
+	}
+
+	return string(b)
+}
+
+// randomSegments returns one to three random strings separated by delimiter,
+// or a single one if delimiter is 0.
+func randomSegments(rng *rand.Rand, delimiter byte) string {
+	if delimiter == 0 {
+		return randomString(rng, 1, 8)
+	}
+
+	segments := make([]string, 1+rng.IntN(3))
+	for i := range segments {
+		segments[i] = randomString(rng, 1, 8)
+	}
+
+	return strings.Join(segments, string(delimiter))
+}
+
+// randomCase changes the case of the ASCII letters of s randomly.
+func randomCase(rng *rand.Rand, s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'a' <= c && c <= 'z' && rng.IntN(2) == 0 {
+			b[i] = c - 'a' + 'A'
+		} else if 'A' <= c && c <= 'Z' && rng.IntN(2) == 0 {
+			b[i] = c - 'A' + 'a'
+		}
+	}
+
+	return string(b)
+}
+
+// mutateValue returns a random alteration of s: a code point inserted,
+// deleted or replaced.
+func mutateValue(rng *rand.Rand, s string) string {
+	i := rng.IntN(len(s) + 1)
+	c := randomString(rng, 1, 1)
+
+	switch {
+	case s == "" || rng.IntN(3) == 0:
+		return s[:i] + c + s[i:]
+	case i == len(s):
+		return s[:i-1]
+	case rng.IntN(2) == 0:
+		return s[:i] + s[i+1:]
+	default:
+		return s[:i] + c + s[i+1:]
+	}
+}
+
+// generateRegexp writes a random string matched by re, a simplified regexp.
+func generateRegexp(rng *rand.Rand, b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && rng.IntN(2) == 0 {
+				r = unicode.SimpleFold(r)
+			}
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(randomRuneInClass(rng, re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(generatedCodePoints[rng.IntN(len(generatedCodePoints))])
+	case syntax.OpCapture:
+		generateRegexp(rng, b, re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lower, upper := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			lower, upper = 0, 3
+		case syntax.OpPlus:
+			lower, upper = 1, 3
+		case syntax.OpQuest:
+			lower, upper = 0, 1
+		}
+		if upper < 0 {
+			upper = lower + 3
+		}
+
+		for range lower + rng.IntN(upper-lower+1) {
+			generateRegexp(rng, b, re.Sub[0])
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			generateRegexp(rng, b, sub)
+		}
+	case syntax.OpAlternate:
+		generateRegexp(rng, b, re.Sub[rng.IntN(len(re.Sub))])
+	}
+}
+
+// randomRuneInClass returns a random code point of the character class
+// ranges, preferring generatedCodePoints.
+func randomRuneInClass(rng *rand.Rand, ranges []rune) rune {
+	var candidates []rune
+	for _, c := range generatedCodePoints + "ABCDEFGHIJKLMNOPQRSTUVWXYZ-._~" {
+		for i := 0; i < len(ranges); i += 2 {
+			if ranges[i] <= c && c <= ranges[i+1] {
+				candidates = append(candidates, c)
+
+				break
+			}
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[rng.IntN(len(candidates))]
+	}
+	if len(ranges) == 0 {
+		return 'a'
+	}
+
+	i := 2 * rng.IntN(len(ranges)/2)
+
+	return ranges[i] + rng.Int32N(ranges[i+1]-ranges[i]+1)
+}
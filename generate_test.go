@@ -0,0 +1,65 @@
+package urlpattern_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestGenerate(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for _, p := range []string{
+		"https://example.com/books/:id",
+		"http{s}?://:subdomain.example.com/files/*",
+		"https://example.com/books/:id(\\d+)/:slug([a-z]+(?:-[a-z]+)*){/:format(json|xml)}?",
+		"https://example.com/:lang(en|fr)/docs/:path+",
+		"https://example.com/search\\?q=:query&page=:page(\\d{1,3})",
+		"https://example.com/api/v:version(\\d+)/*#:section?",
+		"data::type,:payload",
+		"https://*.example.com:8080/",
+	} {
+		u, err := urlpattern.Compile(p)
+		if err != nil {
+			t.Fatalf("%s: %v", p, err)
+		}
+
+		for range 100 {
+			input := urlpattern.GenerateMatching(u, rng)
+			if input == "" || !u.Test(input, "") {
+				t.Fatalf("%s must match %q", p, input)
+			}
+
+			input = urlpattern.GenerateNonMatching(u, rng)
+			if input == "" || u.Test(input, "") {
+				t.Fatalf("%s must not match %q", p, input)
+			}
+		}
+	}
+
+	u, err := urlpattern.Compile("https://example.com/Books/:id", urlpattern.WithIgnoreCase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for range 100 {
+		seen[urlpattern.GenerateMatching(u, rng)[len("https://example.com/"):][:5]] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("the case of fixed text must be randomized, got %v", seen)
+	}
+
+	u, err = urlpattern.Compile("*://*:*/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input := urlpattern.GenerateNonMatching(u, rng); input != "" {
+		t.Errorf("patterns matching anything have no non-matching URLs, got %q", input)
+	}
+
+	first := urlpattern.GenerateMatching(u, rand.New(rand.NewPCG(3, 4)))
+	if second := urlpattern.GenerateMatching(u, rand.New(rand.NewPCG(3, 4))); first != second {
+		t.Errorf("URLs must be reproducible, got %q and %q", first, second)
+	}
+}
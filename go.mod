@@ -3,12 +3,11 @@ module github.com/dunglas/go-urlpattern
 go 1.25.0
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/nlnwa/whatwg-url v0.6.2
-	golang.org/x/exp v0.0.0-20260410095643-746e56fc9e2f
+	golang.org/x/net v0.53.0
+	golang.org/x/text v0.36.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-require (
-	github.com/bits-and-blooms/bitset v1.24.4 // indirect
-	golang.org/x/net v0.53.0 // indirect
-	golang.org/x/text v0.36.0 // indirect
-)
+require github.com/bits-and-blooms/bitset v1.24.4 // indirect
@@ -0,0 +1,88 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	// ErrInvalidGroupValidator is returned by Compile when a validator passed
+	// to WithGroupValidator is nil or has an invalid group name.
+	ErrInvalidGroupValidator = errors.New("invalid group validator")
+	// ErrUnknownValidatedGroup is returned by Compile when no component of
+	// the pattern has the group of a validator passed to WithGroupValidator.
+	ErrUnknownValidatedGroup = errors.New("validated group not found in pattern")
+)
+
+// WithGroupValidator adds a predicate on the value of the groups named name,
+// evaluated by Exec and the other matching methods after the regular
+// expressions of the components matched: the URL doesn't match if valid
+// returns false. This allows checking numeric ranges or enumerations without
+// a second pass:
+//
+//	pattern, err := urlpattern.Compile("https://example.com/books/:id(\\d+)", urlpattern.WithGroupValidator("id", func(id string) bool {
+//		n, err := strconv.Atoi(id)
+//
+//		return err == nil && n > 0 && n <= 10000
+//	}))
+//
+// valid is called for each component having a group named name, including
+// anonymous groups such as "0", but not for optional groups which didn't
+// participate in the match. Several validators can be added for the same
+// group, they are evaluated in order.
+//
+// Compile returns ErrInvalidGroupValidator if valid is nil or name isn't a
+// valid group name, and ErrUnknownValidatedGroup if the pattern has no group
+// named name.
+func WithGroupValidator(name string, valid func(value string) bool) Option {
+	return func(c *config) {
+		if valid == nil || (!isValidName(name) && !IsAnonymousGroupName(name)) {
+			c.setErr(fmt.Errorf("%w: %q", ErrInvalidGroupValidator, name))
+
+			return
+		}
+
+		c.groupValidators = append(c.groupValidators, groupValidator{name: name, valid: valid})
+	}
+}
+
+// groupValidator is a predicate added with WithGroupValidator.
+type groupValidator struct {
+	name  string
+	valid func(value string) bool
+}
+
+// checkGroupValidators returns ErrUnknownValidatedGroup if a validator of u
+// applies to no group.
+func (u *URLPattern) checkGroupValidators() error {
+	for _, v := range u.config.groupValidators {
+		if !slices.ContainsFunc(componentNames[:], func(name string) bool {
+			return slices.Contains(u.component(name).groupNames, v.name)
+		}) {
+			return fmt.Errorf("%w: %q", ErrUnknownValidatedGroup, v.name)
+		}
+	}
+
+	return nil
+}
+
+// validGroups reports whether the groups of r satisfy the validators of u.
+func (u *URLPattern) validGroups(r *URLPatternResult) bool {
+	for _, v := range u.config.groupValidators {
+		for _, name := range componentNames {
+			// Groups is nil when the component matched the empty string, so
+			// the groups are looked up by name.
+			c := r.component(name)
+			if !c.Has(v.name) {
+				continue
+			}
+
+			if !v.valid(c.Groups[v.name]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,78 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestWithGroupValidator(t *testing.T) {
+	u, err := urlpattern.Compile("https://:lang.example.com/books/:id(\\d+){/:format}?",
+		urlpattern.WithGroupValidator("id", func(id string) bool {
+			n, err := strconv.Atoi(id)
+
+			return err == nil && n > 0 && n <= 10000
+		}),
+		urlpattern.WithGroupValidator("lang", func(lang string) bool {
+			return slices.Contains([]string{"en", "fr"}, lang)
+		}),
+		urlpattern.WithGroupValidator("format", func(format string) bool {
+			return format == "json"
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for input, expected := range map[string]bool{
+		"https://en.example.com/books/42":       true,
+		"https://fr.example.com/books/10000":    true,
+		"https://en.example.com/books/42/json":  true,
+		"https://en.example.com/books/0":        false,
+		"https://en.example.com/books/10001":    false,
+		"https://de.example.com/books/42":       false,
+		"https://en.example.com/books/42/xml":   false,
+		"https://en.example.com/authors/42":     false,
+		"https://en.example.com/books/99999999": false,
+	} {
+		if u.Test(input, "") != expected {
+			t.Errorf("%q: expected %v", input, expected)
+		}
+	}
+
+	if r := u.ExecPath("/books/0"); r != nil {
+		t.Error("validators must apply to ExecPath")
+	}
+
+	for _, opt := range []urlpattern.Option{
+		urlpattern.WithGroupValidator("id", nil),
+		urlpattern.WithGroupValidator("", func(string) bool { return true }),
+		urlpattern.WithGroupValidator("a-b", func(string) bool { return true }),
+	} {
+		if _, err := urlpattern.Compile("https://example.com/books/:id", opt); !errors.Is(err, urlpattern.ErrInvalidGroupValidator) {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+
+	if _, err := urlpattern.Compile("https://example.com/books/:id", urlpattern.WithGroupValidator("slug", func(string) bool { return true })); !errors.Is(err, urlpattern.ErrUnknownValidatedGroup) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if _, err := urlpattern.Compile("https://example.com/books/*", urlpattern.WithGroupValidator("0", func(string) bool { return true })); err != nil {
+		t.Errorf("anonymous groups must be validated, got %v", err)
+	}
+
+	// Components matching the empty string have required groups too.
+	for pattern, group := range map[string]string{"https://example.com/#:h(.*)": "h", "https://example.com/?:q(.*)": "q"} {
+		u, err := urlpattern.Compile(pattern, urlpattern.WithGroupValidator(group, func(string) bool { return false }))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Test("https://example.com/", "") {
+			t.Errorf("%s: validators must apply to groups matching the empty string", pattern)
+		}
+	}
+}
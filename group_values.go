@@ -0,0 +1,44 @@
+package urlpattern
+
+import "strings"
+
+// GroupValues returns the values of the group named name of the first
+// component of r having it, in the order of the specification, see
+// URLPatternComponentResult.GroupValues.
+func (r *URLPatternResult) GroupValues(name string) []string {
+	for _, c := range componentNames {
+		if values := r.component(c).GroupValues(name); values != nil {
+			return values
+		}
+	}
+
+	return nil
+}
+
+// GroupValues returns the repetitions captured by the group named name, for
+// groups with the "+" and "*" modifiers whose value is the whole repeated run:
+//
+//	// Pattern: "/files/:path+"
+//	result.Pathname.Groups["path"]      // "a/b/c" for "/files/a/b/c"
+//	result.Pathname.GroupValues("path") // []string{"a", "b", "c"}
+//
+// The value is split on the suffix of the part followed by its prefix, the
+// text separating the repetitions. Repetitions matched by custom regexps
+// containing this separator are split too.
+//
+// Other groups, and repeated groups without prefix nor suffix, have a single
+// value. It returns nil for unknown groups and for groups which didn't
+// participate in the match (see Has).
+func (r *URLPatternComponentResult) GroupValues(name string) []string {
+	if !r.Has(name) {
+		return nil
+	}
+
+	value := r.Groups[name]
+	separator, ok := r.repeatSeparators[name]
+	if !ok {
+		return []string{value}
+	}
+
+	return strings.Split(value, separator)
+}
@@ -0,0 +1,55 @@
+package urlpattern_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestGroupValues(t *testing.T) {
+	for _, tt := range []struct {
+		pattern  string
+		input    string
+		expected []string
+	}{
+		{"/files/:path+", "/files/a", []string{"a"}},
+		{"/files/:path+", "/files/a/b/c", []string{"a", "b", "c"}},
+		{"/files/:path*", "/files/a/b", []string{"a", "b"}},
+		{"/files/:path*", "/files", nil},
+		{"/files/:path?", "/files", nil},
+		{"/files/:path", "/files/a", []string{"a"}},
+		{"/files{/:path.txt}+", "/files/a.txt/b.txt", []string{"a", "b"}},
+		{"/files/:path(\\d+)+", "/files/1/22", []string{"1", "22"}},
+		{"/files-:path(\\d)+", "/files-123", []string{"123"}},
+		{"/files/:path(.*)+", "/files/a/b/c", []string{"a", "b", "c"}},
+	} {
+		u, err := urlpattern.New(tt.pattern, "https://example.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := u.Exec(tt.input, "https://example.com")
+		if r == nil {
+			t.Fatalf("%s: %s doesn't match", tt.pattern, tt.input)
+		}
+
+		if values := r.Pathname.GroupValues("path"); !slices.Equal(values, tt.expected) {
+			t.Errorf("%s: GroupValues() = %q for %s; want %q", tt.pattern, values, tt.input, tt.expected)
+		}
+		if values := r.GroupValues("path"); !slices.Equal(values, tt.expected) {
+			t.Errorf("%s: URLPatternResult.GroupValues() = %q for %s; want %q", tt.pattern, values, tt.input, tt.expected)
+		}
+	}
+
+	u, err := urlpattern.New("/files/:path+", "https://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := u.Exec("/files/a/b", "https://example.com")
+	r.RenameGroups(map[string]string{"path": "segments"})
+	if values := r.Pathname.GroupValues("segments"); !slices.Equal(values, []string{"a", "b"}) {
+		t.Errorf("got %q after renaming", values)
+	}
+}
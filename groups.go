@@ -0,0 +1,138 @@
+package urlpattern
+
+import (
+	"slices"
+	"strconv"
+)
+
+// AnonymousGroupName returns the name of the i-th unnamed group of a
+// component, such as the "*" wildcard of "/static/*" or the regexp group of
+// "/(\\d+)".
+//
+// Unnamed groups are numbered from 0 in the order they appear in the pattern
+// string of each component, as required by the specification: the names are
+// "0", "1"... and are guaranteed to stay the same across versions.
+func AnonymousGroupName(i int) string {
+	return strconv.Itoa(i)
+}
+
+// IsAnonymousGroupName reports whether name is the name of an unnamed group,
+// see AnonymousGroupName. Named groups cannot start with a digit.
+func IsAnonymousGroupName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i := range len(name) {
+		if name[i] < '0' || name[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RenameGroups renames the groups of all the components of r, see
+// URLPatternComponentResult.RenameGroups.
+func (r *URLPatternResult) RenameGroups(names map[string]string) {
+	for _, name := range componentNames {
+		r.component(name).RenameGroups(names)
+	}
+}
+
+// RenameGroups renames the groups of r whose name is a key of names, e.g. to
+// give a meaningful name to anonymous groups:
+//
+//	result.Pathname.RenameGroups(map[string]string{"0": "rest"})
+//
+// All the groups are renamed at once, so names can be swapped. Groups not in
+// names keep their name. If several groups end up with the same name, the
+// value of the last one in pattern order is kept.
+func (r *URLPatternComponentResult) RenameGroups(names map[string]string) {
+	if len(names) == 0 || len(r.groupNames) == 0 {
+		return
+	}
+
+	rename := func(name string) string {
+		if newName, ok := names[name]; ok && name != "" {
+			return newName
+		}
+
+		return name
+	}
+
+	// groupNames is shared with the component and the other results.
+	groupNames := make([]string, len(r.groupNames))
+	var (
+		unmatchedGroups  map[string]bool
+		repeatSeparators map[string]string
+	)
+	for i, name := range r.groupNames {
+		groupNames[i] = rename(name)
+		if r.unmatchedGroups[name] {
+			if unmatchedGroups == nil {
+				unmatchedGroups = make(map[string]bool)
+			}
+			unmatchedGroups[groupNames[i]] = true
+		}
+		if separator, ok := r.repeatSeparators[name]; ok {
+			if repeatSeparators == nil {
+				repeatSeparators = make(map[string]string)
+			}
+			repeatSeparators[groupNames[i]] = separator
+		}
+	}
+
+	if r.Groups != nil {
+		groups := make(map[string]string, len(r.Groups))
+		for _, name := range r.groupNames {
+			if value, ok := r.Groups[name]; ok && name != "" {
+				groups[rename(name)] = value
+			}
+		}
+		r.Groups = groups
+	}
+
+	if r.TypedGroups != nil {
+		typedGroups := make(map[string]any, len(r.TypedGroups))
+		for _, name := range r.groupNames {
+			if value, ok := r.TypedGroups[name]; ok && name != "" {
+				typedGroups[rename(name)] = value
+			}
+		}
+		r.TypedGroups = typedGroups
+	}
+
+	r.groupNames, r.unmatchedGroups, r.repeatSeparators = groupNames, unmatchedGroups, repeatSeparators
+}
+
+// Has reports whether a component of r has a group named name which
+// participated in the match, see URLPatternComponentResult.Has.
+func (r *URLPatternResult) Has(name string) bool {
+	for _, c := range componentNames {
+		if r.component(c).Has(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Has reports whether r has a group named name which participated in the
+// match. Groups is unable to tell apart an optional group which didn't
+// participate from a group which matched the empty string:
+//
+//	// Pattern: "/books/:id(x*)?"
+//	result.Pathname.Has("id") // false for "/books", true for "/books/" and "/books/x"
+//
+// Groups with the "?" and "*" modifiers don't participate when their part is
+// skipped, as they are undefined in browsers. Groups without modifier or with
+// the "+" modifier always participate, even when they match the empty string
+// (e.g. "*" matching ""). It returns false for unknown groups.
+func (r *URLPatternComponentResult) Has(name string) bool {
+	if name == "" || !slices.Contains(r.groupNames, name) {
+		return false
+	}
+
+	return !r.unmatchedGroups[name]
+}
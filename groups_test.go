@@ -0,0 +1,125 @@
+package urlpattern_test
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestAnonymousGroupName(t *testing.T) {
+	u, err := urlpattern.New("https://example.com/(\\d+)/:name/*", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := u.Exec("https://example.com/42/foo/bar/baz", "")
+	if r == nil {
+		t.Fatal("no match")
+	}
+
+	want := map[string]string{
+		urlpattern.AnonymousGroupName(0): "42",
+		"name":                           "foo",
+		urlpattern.AnonymousGroupName(1): "bar/baz",
+	}
+	if !maps.Equal(r.Pathname.Groups, want) {
+		t.Errorf("got %v; want %v", r.Pathname.Groups, want)
+	}
+
+	for name, want := range map[string]bool{"0": true, "12": true, "name": false, "": false, "0a": false} {
+		if got := urlpattern.IsAnonymousGroupName(name); got != want {
+			t.Errorf("IsAnonymousGroupName(%q) = %v; want %v", name, got, want)
+		}
+	}
+}
+
+func TestRenameGroups(t *testing.T) {
+	u, err := urlpattern.New("https://:sub.example.com/(\\d+)/:name/*?", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := u.Exec("https://www.example.com/42/foo", "")
+	if r == nil {
+		t.Fatal("no match")
+	}
+
+	r.RenameGroups(map[string]string{"0": "name", "name": "id", "1": "rest"})
+
+	if want := map[string]string{"name": "42", "id": "foo", "rest": ""}; !maps.Equal(r.Pathname.Groups, want) {
+		t.Errorf("got %v; want %v", r.Pathname.Groups, want)
+	}
+	if want := map[string]string{"sub": "www"}; !maps.Equal(r.Hostname.Groups, want) {
+		t.Errorf("got %v; want %v", r.Hostname.Groups, want)
+	}
+
+	want := []urlpattern.Group{{"name", "42"}, {"id", "foo"}, {"rest", ""}}
+	if got := r.Pathname.OrderedGroups(); !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"groups":{"name":"42","id":"foo","rest":null}`; !strings.Contains(string(b), want) {
+		t.Errorf("got %s; want it to contain %s", b, want)
+	}
+
+	// Renaming must not affect the pattern.
+	r = u.Exec("https://www.example.com/42/foo", "")
+	if want := map[string]string{"0": "42", "name": "foo", "1": ""}; !maps.Equal(r.Pathname.Groups, want) {
+		t.Errorf("got %v; want %v", r.Pathname.Groups, want)
+	}
+}
+
+func TestHas(t *testing.T) {
+	for _, tt := range []struct {
+		pattern  string
+		input    string
+		expected bool
+	}{
+		{"/books/:id?", "/books", false},
+		{"/books/:id?", "/books/1", true},
+		{"/books/:id*", "/books", false},
+		{"/books/:id*", "/books/1/2", true},
+		{"/books/:id+", "/books/1", true},
+		{"/books/:id", "/books/1", true},
+		{"/books/:id(.*)?", "/books", false},
+		{"/books/:id(.*)", "/books/", true},
+		{"/books:id(.*)", "/books", true},
+		{"/books/:id(x*)?", "/books/", true},
+		{"/books/:id(x*)?", "/books", false},
+		{"/books/:id(x*)*", "/books/", true},
+		{"/books/{:id(x*)}?", "/books/", true},
+	} {
+		u, err := urlpattern.New(tt.pattern, "https://example.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := u.Exec(tt.input, "https://example.com")
+		if r == nil {
+			t.Fatalf("%s: %s doesn't match", tt.pattern, tt.input)
+		}
+
+		if has := r.Pathname.Has("id"); has != tt.expected {
+			t.Errorf("%s: Has() = %v for %s", tt.pattern, has, tt.input)
+		}
+		if has := r.Has("id"); has != tt.expected {
+			t.Errorf("%s: URLPatternResult.Has() = %v for %s", tt.pattern, has, tt.input)
+		}
+		if r.Pathname.Has("unknown") || r.Has("") {
+			t.Errorf("%s: unknown group reported", tt.pattern)
+		}
+
+		r.Pathname.RenameGroups(map[string]string{"id": "book"})
+		if has := r.Pathname.Has("book"); has != tt.expected {
+			t.Errorf("%s: Has() = %v for %s after renaming", tt.pattern, has, tt.input)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package urlpattern
+
+import (
+	"slices"
+	"strings"
+)
+
+// HostRouter finds the first pattern, in insertion order, matching a URL,
+// without evaluating the hostname regexp of every pattern.
+//
+// Patterns are indexed by the fixed labels ending their hostname pattern in a
+// trie of reversed labels: "https://*.example.com/*" is stored under "com",
+// then "example". Looking up a URL only evaluates the patterns stored along
+// the path of its hostname labels, which resolves rules such as
+// "*.tenant.example.com" across thousands of tenants in O(labels). Patterns
+// whose hostname doesn't end with fixed labels (e.g. "*" or ":tld") are
+// evaluated for every URL.
+//
+// The zero value is ready to use. Add must not be called concurrently with
+// other methods.
+type HostRouter struct {
+	root trieNode
+	len  int
+}
+
+// Add appends pattern to the router.
+func (r *HostRouter) Add(pattern *URLPattern) {
+	e := patternEntry{index: r.len, pattern: pattern}
+	r.len++
+
+	hostname, exact, ok := fixedHostnameSuffix(pattern)
+	if !ok {
+		r.root.insert(nil, e, false)
+
+		return
+	}
+
+	labels := strings.Split(hostname, ".")
+	slices.Reverse(labels)
+
+	r.root.insert(labels, e, exact)
+}
+
+// Len returns the number of patterns in the router.
+func (r *HostRouter) Len() int {
+	return r.len
+}
+
+// Match returns the first added pattern matching input, and the result of the
+// match. If no pattern matches, it returns nil and a nil result.
+func (r *HostRouter) Match(input string) (*URLPattern, *URLPatternResult) {
+	var candidates []patternEntry
+	if u, err := urlParser.Parse(input); err == nil {
+		labels := strings.Split(u.Hostname(), ".")
+		slices.Reverse(labels)
+
+		candidates = r.root.candidates(nil, labels)
+	} else {
+		// Patterns using IDNALax or IDNANone may still match.
+		candidates = r.root.all(nil)
+	}
+
+	for _, c := range candidates {
+		if result := c.pattern.Exec(input, ""); result != nil {
+			return c.pattern, result
+		}
+	}
+
+	return nil, nil
+}
+
+// fixedHostnameSuffix returns the complete labels of the fixed text ending the
+// hostname pattern of u, and whether they are the whole hostname. ok is false
+// if the hostname pattern doesn't end with a complete fixed label.
+func fixedHostnameSuffix(u *URLPattern) (hostname string, exact bool, ok bool) {
+	if u.config.idnaMode != IDNAPunycode || u.config.urlParser != nil || u.config.ipHostnames {
+		return "", false, false
+	}
+
+	pl := u.hostname.partList
+	if len(pl) == 0 {
+		return "", true, true
+	}
+
+	last := pl[len(pl)-1]
+	if last.pType != partFixedText || last.modifier != partModifierNone {
+		return "", false, false
+	}
+
+	hostname = last.value
+	for i := range len(hostname) {
+		if hostname[i] >= 0x80 {
+			return "", false, false
+		}
+	}
+
+	if len(pl) == 1 {
+		return hostname, true, true
+	}
+
+	// The first label is complete only if it follows a dot.
+	if !strings.HasPrefix(hostname, ".") && !strings.HasSuffix(pl[len(pl)-2].suffix, ".") {
+		_, hostname, ok = strings.Cut(hostname, ".")
+		if !ok {
+			return "", false, false
+		}
+
+		return hostname, false, true
+	}
+
+	return strings.TrimPrefix(hostname, "."), false, true
+}
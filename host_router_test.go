@@ -0,0 +1,73 @@
+package urlpattern_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestHostRouter(t *testing.T) {
+	var router urlpattern.HostRouter
+
+	patterns := make(map[string]*urlpattern.URLPattern)
+	for _, p := range []string{
+		"https://api.example.com/*",
+		"https://*.tenant1.example.com/*",
+		"https://{*.}?tenant2.example.com/*",
+		"https://:sub-admin.example.com/*",
+		"https://example.:tld/*",
+		"https://*.example.com/*",
+	} {
+		pattern, err := urlpattern.Compile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		patterns[p] = pattern
+		router.Add(pattern)
+	}
+
+	for i := range 1000 {
+		pattern, err := urlpattern.Compile(fmt.Sprintf("https://*.customer%d.example.org/*", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		router.Add(pattern)
+	}
+
+	if router.Len() != 1006 {
+		t.Errorf("unexpected length %d", router.Len())
+	}
+
+	for input, want := range map[string]string{
+		"https://api.example.com/foo":         "https://api.example.com/*",
+		"https://a.b.tenant1.example.com/foo": "https://*.tenant1.example.com/*",
+		"https://tenant2.example.com/foo":     "https://{*.}?tenant2.example.com/*",
+		"https://x.tenant2.example.com/foo":   "https://{*.}?tenant2.example.com/*",
+		"https://foo-admin.example.com/foo":   "https://:sub-admin.example.com/*",
+		"https://example.net/foo":             "https://example.:tld/*",
+		"https://example.com/foo":             "https://example.:tld/*",
+		"https://www.example.com/foo":         "https://*.example.com/*",
+		"https://tenant1.example.com/foo":     "https://*.example.com/*",
+		"https://www.example.org/foo":         "",
+		"http://api.example.com/foo":          "",
+	} {
+		t.Run(input, func(t *testing.T) {
+			pattern, result := router.Match(input)
+			if pattern != patterns[want] {
+				t.Fatalf("want %q; got %v", want, pattern)
+			}
+
+			if (result == nil) != (want == "") {
+				t.Errorf("unexpected result %#v", result)
+			}
+		})
+	}
+
+	pattern, result := router.Match("https://www.customer42.example.org/")
+	if pattern == nil || pattern.Hostname() != "*.customer42.example.org" || result.Hostname.Groups["0"] != "www" {
+		t.Errorf("unexpected match %v %#v", pattern, result)
+	}
+}
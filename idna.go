@@ -0,0 +1,129 @@
+package urlpattern
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/url"
+	"golang.org/x/net/idna"
+)
+
+// IDNAMode controls how internationalized domain names are canonicalized,
+// both in hostname patterns and in the URLs they are matched against.
+type IDNAMode uint8
+
+const (
+	// IDNAPunycode converts hostnames to their ASCII (punycode) form, as
+	// specified by the WHATWG URL standard. This is the default.
+	IDNAPunycode IDNAMode = iota
+	// IDNAUnicode converts hostnames to their Unicode form after the
+	// standard processing: "xn--caf-dma.com" becomes "café.com", and
+	// patterns can be written and read in Unicode.
+	IDNAUnicode
+	// IDNALax accepts hostnames rejected by IDNA validation (e.g. labels
+	// containing joiners or disallowed code points, as used by some internal
+	// naming schemes) instead of failing: they are converted as far as
+	// possible and otherwise kept as is.
+	IDNALax
+	// IDNANone disables IDNA entirely: hostnames other than IPv6 addresses
+	// are kept verbatim, only their ASCII letters are lowercased. Unicode
+	// hostnames aren't converted to punycode, and names rejected by the
+	// standard processing, such as "my_svc.ns.svc" or "a\u200db.svc", are
+	// accepted, which suits internal hostnames. IPv4 addresses aren't
+	// canonicalized either: "0x7f.1" doesn't match "127.0.0.1".
+	IDNANone
+)
+
+// WithIDNAMode sets how internationalized domain names are canonicalized.
+func WithIDNAMode(mode IDNAMode) Option {
+	return func(c *config) {
+		c.idnaMode = mode
+	}
+}
+
+// parser returns the default URLParser in mode m.
+func (m IDNAMode) parser() whatwgURLParser {
+	switch m {
+	case IDNALax:
+		return whatwgURLParser{laxURLParser, laxHostnameParser}
+	case IDNANone:
+		return whatwgURLParser{verbatimURLParser, verbatimHostnameParser}
+	}
+
+	return whatwgURLParser{urlParser, hostnameParser}
+}
+
+// https://urlpattern.spec.whatwg.org/#canonicalize-a-hostname
+//
+// The hostname is parsed with p, and then converted to the form used in
+// mode m.
+func (m IDNAMode) canonicalizeHostname(p URLParser, hostnameValue, protocolValue string) (string, error) {
+	h, err := canonicalizeHostname(p, hostnameValue, protocolValue)
+	if err != nil {
+		return "", err
+	}
+
+	return m.hostname(h), nil
+}
+
+// https://github.com/whatwg/urlpattern/issues/220#issuecomment-2074613501
+func (m IDNAMode) canonicalizeDomainName(p URLParser, value string) (string, error) {
+	return m.canonicalizeHostname(p, value, "https")
+}
+
+// hostname converts a hostname canonicalized by the URL parser to the form
+// used in mode m.
+func (m IDNAMode) hostname(h string) string {
+	switch m {
+	case IDNANone:
+		return decodeVerbatimHost(h)
+	case IDNAUnicode:
+	default:
+		return h
+	}
+
+	if u, err := idna.Punycode.ToUnicode(h); err == nil {
+		return u
+	}
+
+	return h
+}
+
+// verbatimHostPrefix starts the hostnames encoded by encodeVerbatimHost, so
+// that they are never parsed as IPv4 addresses nor mistaken for hostnames.
+const verbatimHostPrefix = "xv--"
+
+// encodeVerbatimHost hex-encodes host, with its ASCII letters lowercased, so
+// that the URL parser keeps it unchanged: the encoded form is made of
+// lowercase letters and digits only, which IDNA processing doesn't alter.
+// IPv6 addresses are kept as is. See decodeVerbatimHost.
+func encodeVerbatimHost(_ *url.Url, host string) string {
+	if host == "" || host[0] == '[' {
+		return host
+	}
+
+	b := []byte(host)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+
+	return verbatimHostPrefix + hex.EncodeToString(b)
+}
+
+// decodeVerbatimHost returns the hostname encoded by encodeVerbatimHost, or h
+// if it isn't encoded.
+func decodeVerbatimHost(h string) string {
+	encoded, ok := strings.CutPrefix(h, verbatimHostPrefix)
+	if !ok {
+		return h
+	}
+
+	decoded, err := hex.DecodeString(encoded)
+	if err != nil {
+		return h
+	}
+
+	return string(decoded)
+}
@@ -0,0 +1,96 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestIDNAMode(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		mode         urlpattern.IDNAMode
+		pattern      string
+		wantHostname string
+		input        string
+		wantInput    string
+	}{
+		{"punycode", urlpattern.IDNAPunycode, "https://café.com/*", "xn--caf-dma.com", "https://CAFÉ.com/", "xn--caf-dma.com"},
+		{"unicode", urlpattern.IDNAUnicode, "https://xn--caf-dma.com/*", "café.com", "https://café.com/", "café.com"},
+		{"unicode wildcard", urlpattern.IDNAUnicode, "https://:sub.café.com/*", ":sub.café.com", "https://xn--bcher-kva.xn--caf-dma.com/", "bücher.café.com"},
+		{"lax", urlpattern.IDNALax, "https://a‍b.svc/*", "xn--ab-m1t.svc", "https://a‍b.svc/", "xn--ab-m1t.svc"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pattern, err := urlpattern.Compile(tc.pattern, urlpattern.WithIDNAMode(tc.mode))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if pattern.Hostname() != tc.wantHostname {
+				t.Errorf("want hostname %q; got %q", tc.wantHostname, pattern.Hostname())
+			}
+
+			r := pattern.Exec(tc.input, "")
+			if r == nil {
+				t.Fatalf("want %q to match", tc.input)
+			}
+
+			if r.Hostname.Input != tc.wantInput {
+				t.Errorf("want input %q; got %q", tc.wantInput, r.Hostname.Input)
+			}
+		})
+	}
+
+	if _, err := urlpattern.Compile("https://a‍b.svc/*"); err == nil {
+		t.Error("want error without IDNALax")
+	}
+}
+
+func TestIDNANone(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://:svc.Café_internal.svc/*", urlpattern.WithIDNAMode(urlpattern.IDNANone))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pattern.Hostname() != ":svc.café_internal.svc" {
+		t.Errorf("want hostname kept verbatim; got %q", pattern.Hostname())
+	}
+
+	for input, want := range map[string]string{
+		"https://my_api.CAFé_internal.svc/": "my_api.café_internal.svc",
+		"https://a‍b.café_internal.svc/":    "a‍b.café_internal.svc",
+		"https://a-very-long-label-of-more-than-63-characters-once-hex-encoded.café_internal.svc/": "a-very-long-label-of-more-than-63-characters-once-hex-encoded.café_internal.svc",
+	} {
+		r := pattern.Exec(input, "")
+		if r == nil {
+			t.Errorf("want %q to match", input)
+
+			continue
+		}
+		if r.Hostname.Input != want {
+			t.Errorf("want input %q; got %q", want, r.Hostname.Input)
+		}
+	}
+
+	for _, input := range []string{"https://a.xn--caf_internal-r7a.svc/", "https://a.CAFÉ_internal.svc/"} {
+		if pattern.Test(input, "") {
+			t.Errorf("%s must not match", input)
+		}
+	}
+
+	ip, err := urlpattern.Compile(`https://[\:\:1]/*`, urlpattern.WithIDNAMode(urlpattern.IDNANone))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ip.Test("https://[0:0::1]/", "") {
+		t.Error("IPv6 addresses must still be canonicalized")
+	}
+
+	base, err := urlpattern.Compile("/books/*", urlpattern.WithIDNAMode(urlpattern.IDNANone), urlpattern.WithBaseURL("https://my_svc.café.svc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.Hostname() != "my_svc.café.svc" || !base.Test("/books/1", "https://my_svc.café.svc") {
+		t.Errorf("want base URL hostname kept verbatim; got %q", base.Hostname())
+	}
+}
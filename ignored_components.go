@@ -0,0 +1,36 @@
+package urlpattern
+
+import "sync"
+
+// WithIgnoreSearch makes the pattern match URLs whatever their search
+// component, for routers which never dispatch on query strings: the search
+// pattern is replaced by "*", and search inputs are neither matched against a
+// regexp nor captured in groups.
+func WithIgnoreSearch() Option {
+	return func(c *config) {
+		c.ignoreSearch = true
+	}
+}
+
+// WithIgnoreHash makes the pattern match URLs whatever their hash component,
+// for routers which never dispatch on fragments, see WithIgnoreSearch.
+func WithIgnoreHash() Option {
+	return func(c *config) {
+		c.ignoreHash = true
+	}
+}
+
+// ignoredComponent returns the component used for the search and hash of the
+// patterns compiled with WithIgnoreSearch and WithIgnoreHash. It has the
+// pattern string and the regexp of "*", but matches any input without groups.
+var ignoredComponent = sync.OnceValue(func() *component {
+	wildcard, err := wildcardComponent("", options{})
+	if err != nil {
+		panic(err)
+	}
+
+	c := *wildcard
+	c.groupNames, c.groupIndex, c.ignored = nil, nil, true
+
+	return &c
+})
@@ -0,0 +1,34 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestIgnoreSearchAndHash(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://example.com/books/:id/reviews?page=1#top", urlpattern.WithIgnoreSearch(), urlpattern.WithIgnoreHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pattern.Search() != "*" || pattern.Hash() != "*" {
+		t.Errorf("want wildcards; got %q and %q", pattern.Search(), pattern.Hash())
+	}
+
+	r := pattern.Exec("https://example.com/books/42/reviews?page=2&sort=asc#reviews", "")
+	if r == nil || r.Pathname.Groups["id"] != "42" {
+		t.Fatalf("unexpected result %#v", r)
+	}
+	if r.Search.Input != "page=2&sort=asc" || r.Search.Groups != nil || r.Hash.Input != "reviews" || r.Hash.Groups != nil {
+		t.Errorf("unexpected search and hash results %#v, %#v", r.Search, r.Hash)
+	}
+
+	pattern, err = urlpattern.Compile("https://example.com/books/:id#top", urlpattern.WithIgnoreSearch())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pattern.Test("https://example.com/books/42?page=2#top", "") || pattern.Test("https://example.com/books/42?page=2#reviews", "") {
+		t.Error("want only the hash to be matched")
+	}
+}
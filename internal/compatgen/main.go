@@ -0,0 +1,123 @@
+// Command compatgen generates the table of canonicalization results used by
+// urlpattern.WithStrictCompat from the expected results of the Web Platform
+// Tests embedded in the urlpatterntest package. It must be run again after
+// updating them.
+//
+// Usage:
+//
+//	compatgen [-o file]
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/dunglas/go-urlpattern/urlpatterntest"
+)
+
+var componentNames = []string{"protocol", "username", "password", "hostname", "port", "pathname", "search", "hash"}
+
+var specialSchemes = []string{"ftp", "file", "http", "https", "ws", "wss"}
+
+func main() {
+	output := flag.String("o", "", "output file (default: standard output)")
+	flag.Parse()
+
+	src, err := generate(urlpatterntest.Entries())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "compatgen:", err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*output, src, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "compatgen:", err)
+		os.Exit(1)
+	}
+}
+
+// generate returns the source code of the table derived from entries: the
+// pattern string expected for each component input of the entries created
+// from a URLPatternInit without base URL. Inputs expected to produce
+// different pattern strings in different entries are left out, as well as
+// the hostnames and pathnames of non-special schemes.
+func generate(entries []urlpatterntest.Entry) ([]byte, error) {
+	table := make(map[string]map[string]string, len(componentNames))
+	conflicts := make(map[[2]string]bool)
+	for _, entry := range entries {
+		if len(entry.Pattern) != 1 {
+			continue
+		}
+
+		init, ok := entry.Pattern[0].(map[string]any)
+		if !ok || init["baseURL"] != nil {
+			continue
+		}
+
+		expected, ok := entry.ExpectedObj.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		protocol, hasProtocol := init["protocol"].(string)
+		special := !hasProtocol || slices.Contains(specialSchemes, protocol)
+		for _, name := range componentNames {
+			input, ok := init[name].(string)
+			if !ok || (!special && (name == "hostname" || name == "pathname")) {
+				continue
+			}
+
+			want, ok := expected[name].(string)
+			if !ok {
+				continue
+			}
+
+			if table[name] == nil {
+				table[name] = make(map[string]string)
+			}
+			if got, ok := table[name][input]; ok && got != want {
+				conflicts[[2]string{name, input}] = true
+			}
+			table[name][input] = want
+		}
+	}
+
+	for c := range conflicts {
+		delete(table[c[0]], c[1])
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`// Code generated by compatgen. DO NOT EDIT.
+
+package urlpattern
+
+// compatTable maps the input of components to the pattern strings produced by
+// browsers, derived from the expected results of the Web Platform Tests (see
+// the urlpatterntest package). Hostname and pathname entries apply to special
+// schemes.
+var compatTable = map[string]map[string]string{
+`)
+	for _, name := range componentNames {
+		if len(table[name]) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\t%q: {\n", name)
+		for _, input := range slices.Sorted(maps.Keys(table[name])) {
+			fmt.Fprintf(&b, "\t\t%q: %q,\n", input, table[name][input])
+		}
+		b.WriteString("\t},\n")
+	}
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern/urlpatterntest"
+)
+
+func TestCompatTableUpToDate(t *testing.T) {
+	src, err := generate(urlpatterntest.Entries())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := os.ReadFile("../../compat_table.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(src, current) {
+		t.Error("compat_table.go is stale, run go generate")
+	}
+}
@@ -0,0 +1,72 @@
+package urlpattern
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// WithIPHostnames makes IP addresses match all their equivalent textual
+// representations. Hostname patterns consisting of an IP address, such as
+// "127.0.0.1", "[::1]" or "[0:0:0:0:0:0:0:1]", and the hostnames of the
+// matched URLs are converted to the form returned by CanonicalIP: for
+// instance, "[::ffff:127.0.0.1]" matches the pattern "127.0.0.1", and the
+// Input of the hostname result is "127.0.0.1".
+//
+// IPv6 addresses in hostname patterns don't need to be escaped: with this
+// option, "[::ffff:7f00:1]" is the address, not a group named "ffff".
+func WithIPHostnames() Option {
+	return func(c *config) {
+		c.ipHostnames = true
+	}
+}
+
+// CanonicalIP returns the canonical form of the IP address host, with or
+// without brackets, as used by WithIPHostnames, and reports whether host is an
+// IP address. See IPHostname for the canonical form.
+func CanonicalIP(host string) (string, bool) {
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil || addr.Zone() != "" {
+		return "", false
+	}
+
+	return IPHostname(addr), true
+}
+
+// IPHostname returns the hostname of a URL whose host is addr, for instance
+// to match the address of a client (see net/netip.ParseAddrPort) against a
+// pattern using WithIPHostnames. IPv4-mapped IPv6 addresses are converted to
+// IPv4 addresses, and IPv6 addresses are written in the form recommended by
+// RFC 5952, enclosed in brackets. The zone of addr is dropped.
+func IPHostname(addr netip.Addr) string {
+	addr = addr.Unmap().WithZone("")
+	if addr.Is4() {
+		return addr.String()
+	}
+
+	return "[" + addr.String() + "]"
+}
+
+// canonicalIPHostname returns the canonical form of hostname if it is an IP
+// address, and hostname otherwise.
+func canonicalIPHostname(hostname string) string {
+	if ip, ok := CanonicalIP(hostname); ok {
+		return ip
+	}
+
+	return hostname
+}
+
+// canonicalIPHostnamePattern returns the pattern matching the canonical form
+// of the IP address written in the hostname pattern p, possibly with escaped
+// characters, or p if it isn't an IP address.
+func canonicalIPHostnamePattern(p string) string {
+	if ip, ok := CanonicalIP(strings.ReplaceAll(p, `\`, "")); ok {
+		return EscapePatternString(ip)
+	}
+
+	return p
+}
@@ -0,0 +1,75 @@
+package urlpattern_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestCanonicalIP(t *testing.T) {
+	for host, expected := range map[string]string{
+		"127.0.0.1":          "127.0.0.1",
+		"[::1]":              "[::1]",
+		"::1":                "[::1]",
+		"[0:0:0:0:0:0:0:1]":  "[::1]",
+		"[2001:DB8::0:1]":    "[2001:db8::1]",
+		"[::ffff:127.0.0.1]": "127.0.0.1",
+		"[::ffff:7f00:1]":    "127.0.0.1",
+	} {
+		if ip, ok := urlpattern.CanonicalIP(host); !ok || ip != expected {
+			t.Errorf("CanonicalIP(%q) = %q, %v; want %q", host, ip, ok, expected)
+		}
+	}
+
+	for _, host := range []string{"", "example.com", "127.1", "[::1", "fe80::1%eth0"} {
+		if ip, ok := urlpattern.CanonicalIP(host); ok {
+			t.Errorf("CanonicalIP(%q) = %q; must not be an IP address", host, ip)
+		}
+	}
+
+	if h := urlpattern.IPHostname(netip.MustParseAddr("::ffff:10.0.0.1")); h != "10.0.0.1" {
+		t.Errorf("unexpected hostname %q", h)
+	}
+}
+
+func TestWithIPHostnames(t *testing.T) {
+	for pattern, inputs := range map[string][]string{
+		"http://127.0.0.1/*":          {"http://127.0.0.1/", "http://[::ffff:127.0.0.1]/", "http://[::ffff:7f00:1]/", "http://127.1/"},
+		"http://[::1]/*":              {"http://[::1]/", "http://[0:0:0:0:0:0:0:1]/"},
+		"http://[0:0:0:0:0:0:0:1]/*":  {"http://[::1]/"},
+		"http://[::ffff:7f00:1]/*":    {"http://127.0.0.1/"},
+		`http://\[2001\:db8\:\:1\]/*`: {"http://[2001:DB8:0::1]/"},
+	} {
+		u, err := urlpattern.Compile(pattern, urlpattern.WithIPHostnames())
+		if err != nil {
+			t.Fatalf("%s: %v", pattern, err)
+		}
+
+		for _, input := range inputs {
+			if !u.Test(input, "") {
+				t.Errorf("%s must match %q", pattern, input)
+			}
+		}
+
+		if u.Test("http://[::2]/", "") {
+			t.Errorf("%s must not match another address", pattern)
+		}
+	}
+
+	u, err := urlpattern.Compile("http://127.0.0.1/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Test("http://[::ffff:127.0.0.1]/", "") {
+		t.Error("IPv4-mapped addresses must only match with WithIPHostnames")
+	}
+
+	u, err = urlpattern.Compile("http://*/*", urlpattern.WithIPHostnames())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := u.Exec("http://[::ffff:192.168.0.1]/", ""); r == nil || r.Hostname.Input != "192.168.0.1" {
+		t.Errorf("unexpected result %#v", r)
+	}
+}
@@ -0,0 +1,30 @@
+package urlpattern
+
+import "net/http"
+
+// KeyFunc returns a function computing the keys of requests for rate
+// limiters: the URL of the request is matched against patterns, and the
+// placeholders of template are replaced by the parts of the match they
+// select, as in the labels of a Classifier (see Class.Label). For instance,
+// to limit each book independently per host:
+//
+//	key := urlpattern.KeyFunc(list, "{hostname}:{pathname.id}")
+//	limiters := make(map[string]*rate.Limiter)
+//	limiter, ok := limiters[key(r)]
+//
+// The URL of the request is reconstructed without trusting forwarding
+// headers, see ProxyTrust.RequestURL. The returned function returns an empty
+// string if no pattern matches, and placeholders selecting parts missing from
+// the matching pattern are replaced by empty strings.
+func KeyFunc(patterns *URLPatternList, template string) func(*http.Request) string {
+	parts := parseLabel(template)
+
+	return func(r *http.Request) string {
+		m := patterns.Exec(ProxyTrust{}.RequestURL(r))
+		if m == nil {
+			return ""
+		}
+
+		return expandLabel(parts, m.Result)
+	}
+}
@@ -0,0 +1,32 @@
+package urlpattern_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestKeyFunc(t *testing.T) {
+	var list urlpattern.URLPatternList
+	for _, p := range []string{"http://*/books/:id", "http://*/authors/:name"} {
+		u, err := urlpattern.Compile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list.Add(u)
+	}
+
+	key := urlpattern.KeyFunc(&list, "{hostname}:{pathname.id}")
+
+	for target, expected := range map[string]string{
+		"http://example.com/books/42":     "example.com:42",
+		"http://example.org/books/42?p=1": "example.org:42",
+		"http://example.com/authors/lily": "example.com:",
+		"http://example.com/":             "",
+	} {
+		if k := key(httptest.NewRequest("GET", target, nil)); k != expected {
+			t.Errorf("%s: got %q, want %q", target, k, expected)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package urlpattern
+
+import (
+	"fmt"
+	"regexp/syntax"
+)
+
+// Warning describes a suspicious construct found by Lint.
+type Warning struct {
+	// Component is the name of the component, e.g. "hostname", or the empty
+	// string if the warning applies to the whole pattern.
+	Component string
+	Message   string
+}
+
+func (w Warning) String() string {
+	if w.Component == "" {
+		return w.Message
+	}
+
+	return w.Component + ": " + w.Message
+}
+
+// Lint compiles the constructor string pattern and reports common mistakes:
+//   - unescaped dots in the regexp groups of the hostname, which match any
+//     character instead of a dot,
+//   - unnamed "*" wildcards in pathnames also using named groups, whose value
+//     is only available in the "0" group where ":name*" was probably intended,
+//   - pathnames or hostnames only matching the empty string, which special
+//     URLs never have, making the pattern match nothing,
+//   - regexp groups starting the pathname or ending the hostname, which
+//     prevent URLPatternList and HostRouter from indexing the pattern.
+//
+// If pattern cannot be compiled, the error is the only warning.
+func Lint(pattern string, opts ...Option) []Warning {
+	u, err := Compile(pattern, opts...)
+	if err != nil {
+		return []Warning{{Message: err.Error()}}
+	}
+
+	return u.lint()
+}
+
+func (u *URLPattern) lint() []Warning {
+	var warnings []Warning
+	warn := func(component, format string, args ...any) {
+		warnings = append(warnings, Warning{component, fmt.Sprintf(format, args...)})
+	}
+
+	for _, p := range u.hostname.partList {
+		if p.pType == partRegexp && hasUnescapedDot(p.value) {
+			warn("hostname", "regexp group %q contains an unescaped dot matching any character, use \\. to match a dot", "("+p.value+")")
+		}
+	}
+
+	var named bool
+	for _, p := range u.pathname.partList {
+		if p.pType != partFixedText && isValidName(p.name) {
+			named = true
+		}
+	}
+	if named {
+		for _, p := range u.pathname.partList {
+			if p.pType == partFullWildcard && !isValidName(p.name) {
+				warn("pathname", "unnamed \"*\" wildcard is only available in group %q, use a named group such as \":rest*\" instead", p.name)
+			}
+		}
+	}
+
+	if u.protocol.protocolComponentMatchesSpecialScheme() {
+		for _, c := range []struct {
+			name      string
+			component *component
+		}{{"hostname", u.hostname}, {"pathname", u.pathname}} {
+			if c.component.isExactlyEmpty() {
+				warn(c.name, "only matches the empty string, which URLs with a special scheme never have")
+			}
+		}
+	}
+
+	if pl := u.pathname.partList; len(pl) > 0 && pl[0].pType == partRegexp {
+		warn("pathname", "regexp group %q starts the pathname, which prevents URLPatternList from indexing the pattern", "("+pl[0].value+")")
+	}
+	if pl := u.hostname.partList; len(pl) > 0 && pl[len(pl)-1].pType == partRegexp {
+		warn("hostname", "regexp group %q ends the hostname, which prevents HostRouter from indexing the pattern", "("+pl[len(pl)-1].value+")")
+	}
+
+	return warnings
+}
+
+// hasUnescapedDot reports whether re contains a "." which isn't repeated, and
+// thus probably meant to match a dot.
+func hasUnescapedDot(re string) bool {
+	r, err := syntax.Parse(re, syntax.Perl)
+	if err != nil {
+		return false
+	}
+
+	var walk func(r *syntax.Regexp) bool
+	walk = func(r *syntax.Regexp) bool {
+		switch r.Op {
+		case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+			return true
+		case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+			if sub := r.Sub[0]; sub.Op == syntax.OpAnyChar || sub.Op == syntax.OpAnyCharNotNL {
+				return false
+			}
+		}
+
+		for _, sub := range r.Sub {
+			if walk(sub) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return walk(r)
+}
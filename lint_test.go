@@ -0,0 +1,42 @@
+package urlpattern_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func ExampleLint() {
+	for _, w := range urlpattern.Lint("https://(api|www).example(.com|.org)/users/:id/*") {
+		fmt.Println(w)
+	}
+	// Output:
+	// hostname: regexp group "(.com|.org)" contains an unescaped dot matching any character, use \. to match a dot
+	// pathname: unnamed "*" wildcard is only available in group "0", use a named group such as ":rest*" instead
+	// hostname: regexp group "(.com|.org)" ends the hostname, which prevents HostRouter from indexing the pattern
+}
+
+func TestLint(t *testing.T) {
+	for pattern, want := range map[string][]string{
+		"https://example.com/users/:id":        nil,
+		"https://*.example.com/static/*":       nil,
+		"https://([a-z.]+).example.com/(.*)/a": nil,
+		"https://:8080/a":                      {"hostname: only matches the empty string, which URLs with a special scheme never have"},
+		"https://example.com":                  nil,
+		"https://example.com/(\\d+)":           {`pathname: regexp group "(\\d+)" starts the pathname, which prevents URLPatternList from indexing the pattern`},
+		"https://example.com/a{b":              {"type error: missing close token: missing required token"},
+	} {
+		got := urlpattern.Lint(pattern)
+		if len(got) != len(want) {
+			t.Errorf("%s: want %q; got %q", pattern, want, got)
+
+			continue
+		}
+		for i, w := range got {
+			if w.String() != want[i] {
+				t.Errorf("%s: want %q; got %q", pattern, want[i], w.String())
+			}
+		}
+	}
+}
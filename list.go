@@ -0,0 +1,247 @@
+package urlpattern
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// URLPatternList is an ordered list of patterns, matched by decreasing
+// priority, then in insertion order unless another MatchStrategy is set.
+//
+// Patterns are indexed by the fixed text starting their pathname pattern in a
+// trie of path segments: "https://example.com/books/:id" is stored under
+// "books". Matching a URL only evaluates the patterns stored along the path
+// of its pathname segments, which greatly reduces the number of regexps run
+// on big route tables. Patterns whose pathname doesn't start with fixed
+// segments, or which ignore case or use a custom URLParser, are evaluated for
+// every URL.
+//
+// The zero value is ready to use. Add must not be called concurrently with
+// other methods: to update a list while serving it, use the copy-on-write
+// WithRoute, WithoutRoute and WithReplacedRoute methods, or a ReloadableList.
+type URLPatternList struct {
+	routes   []Route
+	root     trieNode
+	metrics  *ListMetrics
+	strategy MatchStrategy
+	// exclusions holds the patterns added with Exclude.
+	exclusions []*URLPattern
+	hooks      ListHooks
+}
+
+// Route is an entry of a URLPatternList.
+type Route struct {
+	Pattern *URLPattern
+	// Name optionally identifies the route.
+	Name string
+	// Priority of the route: routes with a higher priority are matched
+	// first. Routes with the same priority are matched in insertion order.
+	Priority int
+	// Metadata holds user-supplied data associated with the route.
+	Metadata any
+}
+
+// Add appends pattern to the list, with the default priority.
+func (l *URLPatternList) Add(pattern *URLPattern) {
+	l.AddRoute(Route{Pattern: pattern})
+}
+
+// AddWithMetadata appends pattern to the list, with the default priority and
+// user-supplied metadata returned by MatchRoute, e.g. cache or authorization
+// rules.
+func (l *URLPatternList) AddWithMetadata(pattern *URLPattern, metadata any) {
+	l.AddRoute(Route{Pattern: pattern, Metadata: metadata})
+}
+
+// AddRoute appends route to the list.
+func (l *URLPatternList) AddRoute(route Route) {
+	e := patternEntry{index: len(l.routes), priority: route.Priority, pattern: route.Pattern}
+	l.routes = append(l.routes, route)
+
+	if l.metrics != nil {
+		l.metrics.addRoute(e.index, route.Name)
+	}
+
+	segments, exact := fixedPathnamePrefix(route.Pattern)
+	l.root.insert(segments, e, exact)
+}
+
+// Exclude adds a negated pattern to the list: URLs matching pattern never
+// match the list, whatever the other patterns, as in allow/deny rules of
+// proxies or security filters. Exclusions are only evaluated for URLs matching
+// a pattern of the list, and aren't counted by Len.
+func (l *URLPatternList) Exclude(pattern *URLPattern) {
+	l.exclusions = append(l.exclusions, pattern)
+}
+
+// Exclusions returns the patterns added with Exclude, in insertion order.
+func (l *URLPatternList) Exclusions() []*URLPattern {
+	return slices.Clone(l.exclusions)
+}
+
+// excluded reports whether input matches an exclusion of the list.
+func (l *URLPatternList) excluded(input string) bool {
+	for _, p := range l.exclusions {
+		if p.Test(input, "") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Len returns the number of patterns in the list.
+func (l *URLPatternList) Len() int {
+	return len(l.routes)
+}
+
+// Patterns returns the patterns of the list, in insertion order.
+func (l *URLPatternList) Patterns() []*URLPattern {
+	patterns := make([]*URLPattern, len(l.routes))
+	for i, r := range l.routes {
+		patterns[i] = r.Pattern
+	}
+
+	return patterns
+}
+
+// Routes returns the routes of the list, in insertion order.
+func (l *URLPatternList) Routes() []Route {
+	return slices.Clone(l.routes)
+}
+
+// Match returns the first pattern matching input, by decreasing priority then
+// according to the strategy of the list (by default in insertion order, see
+// SetStrategy), and the result of the match. If no pattern matches, it
+// returns nil and a nil result.
+func (l *URLPatternList) Match(input string) (*URLPattern, *URLPatternResult) {
+	_, pattern, result := l.observedMatch(input)
+
+	return pattern, result
+}
+
+// MatchRoute is like Match, but returns the matching route, allowing the list
+// to be used as a policy engine without a parallel lookup table:
+//
+//	route, result := list.MatchRoute("https://example.com/admin/users")
+//	if result != nil {
+//		rules := route.Metadata.(AuthRules)
+//	}
+//
+// If no pattern matches, it returns the zero Route and a nil result.
+func (l *URLPatternList) MatchRoute(input string) (Route, *URLPatternResult) {
+	index, _, result := l.observedMatch(input)
+	if index == -1 {
+		return Route{}, nil
+	}
+
+	return l.routes[index], result
+}
+
+// Exec is like MatchRoute, but returns the route with its index and the
+// result of the match in a ListMatch, so callers don't have to correlate
+// results with the routes of the list:
+//
+//	if m := list.Exec("https://example.com/books/1"); m != nil {
+//		log.Printf("route %d (%s): %v", m.Index, m.Route.Name, m.Result.Pathname.Groups)
+//	}
+//
+// If no pattern matches, it returns nil.
+func (l *URLPatternList) Exec(input string) *ListMatch {
+	index, _, result := l.observedMatch(input)
+	if index == -1 {
+		return nil
+	}
+
+	return &ListMatch{Index: index, Route: l.routes[index], Result: result}
+}
+
+// observedMatch is match, recording the metrics of the list and calling its
+// hooks if any.
+func (l *URLPatternList) observedMatch(input string) (int, *URLPattern, *URLPatternResult) {
+	if l.metrics == nil {
+		index, pattern, result := l.match(input)
+		l.notify(input, index, result)
+
+		return index, pattern, result
+	}
+
+	start := time.Now()
+	index, pattern, result := l.match(input)
+	l.metrics.observe(index, time.Since(start))
+	l.notify(input, index, result)
+
+	return index, pattern, result
+}
+
+// match returns the index of the first route matching input, its pattern and
+// the result of the match, or -1 if no route matches.
+func (l *URLPatternList) match(input string) (int, *URLPattern, *URLPatternResult) {
+	if l.strategy != StrategyFirstMatch {
+		matches := l.sortedMatches(input)
+		if len(matches) == 0 || l.excluded(input) {
+			return -1, nil, nil
+		}
+
+		return matches[0].index, matches[0].pattern, matches[0].result
+	}
+
+	for _, c := range l.candidates(input) {
+		if result := c.pattern.Exec(input, ""); result != nil {
+			if l.excluded(input) {
+				break
+			}
+
+			return c.index, c.pattern, result
+		}
+	}
+
+	return -1, nil, nil
+}
+
+// candidates returns the patterns that may match input, in matching order.
+func (l *URLPatternList) candidates(input string) []patternEntry {
+	u, err := urlParser.Parse(input)
+	if err != nil {
+		// Patterns using IDNALax or IDNANone may still match.
+		return l.root.all(nil)
+	}
+
+	return l.root.candidates(nil, strings.Split(u.Pathname(), "/"))
+}
+
+// fixedPathnamePrefix returns the complete segments of the fixed text starting
+// the pathname pattern of u, and whether they are the whole pathname.
+func fixedPathnamePrefix(u *URLPattern) (segments []string, exact bool) {
+	if u.config.ignoreCase || u.config.normalizes() || u.config.urlParser != nil {
+		return nil, false
+	}
+
+	pl := u.pathname.partList
+
+	var prefix strings.Builder
+
+	i := 0
+	for ; i < len(pl) && pl[i].pType == partFixedText && pl[i].modifier == partModifierNone; i++ {
+		prefix.WriteString(pl[i].value)
+	}
+
+	if i == len(pl) {
+		return strings.Split(prefix.String(), "/"), true
+	}
+
+	// The prefix of a required group, such as "/" in "/:id", is fixed text.
+	if m := pl[i].modifier; m == partModifierNone || m == partModifierOneOrMore {
+		prefix.WriteString(pl[i].prefix)
+	}
+
+	// Only keep the segments followed by a slash, the last one may be partial.
+	p := prefix.String()
+	end := strings.LastIndexByte(p, '/')
+	if end == -1 {
+		return nil, false
+	}
+
+	return strings.Split(p[:end], "/"), false
+}
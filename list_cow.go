@@ -0,0 +1,51 @@
+package urlpattern
+
+import "slices"
+
+// WithRoute returns a copy of l with route appended. l is left unchanged, so
+// it can still be matched concurrently: lists served to concurrent readers
+// can be updated by swapping them with their copies, as ReloadableList.Add
+// does. The patterns aren't recompiled.
+//
+// The copies returned by WithRoute, WithoutRoute and WithReplacedRoute have
+// the strategy, the exclusions and the hooks of l, but not its metrics.
+func (l *URLPatternList) WithRoute(route Route) *URLPatternList {
+	return l.withRoutes(append(slices.Clip(l.routes), route))
+}
+
+// WithoutRoute returns a copy of l without the routes named name, and
+// whether such a route was found, see WithRoute. If not, l itself is
+// returned.
+func (l *URLPatternList) WithoutRoute(name string) (*URLPatternList, bool) {
+	routes := slices.DeleteFunc(slices.Clone(l.routes), func(r Route) bool { return r.Name == name })
+	if len(routes) == len(l.routes) {
+		return l, false
+	}
+
+	return l.withRoutes(routes), true
+}
+
+// WithReplacedRoute returns a copy of l in which the first route named name
+// is replaced by route, keeping its insertion order, and whether such a route
+// was found, see WithRoute. If not, l itself is returned.
+func (l *URLPatternList) WithReplacedRoute(name string, route Route) (*URLPatternList, bool) {
+	i := slices.IndexFunc(l.routes, func(r Route) bool { return r.Name == name })
+	if i == -1 {
+		return l, false
+	}
+
+	routes := slices.Clone(l.routes)
+	routes[i] = route
+
+	return l.withRoutes(routes), true
+}
+
+// withRoutes returns a new list containing routes, with the settings of l.
+func (l *URLPatternList) withRoutes(routes []Route) *URLPatternList {
+	list := &URLPatternList{strategy: l.strategy, exclusions: slices.Clone(l.exclusions), hooks: l.hooks}
+	for _, r := range routes {
+		list.AddRoute(r)
+	}
+
+	return list
+}
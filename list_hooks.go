@@ -0,0 +1,41 @@
+package urlpattern
+
+// ListHooks holds callbacks invoked by the matching methods of a
+// URLPatternList returning a single route (Match, MatchRoute and Exec), for
+// instance to keep an audit trail of the rule which allowed or denied each
+// URL without wrapping every call site:
+//
+//	list.SetHooks(urlpattern.ListHooks{
+//		OnMatch: func(input string, m urlpattern.ListMatch) {
+//			slog.Info("allowed", "url", input, "route", m.Route)
+//		},
+//		OnMiss: func(input string) {
+//			slog.Warn("denied", "url", input)
+//		},
+//	})
+//
+// The callbacks are called synchronously, before the matching method returns,
+// and may be called from several goroutines at once. Either may be nil.
+type ListHooks struct {
+	// OnMatch is called with the input and the winning route when a route
+	// matches.
+	OnMatch func(input string, m ListMatch)
+	// OnMiss is called with the input when no route matches, including
+	// when the URL matches an exclusion (see Exclude).
+	OnMiss func(input string)
+}
+
+// SetHooks sets the callbacks invoked when matching URLs against the list.
+func (l *URLPatternList) SetHooks(h ListHooks) {
+	l.hooks = h
+}
+
+// notify calls the hook matching the result of a match of input.
+func (l *URLPatternList) notify(input string, index int, result *URLPatternResult) {
+	switch {
+	case index == -1 && l.hooks.OnMiss != nil:
+		l.hooks.OnMiss(input)
+	case index != -1 && l.hooks.OnMatch != nil:
+		l.hooks.OnMatch(input, ListMatch{Index: index, Route: l.routes[index], Result: result})
+	}
+}
@@ -0,0 +1,56 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestListHooks(t *testing.T) {
+	list := &urlpattern.URLPatternList{}
+	for name, p := range map[string]string{"admin": "https://example.com/admin/*", "public": "https://example.com/public/*"} {
+		u, err := urlpattern.Compile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list.AddRoute(urlpattern.Route{Pattern: u, Name: name})
+	}
+
+	excluded, err := urlpattern.Compile("https://example.com/admin/secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Exclude(excluded)
+
+	var matches, misses []string
+	list.SetHooks(urlpattern.ListHooks{
+		OnMatch: func(input string, m urlpattern.ListMatch) {
+			if m.Result == nil || list.Routes()[m.Index].Name != m.Route.Name {
+				t.Errorf("unexpected match %#v", m)
+			}
+			matches = append(matches, m.Route.Name+" "+input)
+		},
+		OnMiss: func(input string) {
+			misses = append(misses, input)
+		},
+	})
+
+	list.Match("https://example.com/admin/users")
+	list.MatchRoute("https://example.com/public/index.html")
+	list.Exec("https://example.com/admin/secret")
+	list.Exec("https://example.com/private")
+	list.MatchAll("https://example.com/public/index.html")
+
+	if len(matches) != 2 || matches[0] != "admin https://example.com/admin/users" || matches[1] != "public https://example.com/public/index.html" {
+		t.Errorf("unexpected matches %q", matches)
+	}
+	if len(misses) != 2 || misses[0] != "https://example.com/admin/secret" || misses[1] != "https://example.com/private" {
+		t.Errorf("unexpected misses %q", misses)
+	}
+
+	list.SetHooks(urlpattern.ListHooks{})
+	list.Match("https://example.com/private")
+	if len(misses) != 2 {
+		t.Error("hooks must be removable")
+	}
+}
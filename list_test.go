@@ -0,0 +1,190 @@
+package urlpattern_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestURLPatternList(t *testing.T) {
+	var list urlpattern.URLPatternList
+
+	patterns := make(map[string]*urlpattern.URLPattern)
+	for _, p := range []string{
+		"https://example.com/",
+		"https://example.com/books",
+		"https://example.com/books/:id",
+		"https://example.com/books/:id/reviews{/:review}?",
+		"https://example.com/authors/*",
+		"https://example.com/:lang/about",
+		"https://example.com/files/report-*.pdf",
+		"https://example.com/CASE/*",
+		"https://example.com/*",
+	} {
+		opts := []urlpattern.Option{}
+		if p == "https://example.com/CASE/*" {
+			opts = append(opts, urlpattern.WithIgnoreCase())
+		}
+
+		pattern, err := urlpattern.Compile(p, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		patterns[p] = pattern
+		list.Add(pattern)
+	}
+
+	if list.Len() != 9 || list.Patterns()[2] != patterns["https://example.com/books/:id"] {
+		t.Errorf("unexpected patterns %v", list.Patterns())
+	}
+
+	for input, want := range map[string]string{
+		"https://example.com/":                      "https://example.com/",
+		"https://example.com/books":                 "https://example.com/books",
+		"https://example.com/books/":                "https://example.com/*",
+		"https://example.com/books/42":              "https://example.com/books/:id",
+		"https://example.com/books/42/reviews":      "https://example.com/books/:id/reviews{/:review}?",
+		"https://example.com/books/42/reviews/7":    "https://example.com/books/:id/reviews{/:review}?",
+		"https://example.com/authors/victor-hugo":   "https://example.com/authors/*",
+		"https://example.com/fr/about":              "https://example.com/:lang/about",
+		"https://example.com/files/report-2024.pdf": "https://example.com/files/report-*.pdf",
+		"https://example.com/case/foo":              "https://example.com/CASE/*",
+		"https://example.com/unknown":               "https://example.com/*",
+		"https://example.org/books/42":              "",
+	} {
+		t.Run(input, func(t *testing.T) {
+			pattern, result := list.Match(input)
+			if pattern != patterns[want] {
+				t.Fatalf("want %q; got %v", want, pattern)
+			}
+
+			if (result == nil) != (want == "") {
+				t.Errorf("unexpected result %#v", result)
+			}
+		})
+	}
+}
+
+func TestURLPatternListLarge(t *testing.T) {
+	var list urlpattern.URLPatternList
+	for i := range 1000 {
+		pattern, err := urlpattern.Compile(fmt.Sprintf("https://example.com/resources%d/:id", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		list.Add(pattern)
+	}
+
+	pattern, result := list.Match("https://example.com/resources999/42")
+	if pattern == nil || pattern.Pathname() != "/resources999/:id" || result.Pathname.Groups["id"] != "42" {
+		t.Errorf("unexpected match %v %#v", pattern, result)
+	}
+}
+
+func ExampleURLPatternList_MatchRoute() {
+	type cacheRule struct{ maxAge int }
+
+	var list urlpattern.URLPatternList
+	for pattern, rule := range map[string]cacheRule{
+		"https://example.com/static/*": {maxAge: 86400},
+		"https://example.com/api/*":    {maxAge: 0},
+	} {
+		p, err := urlpattern.Compile(pattern)
+		if err != nil {
+			panic(err)
+		}
+		list.AddWithMetadata(p, rule)
+	}
+
+	route, _ := list.MatchRoute("https://example.com/static/app.css")
+	fmt.Println(route.Metadata.(cacheRule).maxAge)
+
+	if _, result := list.MatchRoute("https://example.com/about"); result == nil {
+		fmt.Println("no rule")
+	}
+
+	// Output: 86400
+	// no rule
+}
+
+func TestURLPatternListExclude(t *testing.T) {
+	var list urlpattern.URLPatternList
+
+	all, err := urlpattern.Compile("https://example.com/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Add(all)
+
+	admin, err := urlpattern.Compile("*://*/admin/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Exclude(admin)
+
+	if p, _ := list.Match("https://example.com/books/1"); p != all {
+		t.Errorf("want match; got %v", p)
+	}
+	if p, r := list.Match("https://example.com/admin/users"); p != nil || r != nil {
+		t.Errorf("want no match; got %v", p)
+	}
+	if m := list.MatchAll("https://example.com/admin/users"); m != nil {
+		t.Errorf("want no match; got %v", m)
+	}
+
+	list.SetStrategy(urlpattern.StrategyMostSpecific)
+	if route, r := list.MatchRoute("https://example.com/admin/users"); r != nil {
+		t.Errorf("want no match; got %v", route)
+	}
+
+	if list.Len() != 1 || len(list.Exclusions()) != 1 {
+		t.Errorf("unexpected lengths %d and %d", list.Len(), len(list.Exclusions()))
+	}
+}
+
+func TestURLPatternListExec(t *testing.T) {
+	var list urlpattern.URLPatternList
+
+	for i, pattern := range []string{"https://example.com/authors/:id", "https://example.com/books/:id"} {
+		p, err := urlpattern.Compile(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list.AddRoute(urlpattern.Route{Pattern: p, Name: fmt.Sprint("route", i), Metadata: i})
+	}
+
+	m := list.Exec("https://example.com/books/1")
+	if m == nil {
+		t.Fatal("want match")
+	}
+	if m.Index != 1 || m.Route.Name != "route1" || m.Route.Metadata != 1 || m.Route.Pattern != list.Patterns()[1] || m.Result.Pathname.Groups["id"] != "1" {
+		t.Errorf("unexpected match %#v", m)
+	}
+
+	if m := list.MatchAll("https://example.com/authors/1"); len(m) != 1 || m[0].Index != 0 {
+		t.Errorf("unexpected matches %#v", m)
+	}
+
+	if m := list.Exec("https://example.com/about"); m != nil {
+		t.Errorf("want no match; got %#v", m)
+	}
+}
+
+func TestURLPatternListExtremePriorities(t *testing.T) {
+	var list urlpattern.URLPatternList
+	for _, priority := range []int{-2, math.MaxInt, math.MinInt} {
+		p, err := urlpattern.Compile("https://example.com/books/*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		list.AddRoute(urlpattern.Route{Pattern: p, Priority: priority})
+	}
+
+	if route, _ := list.MatchRoute("https://example.com/books/1"); route.Priority != math.MaxInt {
+		t.Errorf("want the route with the highest priority; got %d", route.Priority)
+	}
+}
@@ -0,0 +1,90 @@
+package urlpattern
+
+import (
+	"net/http"
+	"slices"
+)
+
+// MethodPattern pairs a URLPattern with the set of HTTP methods it applies
+// to, as routers do.
+type MethodPattern struct {
+	pattern *URLPattern
+	methods []string
+}
+
+// NewMethodPattern creates a MethodPattern matching the requests using one
+// of methods. Methods are case-sensitive. A pattern matching GET also
+// matches HEAD, like http.ServeMux. Without methods, every method matches.
+func NewMethodPattern(pattern *URLPattern, methods ...string) *MethodPattern {
+	return &MethodPattern{pattern: pattern, methods: slices.Clone(methods)}
+}
+
+// Pattern returns the URL pattern.
+func (m *MethodPattern) Pattern() *URLPattern {
+	return m.pattern
+}
+
+// Methods returns the methods of the pattern, or nil if every method matches.
+func (m *MethodPattern) Methods() []string {
+	return slices.Clone(m.methods)
+}
+
+// MatchesMethod reports whether method is one of the methods of the pattern.
+func (m *MethodPattern) MatchesMethod(method string) bool {
+	if len(m.methods) == 0 || slices.Contains(m.methods, method) {
+		return true
+	}
+
+	return method == http.MethodHead && slices.Contains(m.methods, http.MethodGet)
+}
+
+// Match returns the result of matching input against the URL pattern, or nil
+// if it doesn't match or if method is not one of the methods of the pattern.
+func (m *MethodPattern) Match(method, input string) *URLPatternResult {
+	if !m.MatchesMethod(method) {
+		return nil
+	}
+
+	return m.pattern.Exec(input, "")
+}
+
+// MethodPatterns is an ordered collection of MethodPattern.
+type MethodPatterns []*MethodPattern
+
+// Match returns the first pattern matching method and input, and the result
+// of the match. If no pattern matches, it returns nil and a nil result.
+func (ms MethodPatterns) Match(method, input string) (*MethodPattern, *URLPatternResult) {
+	for _, m := range ms {
+		if r := m.Match(method, input); r != nil {
+			return m, r
+		}
+	}
+
+	return nil, nil
+}
+
+// Allowed returns the methods of the patterns matching input regardless of
+// the method, in order and without duplicates, e.g. to fill the Allow header
+// of a 405 Method Not Allowed response. It returns nil if a matching pattern
+// accepts every method.
+func (ms MethodPatterns) Allowed(input string) []string {
+	var allowed []string
+
+	for _, m := range ms {
+		if !m.pattern.Test(input, "") {
+			continue
+		}
+
+		if len(m.methods) == 0 {
+			return nil
+		}
+
+		for _, method := range m.methods {
+			if !slices.Contains(allowed, method) {
+				allowed = append(allowed, method)
+			}
+		}
+	}
+
+	return allowed
+}
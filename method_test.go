@@ -0,0 +1,55 @@
+package urlpattern_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestMethodPatterns(t *testing.T) {
+	books, err := urlpattern.Compile("https://example.com/books/:id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := urlpattern.Compile("https://example.com/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	show := urlpattern.NewMethodPattern(books, http.MethodGet)
+	update := urlpattern.NewMethodPattern(books, http.MethodPut, http.MethodPatch)
+	options := urlpattern.NewMethodPattern(all, http.MethodOptions)
+	patterns := urlpattern.MethodPatterns{show, update, options}
+
+	for _, tc := range []struct {
+		method string
+		input  string
+		want   *urlpattern.MethodPattern
+	}{
+		{http.MethodGet, "https://example.com/books/1", show},
+		{http.MethodHead, "https://example.com/books/1", show},
+		{http.MethodPatch, "https://example.com/books/1", update},
+		{http.MethodOptions, "https://example.com/books/1", options},
+		{http.MethodDelete, "https://example.com/books/1", nil},
+		{http.MethodGet, "https://example.com/authors/1", nil},
+	} {
+		t.Run(tc.method+" "+tc.input, func(t *testing.T) {
+			m, r := patterns.Match(tc.method, tc.input)
+			if m != tc.want {
+				t.Fatalf("want %v; got %v", tc.want, m)
+			}
+
+			if m != nil && m.Pattern() == books && r.Pathname.Groups["id"] != "1" {
+				t.Errorf("unexpected result %#v", r)
+			}
+		})
+	}
+
+	want := []string{http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodOptions}
+	if got := patterns.Allowed("https://example.com/books/1"); !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v; got %v", want, got)
+	}
+}
@@ -0,0 +1,167 @@
+package urlpattern
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds of the match latency histogram
+// used by NewListMetrics when no bucket is given.
+var DefaultLatencyBuckets = []time.Duration{
+	time.Microsecond,
+	5 * time.Microsecond,
+	10 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+}
+
+// ListMetrics counts the matches of each route of a URLPatternList, the
+// misses, and records a histogram of the match latency, so operators can see
+// which routes are hot and which never match.
+//
+// ListMetrics implements expvar.Var and can be published with
+// expvar.Publish. A ListMetrics must only instrument a single list.
+type ListMetrics struct {
+	mu     sync.RWMutex
+	routes []*routeMetrics
+
+	misses  atomic.Uint64
+	buckets []time.Duration
+	// counts holds the number of observations per bucket, the last one
+	// being for observations greater than all the bucket bounds.
+	counts []atomic.Uint64
+	count  atomic.Uint64
+	sum    atomic.Int64
+}
+
+type routeMetrics struct {
+	name    string
+	matches atomic.Uint64
+}
+
+// MetricsSnapshot is a snapshot of ListMetrics.
+type MetricsSnapshot struct {
+	// Matches is the number of matches per route, identified by its name
+	// or, for unnamed routes, by "#" followed by its index.
+	Matches map[string]uint64 `json:"matches"`
+	Misses  uint64            `json:"misses"`
+	Latency LatencySnapshot   `json:"latency"`
+}
+
+// LatencySnapshot is a snapshot of a match latency histogram.
+type LatencySnapshot struct {
+	// Buckets are the cumulative counts of matches per upper bound, as
+	// Prometheus histograms. The last bucket, the +Inf bucket of Prometheus,
+	// has an upper bound of math.MaxInt64 and counts all the matches.
+	Buckets []LatencyBucket `json:"buckets"`
+	Count   uint64          `json:"count"`
+	Sum     time.Duration   `json:"sum"`
+}
+
+// LatencyBucket is a bucket of a latency histogram.
+type LatencyBucket struct {
+	UpperBound time.Duration `json:"upperBound"`
+	Count      uint64        `json:"count"`
+}
+
+// NewListMetrics creates a ListMetrics with the given latency histogram
+// buckets, in increasing order, or DefaultLatencyBuckets if none is given.
+func NewListMetrics(buckets ...time.Duration) *ListMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	return &ListMetrics{
+		buckets: buckets,
+		counts:  make([]atomic.Uint64, len(buckets)+1),
+	}
+}
+
+// SetMetrics instruments the list with m. Pass nil to disable metrics.
+func (l *URLPatternList) SetMetrics(m *ListMetrics) {
+	l.metrics = m
+	if m == nil {
+		return
+	}
+
+	for i := range l.routes {
+		m.addRoute(i, l.routes[i].Name)
+	}
+}
+
+func (m *ListMetrics) addRoute(index int, name string) {
+	if name == "" {
+		name = "#" + strconv.Itoa(index)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.routes = append(m.routes[:index], &routeMetrics{name: name})
+}
+
+// observe records a match of the route at index, or a miss if index is -1.
+func (m *ListMetrics) observe(index int, elapsed time.Duration) {
+	if index == -1 {
+		m.misses.Add(1)
+	} else {
+		m.mu.RLock()
+		m.routes[index].matches.Add(1)
+		m.mu.RUnlock()
+	}
+
+	i := 0
+	for i < len(m.buckets) && elapsed > m.buckets[i] {
+		i++
+	}
+
+	m.counts[i].Add(1)
+	m.count.Add(1)
+	m.sum.Add(int64(elapsed))
+}
+
+// Snapshot returns the current values of the metrics.
+func (m *ListMetrics) Snapshot() MetricsSnapshot {
+	m.mu.RLock()
+	matches := make(map[string]uint64, len(m.routes))
+	for _, r := range m.routes {
+		matches[r.name] += r.matches.Load()
+	}
+	m.mu.RUnlock()
+
+	latency := LatencySnapshot{
+		Buckets: make([]LatencyBucket, len(m.counts)),
+		Count:   m.count.Load(),
+		Sum:     time.Duration(m.sum.Load()),
+	}
+
+	var cumulative uint64
+	for i := range m.counts {
+		cumulative += m.counts[i].Load()
+		latency.Buckets[i].Count = cumulative
+
+		if i < len(m.buckets) {
+			latency.Buckets[i].UpperBound = m.buckets[i]
+		} else {
+			latency.Buckets[i].UpperBound = time.Duration(math.MaxInt64)
+		}
+	}
+
+	return MetricsSnapshot{Matches: matches, Misses: m.misses.Load(), Latency: latency}
+}
+
+// String returns the snapshot of the metrics as JSON, implementing
+// expvar.Var.
+func (m *ListMetrics) String() string {
+	b, _ := json.Marshal(m.Snapshot())
+
+	return string(b)
+}
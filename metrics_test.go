@@ -0,0 +1,52 @@
+package urlpattern_test
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestListMetrics(t *testing.T) {
+	books, err := urlpattern.Compile("https://example.com/books/:id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authors, err := urlpattern.Compile("https://example.com/authors/:id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var list urlpattern.URLPatternList
+	list.AddRoute(urlpattern.Route{Pattern: books, Name: "book"})
+
+	metrics := urlpattern.NewListMetrics(time.Hour)
+	list.SetMetrics(metrics)
+	list.Add(authors)
+
+	list.Match("https://example.com/books/1")
+	list.Match("https://example.com/books/2")
+	list.Match("https://example.com/publishers/1")
+
+	snapshot := metrics.Snapshot()
+	if want := map[string]uint64{"book": 2, "#1": 0}; !reflect.DeepEqual(want, snapshot.Matches) {
+		t.Errorf("want %v; got %v", want, snapshot.Matches)
+	}
+
+	if snapshot.Misses != 1 || snapshot.Latency.Count != 3 || snapshot.Latency.Sum <= 0 {
+		t.Errorf("unexpected snapshot %#v", snapshot)
+	}
+
+	if want := []urlpattern.LatencyBucket{{UpperBound: time.Hour, Count: 3}, {UpperBound: math.MaxInt64, Count: 3}}; !reflect.DeepEqual(want, snapshot.Latency.Buckets) {
+		t.Errorf("want %v; got %v", want, snapshot.Latency.Buckets)
+	}
+
+	var decoded urlpattern.MetricsSnapshot
+	if err := json.Unmarshal([]byte(metrics.String()), &decoded); err != nil || !reflect.DeepEqual(decoded, snapshot) {
+		t.Errorf("unexpected JSON %s (%v)", metrics.String(), err)
+	}
+}
@@ -0,0 +1,107 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidRouteTemplate is returned by FromGorillaRoute and FromChi for
+// invalid or unsupported route templates.
+var ErrInvalidRouteTemplate = errors.New("invalid route template")
+
+// FromGorillaRoute converts a gorilla/mux path template, such as
+// "/articles/{category}/{id:[0-9]+}", to an equivalent URLPattern matching any
+// protocol, hostname, search and hash, to migrate existing services
+// incrementally.
+//
+// Variables are converted to named groups: "{id:[0-9]+}" to ":id([0-9]+)",
+// and "{category}" to ":category", or ":category([^/]+)" if the variable
+// doesn't span a whole segment, as gorilla/mux variables are greedy.
+func FromGorillaRoute(path string, opts ...Option) (*URLPattern, error) {
+	return fromBraceTemplate(path, false, opts)
+}
+
+// FromChi converts a chi route pattern, such as "/users/{userID}" or
+// "/files/*", to an equivalent URLPattern matching any protocol, hostname,
+// search and hash, to migrate existing services incrementally.
+//
+// Parameters are converted to named groups: "{id:\d+}" to ":id(\d+)" and
+// "{userID}" to ":userID". The final "*" wildcard is kept, its value is in the
+// "0" group.
+func FromChi(pattern string, opts ...Option) (*URLPattern, error) {
+	return fromBraceTemplate(pattern, true, opts)
+}
+
+// fromBraceTemplate converts the route templates of gorilla/mux and chi,
+// allowing a final "*" wildcard if wildcard is set.
+func fromBraceTemplate(template string, wildcard bool, opts []Option) (*URLPattern, error) {
+	if !strings.HasPrefix(template, "/") {
+		return nil, fmt.Errorf("%w: %q must start with /", ErrInvalidRouteTemplate, template)
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(template); {
+		switch c := template[i]; {
+		case c == '{':
+			end := closingBrace(template, i)
+			if end == -1 {
+				return nil, fmt.Errorf("%w: unbalanced braces in %q", ErrInvalidRouteTemplate, template)
+			}
+
+			name, re, hasRegexp := strings.Cut(template[i+1:end], ":")
+			if !isValidName(name) {
+				return nil, fmt.Errorf("%w: invalid variable name %q", ErrInvalidRouteTemplate, name)
+			}
+
+			group := ":" + name
+			wholeSegment := template[i-1] == '/' && (end+1 == len(template) || template[end+1] == '/')
+			switch {
+			case hasRegexp:
+				group += "(" + re + ")"
+			case !wildcard && !wholeSegment:
+				group += "([^/]+)"
+			case end+1 < len(template) && isValidNameCodePoint(firstCodePoint(template[end+1:]), false):
+				// Prevent the following text from being part of the name.
+				group = "{" + group + "}"
+			}
+			b.WriteString(group)
+
+			i = end + 1
+
+		case c == '}':
+			return nil, fmt.Errorf("%w: unbalanced braces in %q", ErrInvalidRouteTemplate, template)
+
+		case c == '*' && wildcard && i == len(template)-1:
+			b.WriteByte('*')
+			i++
+
+		default:
+			b.WriteString(EscapePatternString(template[i : i+1]))
+			i++
+		}
+	}
+
+	pathname := b.String()
+
+	return Compile(&URLPatternInit{Pathname: &pathname}, opts...)
+}
+
+// closingBrace returns the index of the brace closing the one at start in s,
+// or -1. Braces may be nested in regular expressions, e.g. "{id:[0-9]{3}}".
+func closingBrace(s string, start int) int {
+	var depth int
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
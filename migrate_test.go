@@ -0,0 +1,51 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestFromGorillaRouteAndChi(t *testing.T) {
+	for _, tc := range []struct {
+		name, template string
+		convert        func(string, ...urlpattern.Option) (*urlpattern.URLPattern, error)
+		pathname       string
+		input          string
+		groups         map[string]string
+	}{
+		{"gorilla", "/articles/{category}/{id:[0-9]+}", urlpattern.FromGorillaRoute, "/articles/:category/:id([0-9]+)", "/articles/tech/42", map[string]string{"category": "tech", "id": "42"}},
+		{"gorilla partial segment", "/files/{name}-{version}.tar.gz", urlpattern.FromGorillaRoute, "/files/:name([^/]+)-:version([^/]+).tar.gz", "/files/go-1-2.tar.gz", map[string]string{"name": "go-1", "version": "2"}},
+		{"gorilla nested braces", "/codes/{code:[A-Z]{3}}", urlpattern.FromGorillaRoute, "/codes/:code([A-Z]{3})", "/codes/ABC", map[string]string{"code": "ABC"}},
+		{"chi", "/users/{userID}", urlpattern.FromChi, "/users/:userID", "/users/42", map[string]string{"userID": "42"}},
+		{"chi suffix", "/users/{id}x/{n:\\d+}", urlpattern.FromChi, "/users/{:id}x/:n(\\d+)", "/users/1x/2", map[string]string{"id": "1", "n": "2"}},
+		{"chi wildcard", "/static/*", urlpattern.FromChi, "/static/*", "/static/css/app.css", map[string]string{"0": "css/app.css"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pattern, err := tc.convert(tc.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if pattern.Pathname() != tc.pathname {
+				t.Errorf("want pathname %q; got %q", tc.pathname, pattern.Pathname())
+			}
+
+			r := pattern.Exec("https://example.com"+tc.input, "")
+			if r == nil {
+				t.Fatal("want match")
+			}
+			if !reflect.DeepEqual(tc.groups, r.Pathname.Groups) {
+				t.Errorf("want %#v; got %#v", tc.groups, r.Pathname.Groups)
+			}
+		})
+	}
+
+	for _, template := range []string{"users", "/users/{id", "/users/id}", "/users/{user-id}"} {
+		if _, err := urlpattern.FromGorillaRoute(template); !errors.Is(err, urlpattern.ErrInvalidRouteTemplate) {
+			t.Errorf("%s: want ErrInvalidRouteTemplate; got %v", template, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package urlpattern
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// WithNFC applies Unicode Normalization Form C to the username, password,
+// pathname, search and hash of patterns and matched URLs, so that visually
+// identical but differently composed text (e.g. "é" as U+00E9 or as "e"
+// followed by U+0301) matches, whether it is percent-encoded or not.
+//
+// Hostnames are always normalized by IDNA processing.
+func WithNFC() Option {
+	return func(c *config) {
+		c.normalizeNFC = true
+	}
+}
+
+// normalizeNFC normalizes the canonicalized component s to NFC. Non-ASCII
+// code points are percent-encoded in canonicalized components, so they are
+// decoded before normalization and encoded again afterwards; ASCII
+// percent-encodings are preserved.
+func normalizeNFC(s string) string {
+	if !strings.Contains(s, "%") {
+		if norm.NFC.IsNormalString(s) {
+			return s
+		}
+
+		return norm.NFC.String(s)
+	}
+
+	var decoded strings.Builder
+	decoded.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && isHexDigit(s[i+1]) && isHexDigit(s[i+2]) {
+			if c := unhex(s[i+1])<<4 | unhex(s[i+2]); c >= 0x80 {
+				decoded.WriteByte(c)
+				i += 2
+
+				continue
+			}
+		}
+
+		decoded.WriteByte(s[i])
+	}
+
+	d := decoded.String()
+	if norm.NFC.IsNormalString(d) {
+		return s
+	}
+
+	const upperHexDigits = "0123456789ABCDEF"
+
+	n := norm.NFC.String(d)
+	var result strings.Builder
+	result.Grow(len(s))
+	for i := range len(n) {
+		if c := n[i]; c >= 0x80 {
+			result.WriteByte('%')
+			result.WriteByte(upperHexDigits[c>>4])
+			result.WriteByte(upperHexDigits[c&0xF])
+		} else {
+			result.WriteByte(c)
+		}
+	}
+
+	return result.String()
+}
@@ -0,0 +1,49 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestNFC(t *testing.T) {
+	// "café" with a precomposed "é" (U+00E9) in the pattern.
+	pattern, err := urlpattern.Compile("https://example.com/café/:name", urlpattern.WithNFC())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range []string{
+		"https://example.com/café/x",
+		"https://example.com/café/x",
+		"https://example.com/cafe%CC%81/x",
+	} {
+		r := pattern.Exec(input, "")
+		if r == nil {
+			t.Errorf("want %q to match", input)
+
+			continue
+		}
+
+		if want := "/caf%C3%A9/x"; r.Pathname.Input != want {
+			t.Errorf("want %q; got %q", want, r.Pathname.Input)
+		}
+	}
+
+	// Decomposed pattern.
+	pattern, err = urlpattern.Compile("https://example.com/café", urlpattern.WithNFC())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/caf%C3%A9"; pattern.Pathname() != want {
+		t.Errorf("want %q; got %q", want, pattern.Pathname())
+	}
+
+	pattern, err = urlpattern.Compile("https://example.com/café")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pattern.Test("https://example.com/café", "") {
+		t.Error("want no match without NFC")
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !race
+
+package urlpattern_test
+
+// raceEnabled reports whether the race detector, which makes allocation
+// counts unreliable, is enabled.
+const raceEnabled = false
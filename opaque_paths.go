@@ -0,0 +1,49 @@
+package urlpattern
+
+import "errors"
+
+// ErrInvalidOpaquePathDelimiter is returned by Compile when the delimiter
+// passed to WithOpaquePathDelimiter isn't a printable ASCII character.
+var ErrInvalidOpaquePathDelimiter = errors.New("invalid opaque path delimiter")
+
+// WithOpaquePathDelimiter splits the opaque paths of URLs whose scheme isn't
+// special, such as "data:", "mailto:" or "urn:" URLs, into segments separated
+// by delim, like pathnames are split on "/".
+//
+// By default, opaque paths have no segments: named groups match any
+// non-empty text, and groups aren't automatically prefixed. With delim set to
+// ',', named groups match up to the next ',' and ',' is the automatic prefix
+// of groups, so that data URLs can be matched with:
+//
+//	pattern, err := urlpattern.Compile("data::type,:payload*", urlpattern.WithOpaquePathDelimiter(','))
+//
+// ":type" matches "text/plain;base64" but not "text/plain,hello", and
+// ":payload*" matches the remaining comma-separated segments, if any, like
+// ":rest*" in a pathname. Likewise, ',' splits the recipients of "mailto:"
+// URLs, and ':' the components of URNs (`urn::nid{\::nss}+`).
+//
+// The pathnames of URLs with a special scheme are unaffected. Compile returns
+// ErrInvalidOpaquePathDelimiter if delim isn't a printable ASCII character.
+func WithOpaquePathDelimiter(delim byte) Option {
+	return func(c *config) {
+		if delim <= ' ' || delim >= 0x7f {
+			c.setErr(ErrInvalidOpaquePathDelimiter)
+
+			return
+		}
+
+		c.opaquePathDelimiter = delim
+	}
+}
+
+// opaquePathnameOptions returns the options used to compile the pathname
+// component of patterns whose protocol doesn't match a special scheme.
+func (c *config) opaquePathnameOptions() options {
+	o := options{ignoreCase: c.ignoreCase}
+	if c.opaquePathDelimiter != 0 {
+		o.delimiterCodePoint = c.opaquePathDelimiter
+		o.prefixCodePoint = c.opaquePathDelimiter
+	}
+
+	return o
+}
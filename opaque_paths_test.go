@@ -0,0 +1,72 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestOpaquePaths(t *testing.T) {
+	for _, tt := range []struct {
+		pattern string
+		delim   byte
+		input   string
+		groups  map[string]string
+	}{
+		{"data::type,:payload", 0, "data:text/plain,a,b", map[string]string{"type": "text/plain", "payload": "a,b"}},
+		{"data::type,:payload*", ',', "data:text/plain;base64,SGVsbG8=", map[string]string{"type": "text/plain;base64", "payload": "SGVsbG8="}},
+		{"data::type,:payload*", ',', "data:text/plain,a,b", map[string]string{"type": "text/plain", "payload": "a,b"}},
+		{"data::type,:payload*", ',', "data:text/plain", map[string]string{"type": "text/plain", "payload": ""}},
+		{"mailto::to", 0, "mailto:a@example.com,b@example.com", map[string]string{"to": "a@example.com,b@example.com"}},
+		{"mailto::first,:others*", ',', "mailto:a@example.com,b@example.com,c@example.com", map[string]string{"first": "a@example.com", "others": "b@example.com,c@example.com"}},
+		{`urn::nid{\::nss}+`, ':', "urn:isbn:0451450523", map[string]string{"nid": "isbn", "nss": "0451450523"}},
+		{`urn::nid{\::nss}+`, ':', "urn:ietf:rfc:2648", map[string]string{"nid": "ietf", "nss": "rfc:2648"}},
+		{"urn\\:isbn\\::isbn", ':', "urn:isbn:0451450523", map[string]string{"isbn": "0451450523"}},
+	} {
+		var opts []urlpattern.Option
+		if tt.delim != 0 {
+			opts = append(opts, urlpattern.WithOpaquePathDelimiter(tt.delim))
+		}
+
+		u, err := urlpattern.Compile(tt.pattern, opts...)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.pattern, err)
+		}
+
+		r := u.Exec(tt.input, "")
+		if r == nil {
+			t.Errorf("%s (%q) must match %q", tt.pattern, tt.delim, tt.input)
+
+			continue
+		}
+
+		for name, value := range tt.groups {
+			if r.Pathname.Groups[name] != value {
+				t.Errorf("%s (%q): %s = %q for %q, want %q", tt.pattern, tt.delim, name, r.Pathname.Groups[name], tt.input, value)
+			}
+		}
+	}
+
+	u, err := urlpattern.Compile("data::type,:payload", urlpattern.WithOpaquePathDelimiter(','))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Test("data:text/plain,a,b", "") {
+		t.Error("groups must not match the delimiter")
+	}
+
+	u, err = urlpattern.Compile("https://example.com/:a,:b", urlpattern.WithOpaquePathDelimiter(','))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := u.Exec("https://example.com/a,b,c", ""); r == nil || r.Pathname.Groups["a"] != "a" || r.Pathname.Groups["b"] != "b,c" {
+		t.Errorf("special schemes must be unaffected, got %#v", r)
+	}
+
+	for _, delim := range []byte{0, ' ', 0x7f, 0x80} {
+		if _, err := urlpattern.Compile("data:*", urlpattern.WithOpaquePathDelimiter(delim)); !errors.Is(err, urlpattern.ErrInvalidOpaquePathDelimiter) {
+			t.Errorf("%q: unexpected error %v", delim, err)
+		}
+	}
+}
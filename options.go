@@ -6,4 +6,120 @@ type options struct {
 	delimiterCodePoint byte
 	prefixCodePoint    byte
 	ignoreCase         bool
+
+	// maxRepetitions bounds the repetitions of the groups with a
+	// one-or-more or zero-or-more modifier, see WithMaxRepetitions.
+	maxRepetitions int
+}
+
+// Option configures a URLPattern created with Compile.
+type Option func(*config)
+
+// config holds the settings applied by Option functions.
+type config struct {
+	baseURL    *string
+	ignoreCase bool
+	idnaMode   IDNAMode
+	portRanges bool
+
+	ipHostnames bool
+
+	constraints bool
+
+	wildcardNames string
+
+	maxRepetitions int
+
+	opaquePathDelimiter byte
+
+	groupValidators []groupValidator
+	resultMappers   map[string][]ResultMapper
+
+	searchParams *SearchParamsPattern
+
+	ignoreSearch bool
+	ignoreHash   bool
+
+	strictCompat bool
+	compatReport func(CompatDivergence)
+
+	normalizePercentEncoding bool
+	normalizeNFC             bool
+
+	tracer Tracer
+
+	urlParser URLParser
+
+	// err is the first error reported by an Option, returned by Compile.
+	err error
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
+// setErr records err, unless an error has already been recorded.
+func (c *config) setErr(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// WithBaseURL sets the URL against which relative constructor strings are
+// resolved, like the baseURL parameter of New.
+func WithBaseURL(baseURL string) Option {
+	return func(c *config) {
+		c.baseURL = &baseURL
+	}
+}
+
+// WithIgnoreCase enables case-insensitive matching, like Options.IgnoreCase.
+//
+// https://urlpattern.spec.whatwg.org/#dom-urlpatternoptions-ignorecase
+func WithIgnoreCase() Option {
+	return func(c *config) {
+		c.ignoreCase = true
+	}
+}
+
+// normalizes reports whether c enables normalizations of the username,
+// password, pathname, search and hash components.
+func (c *config) normalizes() bool {
+	return c.normalizeNFC || c.normalizePercentEncoding
+}
+
+// normalize applies the normalizations enabled in c to a canonicalized
+// username, password, pathname, search or hash.
+func (c *config) normalize(s string) string {
+	if c.normalizeNFC {
+		s = normalizeNFC(s)
+	}
+
+	if c.normalizePercentEncoding {
+		s = normalizePercentEncoding(s)
+	}
+
+	return s
+}
+
+// encoding returns the encoding callback to use for a component: cb, followed
+// by the normalizations enabled in c.
+func (c *config) encoding(cb encodingCallback) encodingCallback {
+	if !c.normalizes() {
+		return cb
+	}
+
+	return func(s string) (string, error) {
+		s, err := cb(s)
+		if err != nil {
+			return "", err
+		}
+
+		return c.normalize(s), nil
+	}
 }
@@ -3,7 +3,6 @@ package urlpattern
 import (
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"unicode"
 
@@ -14,30 +13,17 @@ import (
 // https://urlpattern.spec.whatwg.org/#full-wildcard-regexp-value
 const fullWildcardRegexpValue = ".*"
 
-// DefaultPorts maps a protocol scheme to its default port string.
-//
-// Callers may add entries for additional schemes, but only during program
-// initialization and before any concurrent use of this package: as with
-// any Go map, concurrent read/write is not safe.
-//
-// This is an experimental affordance and the symbol may change or be
-// removed in a future release.
-//
-// TODO: there is nothing in the Go stdlib to find the default port
-// associated with a protocol. Only the specialSchemeSet entries are
-// populated for now; the list can be completed using
-// https://en.wikipedia.org/wiki/List_of_TCP_and_UDP_port_numbers.
-var DefaultPorts = map[string]string{
-	"http":  "80",
-	"https": "443",
-	"ws":    "80",
-	"wss":   "443",
-	"ftp":   "21",
-}
-
 var urlParser = url.NewParser()
 var hostnameParser = canonicalizer.New(canonicalizer.WithDefaultScheme("http"))
 
+// Parsers keeping hostnames rejected by IDNA as is, see IDNALax.
+var laxURLParser = url.NewParser(url.WithLaxHostParsing())
+var laxHostnameParser = canonicalizer.New(canonicalizer.WithDefaultScheme("http"), url.WithLaxHostParsing())
+
+// Parsers keeping hostnames verbatim, see IDNANone.
+var verbatimURLParser = url.NewParser(url.WithPreParseHostFunc(encodeVerbatimHost))
+var verbatimHostnameParser = canonicalizer.New(canonicalizer.WithDefaultScheme("http"), url.WithPreParseHostFunc(encodeVerbatimHost))
+
 var (
 	ErrNonEmptySuffix      = errors.New("suffix must be the empty string")
 	ErrBadParserIndex      = errors.New("parser's index must be less than parser's token list size")
@@ -52,6 +38,13 @@ var errInvalidHostname = errors.New("invalid hostname")
 // https://urlpattern.spec.whatwg.org/#encoding-callback
 type encodingCallback func(string) (string, error)
 
+// withParser returns the encoding callback running canonicalize with p.
+func withParser(p URLParser, canonicalize func(URLParser, string) (string, error)) encodingCallback {
+	return func(value string) (string, error) {
+		return canonicalize(p, value)
+	}
+}
+
 // https://urlpattern.spec.whatwg.org/#parse-a-pattern-string
 func parsePatternString(input string, options options, encodingCallback encodingCallback) (partList, error) {
 	tl, err := tokenize(input, tokenizePolicyStrict)
@@ -184,7 +177,7 @@ type patternParser struct {
 	seenNames             map[string]struct{}
 	pendingFixedValue     string
 	index                 int
-	nextNumericName       float64
+	nextNumericName       int
 }
 
 // https://urlpattern.spec.whatwg.org/#try-to-consume-a-token
@@ -321,7 +314,7 @@ func (p *patternParser) addPart(prefix string, nameToken *token, regexpOrWildcar
 	if nameToken != nil {
 		name = nameToken.value
 	} else if regexpOrWildcardToken != nil {
-		name = strconv.FormatFloat(p.nextNumericName, 'f', -1, 64)
+		name = AnonymousGroupName(p.nextNumericName)
 		p.nextNumericName++
 	}
 
@@ -389,44 +382,44 @@ func (p *patternParser) consumeRequiredToken(tokenType tokenType) error {
 
 // https://urlpattern.spec.whatwg.org/#generate-a-segment-wildcard-regexp
 func generateSegmentWildcardRegexp(options options) string {
-	return "[^" + escapeRegexpString(string(options.delimiterCodePoint)) + "]+?"
+	return "[^" + EscapeRegexpString(string(options.delimiterCodePoint)) + "]+?"
 }
 
 // https://urlpattern.spec.whatwg.org/#canonicalize-a-protocol
-func canonicalizeProtocol(value string) (string, error) {
+func canonicalizeProtocol(p URLParser, value string) (string, error) {
 	if value == "" {
 		return value, nil
 	}
 
-	dummyURL, err := urlParser.Parse(value + "://dummy.test")
+	dummyURL, err := p.Parse(value+"://dummy.test", "")
 	if err != nil {
 		return "", err
 	}
 
-	return dummyURL.Scheme(), nil
+	return dummyURL.Protocol, nil
 }
 
 // https://urlpattern.spec.whatwg.org/#canonicalize-a-username
-func canonicalizeUsername(value string) (string, error) {
+func canonicalizeUsername(p URLParser, value string) (string, error) {
 	if value == "" {
 		return value, nil
 	}
 
-	return urlParser.PercentEncodeString(value, url.UserInfoPercentEncodeSet), nil
+	return p.PercentEncodeString(value, PercentEncodeUserInfo), nil
 }
 
 // https://urlpattern.spec.whatwg.org/#canonicalize-a-password
-func canonicalizePassword(value string) (string, error) {
+func canonicalizePassword(p URLParser, value string) (string, error) {
 	if value == "" {
 		return value, nil
 	}
 
-	return urlParser.PercentEncodeString(value, url.UserInfoPercentEncodeSet), nil
+	return p.PercentEncodeString(value, PercentEncodeUserInfo), nil
 }
 
 // https://urlpattern.spec.whatwg.org/#canonicalize-a-hostname
 // https://github.com/whatwg/urlpattern/issues/220#issuecomment-2074613501
-func canonicalizeHostname(hostnameValue, protocolValue string) (string, error) {
+func canonicalizeHostname(p URLParser, hostnameValue, protocolValue string) (string, error) {
 	if hostnameValue == "" {
 		return hostnameValue, nil
 	}
@@ -442,35 +435,16 @@ func canonicalizeHostname(hostnameValue, protocolValue string) (string, error) {
 		}
 	}
 
-	var (
-		u   *url.Url
-		err error
-	)
-
-	if protocolValue == "" {
-		u = hostnameParser.NewUrl()
-	} else {
-		u, err = hostnameParser.Parse(protocolValue + "://dummy.test")
-		if err != nil {
-			return "", err
-		}
-	}
-
-	u, err = hostnameParser.BasicParser(hostnameValue, nil, u, url.StateHostname)
+	u, err := p.BasicParser(hostnameValue, protocolValue, ParserStateHostname)
 	if err != nil {
 		return "", err
 	}
 
-	return u.Hostname(), nil
-}
-
-// https://github.com/whatwg/urlpattern/issues/220#issuecomment-2074613501
-func canonicalizeDomainName(value string) (string, error) {
-	return canonicalizeHostname(value, "https")
+	return u.Hostname, nil
 }
 
 // https://urlpattern.spec.whatwg.org/#canonicalize-a-port
-func canonicalizePort(portValue, protocolValue string) (string, error) {
+func canonicalizePort(p URLParser, portValue, protocolValue string) (string, error) {
 	if portValue == "" {
 		return portValue, nil
 	}
@@ -500,27 +474,22 @@ func canonicalizePort(portValue, protocolValue string) (string, error) {
 		scheme = "urlpattern-non-special"
 	}
 
-	u, err := urlParser.Parse(scheme + "://dummy.test")
-	if err != nil {
-		return "", err
-	}
-
-	u, err = urlParser.BasicParser(portValue, nil, u, url.StatePort)
+	u, err := p.BasicParser(portValue, scheme, ParserStatePort)
 	if err != nil {
 		return "", err
 	}
 
-	return u.Port(), nil
+	return u.Port, nil
 }
 
 // https://urlpattern.spec.whatwg.org/#canonicalize-a-pathname
 // TODO: Note, implementations are free to simply disable slash prepending in their URL parsing code instead of paying the performance penalty of inserting and removing characters in this algorithm.
-func canonicalizePathname(value string) (string, error) {
+func canonicalizePathname(p URLParser, value string) (string, error) {
 	if value == "" {
 		return value, nil
 	}
 
-	leadingSlash := []rune(value)[0] == '/'
+	leadingSlash := value[0] == '/'
 	var modifiedValue strings.Builder
 
 	if !leadingSlash {
@@ -529,15 +498,21 @@ func canonicalizePathname(value string) (string, error) {
 
 	modifiedValue.WriteString(value)
 
-	dummyURL := urlParser.NewUrl()
-	u, err := urlParser.BasicParser(modifiedValue.String(), nil, dummyURL, url.StatePathStart)
+	u, err := p.BasicParser(modifiedValue.String(), "", ParserStatePathStart)
 	if err != nil {
 		return "", err
 	}
 
-	result := u.Pathname()
+	result := u.Pathname
 
 	if !leadingSlash {
+		// The "-" segment can be removed by a following ".." segment, e.g.
+		// for "./..": as the substring method of JavaScript, return the
+		// empty string if the result is too short.
+		if len(result) < 2 {
+			return "", nil
+		}
+
 		result = result[2:]
 	}
 
@@ -545,51 +520,45 @@ func canonicalizePathname(value string) (string, error) {
 }
 
 // https://urlpattern.spec.whatwg.org/#canonicalize-an-opaque-pathname
-func canonicalizeOpaquePathname(value string) (string, error) {
+func canonicalizeOpaquePathname(p URLParser, value string) (string, error) {
 	if value == "" {
 		return value, nil
 	}
 
-	var err error
-	dummyURL := urlParser.NewUrl()
-
-	u, err := urlParser.BasicParser(value, nil, dummyURL, url.StateOpaquePath)
+	u, err := p.BasicParser(value, "", ParserStateOpaquePath)
 	if err != nil {
 		return "", err
 	}
 
-	return u.Pathname(), nil
+	return u.Pathname, nil
 }
 
 // https://urlpattern.spec.whatwg.org/#canonicalize-a-search
-func canonicalizeSearch(value string) (string, error) {
+func canonicalizeSearch(p URLParser, value string) (string, error) {
 	if value == "" {
 		return value, nil
 	}
 
-	dummyURL := urlParser.NewUrl()
-
-	u, err := urlParser.BasicParser(value, nil, dummyURL, url.StateQuery)
+	u, err := p.BasicParser(value, "", ParserStateQuery)
 	if err != nil {
 		return "", err
 	}
 
-	return u.Query(), nil
+	return u.Search, nil
 }
 
 // https://urlpattern.spec.whatwg.org/#canonicalize-a-hash
-func canonicalizeHash(value string) (string, error) {
+func canonicalizeHash(p URLParser, value string) (string, error) {
 	if value == "" {
 		return value, nil
 	}
 
-	dummyURL := urlParser.NewUrl()
-	u, err := urlParser.BasicParser(value, nil, dummyURL, url.StateFragment)
+	u, err := p.BasicParser(value, "", ParserStateFragment)
 	if err != nil {
 		return "", err
 	}
 
-	return u.Fragment(), nil
+	return u.Hash, nil
 }
 
 // https://urlpattern.spec.whatwg.org/#canonicalize-an-ipv6-hostname
@@ -2,8 +2,10 @@ package urlpattern
 
 import (
 	"errors"
+	"regexp/syntax"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 type partType uint8
@@ -20,10 +22,10 @@ const (
 )
 
 var (
-	ErrEmptyPartName    = errors.New("part's name must not be empty string")
-	ErrInvalidModifier  = errors.New(`part's modifier must be "zero-or-more" or "one-or-more"`)
+	ErrEmptyPartName         = errors.New("part's name must not be empty string")
+	ErrInvalidModifier       = errors.New(`part's modifier must be "zero-or-more" or "one-or-more"`)
 	ErrInvalidPrefixOrSuffix = errors.New("part's prefix is not the empty string or part's suffix is not the empty string")
-	ErrInvalidPartName  = errors.New("part's name is not the empty string or null")
+	ErrInvalidPartName       = errors.New("part's name is not the empty string or null")
 )
 
 type partModifier uint8
@@ -54,6 +56,7 @@ type partList []part
 func (pl partList) generateRegularExpressionAndNameList(options options) (string, []string, error) {
 	var result strings.Builder
 	nameList := make([]string, 0, len(pl))
+	reserved := pl.customCaptureNames()
 
 	// the v flag doesn't exist in Go
 	if options.ignoreCase {
@@ -65,15 +68,12 @@ func (pl partList) generateRegularExpressionAndNameList(options options) (string
 	for _, p := range pl {
 		if p.pType == partFixedText {
 			if p.modifier == partModifierNone {
-				result.WriteString(escapeRegexpString(p.value))
+				result.WriteString(EscapeRegexpString(p.value))
 			} else {
 				result.WriteString("(?:")
-				result.WriteString(escapeRegexpString(p.value))
+				result.WriteString(EscapeRegexpString(p.value))
 				result.WriteByte(')')
-
-				if modifierToString := convertModifierToString(p.modifier); modifierToString != 0 {
-					result.WriteByte(modifierToString)
-				}
+				writeModifier(&result, p.modifier, options)
 			}
 
 			continue
@@ -99,7 +99,7 @@ func (pl partList) generateRegularExpressionAndNameList(options options) (string
 		if p.prefix == "" && p.suffix == "" {
 			switch p.modifier {
 			case partModifierNone, partModifierOptional:
-				result.WriteByte('(')
+				writeCaptureStart(&result, p.name, reserved)
 				result.WriteString(regexpValue)
 				result.WriteByte(')')
 
@@ -108,14 +108,11 @@ func (pl partList) generateRegularExpressionAndNameList(options options) (string
 				}
 
 			default:
-				result.WriteString("((?:")
+				writeCaptureStart(&result, p.name, reserved)
+				result.WriteString("(?:")
 				result.WriteString(regexpValue)
 				result.WriteByte(')')
-
-				if modifierToString := convertModifierToString(p.modifier); modifierToString != 0 {
-					result.WriteByte(modifierToString)
-				}
-
+				writeModifier(&result, p.modifier, options)
 				result.WriteByte(')')
 			}
 
@@ -124,11 +121,11 @@ func (pl partList) generateRegularExpressionAndNameList(options options) (string
 
 		if p.modifier == partModifierNone || p.modifier == partModifierOptional {
 			result.WriteString("(?:")
-			result.WriteString(escapeRegexpString((p.prefix)))
-			result.WriteByte('(')
+			result.WriteString(EscapeRegexpString((p.prefix)))
+			writeCaptureStart(&result, p.name, reserved)
 			result.WriteString(regexpValue)
 			result.WriteByte(')')
-			result.WriteString(escapeRegexpString((p.suffix)))
+			result.WriteString(EscapeRegexpString((p.suffix)))
 			result.WriteByte(')')
 
 			if modifierToString := convertModifierToString(p.modifier); modifierToString != 0 {
@@ -149,16 +146,19 @@ func (pl partList) generateRegularExpressionAndNameList(options options) (string
 		}
 
 		result.WriteString("(?:")
-		result.WriteString(escapeRegexpString(p.prefix))
-		result.WriteString("((?:")
+		result.WriteString(EscapeRegexpString(p.prefix))
+		writeCaptureStart(&result, p.name, reserved)
+		result.WriteString("(?:")
 		result.WriteString(regexpValue)
 		result.WriteString(")(?:")
-		result.WriteString(escapeRegexpString(p.suffix))
-		result.WriteString(escapeRegexpString(p.prefix))
+		result.WriteString(EscapeRegexpString(p.suffix))
+		result.WriteString(EscapeRegexpString(p.prefix))
 		result.WriteString("(?:")
 		result.WriteString(regexpValue)
-		result.WriteString("))*)")
-		result.WriteString(escapeRegexpString(p.suffix))
+		result.WriteString("))")
+		writeFollowingRepetitions(&result, options)
+		result.WriteByte(')')
+		result.WriteString(EscapeRegexpString(p.suffix))
 		result.WriteByte(')')
 		if p.modifier == partModifierZeroOrMore {
 			result.WriteByte('?')
@@ -170,6 +170,105 @@ func (pl partList) generateRegularExpressionAndNameList(options options) (string
 	return result.String(), nameList, nil
 }
 
+// writeCaptureStart opens the capturing group of the part named name. It is a
+// Go named group ("(?P<name>"), which makes the generated regular expression
+// introspectable with regexp.Regexp.SubexpNames, unless name isn't usable as
+// such (see isCaptureName).
+func writeCaptureStart(result *strings.Builder, name string, reserved map[string]struct{}) {
+	if !isCaptureName(name, reserved) {
+		result.WriteByte('(')
+
+		return
+	}
+
+	result.WriteString("(?P<")
+	result.WriteString(name)
+	result.WriteByte('>')
+}
+
+// isCaptureName reports whether the part named name is generated as a Go
+// named group. Go group names only contain ASCII letters, digits and
+// underscores, while part names are JavaScript identifiers, and must not clash
+// with the named groups of custom regexp parts (reserved).
+func isCaptureName(name string, reserved map[string]struct{}) bool {
+	if name == "" {
+		return false
+	}
+
+	for i := range len(name) {
+		if c := name[i]; c != '_' && !isASCIIAlpha(c) && (c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	_, ok := reserved[name]
+
+	return !ok
+}
+
+// customCaptureNames returns the names of the groups declared in the custom
+// regexp parts of pl (e.g. "(?<x>...)").
+func (pl partList) customCaptureNames() map[string]struct{} {
+	var names map[string]struct{}
+	for _, p := range pl {
+		if p.pType != partRegexp {
+			continue
+		}
+
+		// Invalid expressions are reported by checkRegexpFeatures.
+		re, err := syntax.Parse(p.value, syntax.Perl)
+		if err != nil {
+			continue
+		}
+
+		for _, name := range re.CapNames() {
+			if name == "" {
+				continue
+			}
+
+			if names == nil {
+				names = make(map[string]struct{})
+			}
+			names[name] = struct{}{}
+		}
+	}
+
+	return names
+}
+
+// subexpIndexes returns, for each entry of the name list generated from pl,
+// the index of the corresponding capturing group in the generated regular
+// expression, in the manner of regexp.Regexp.SubexpIndex.
+//
+// It is only needed for the parts that aren't generated as Go named groups
+// (see isCaptureName): custom regexp parts may contain capturing groups of
+// their own (e.g. "(?<x>...)"), which shift the indexes of the following
+// groups.
+func (pl partList) subexpIndexes() []int {
+	indexes := make([]int, 0, len(pl))
+	next := 1
+
+	for _, p := range pl {
+		if p.pType == partFixedText {
+			continue
+		}
+
+		indexes = append(indexes, next)
+		next++
+
+		if p.pType != partRegexp {
+			continue
+		}
+
+		// Invalid expressions are reported by checkRegexpFeatures.
+		if re, err := syntax.Parse(p.value, syntax.Perl); err == nil {
+			next += re.MaxCap()
+		}
+	}
+
+	return indexes
+}
+
 // https://urlpattern.spec.whatwg.org/#generate-a-pattern-string
 func (pl partList) generatePatternString(options options) (string, error) {
 	var result strings.Builder
@@ -189,13 +288,13 @@ func (pl partList) generatePatternString(options options) (string, error) {
 
 		if part.pType == partFixedText {
 			if part.modifier == partModifierNone {
-				result.WriteString(escapePatternString(part.value))
+				result.WriteString(EscapePatternString(part.value))
 
 				continue
 			}
 
 			result.WriteByte('{')
-			result.WriteString(escapePatternString(part.value))
+			result.WriteString(EscapePatternString(part.value))
 			result.WriteByte('}')
 			if modifier := convertModifierToString(part.modifier); modifier != 0 {
 				result.WriteByte(modifier)
@@ -204,7 +303,12 @@ func (pl partList) generatePatternString(options options) (string, error) {
 			continue
 		}
 
-		customName := !unicode.IsDigit([]rune(part.name)[0])
+		// Assert: part’s name is not the empty string or null.
+		if part.name == "" {
+			return "", ErrInvalidPartName
+		}
+
+		customName := !unicode.IsDigit(firstCodePoint(part.name))
 		needGrouping := part.suffix != "" || (part.prefix != "" && part.prefix != string(options.prefixCodePoint))
 
 		if !needGrouping &&
@@ -215,10 +319,10 @@ func (pl partList) generatePatternString(options options) (string, error) {
 			nextPart.prefix == "" &&
 			nextPart.suffix == "" {
 			if nextPart.pType == partFixedText {
-				if isValidNameCodePoint([]rune(nextPart.value)[0], false) {
+				if isValidNameCodePoint(firstCodePoint(nextPart.value), false) {
 					needGrouping = true
 				}
-			} else if unicode.IsDigit([]rune(nextPart.name)[0]) {
+			} else if unicode.IsDigit(firstCodePoint(nextPart.name)) {
 				needGrouping = true
 			}
 		}
@@ -227,20 +331,15 @@ func (pl partList) generatePatternString(options options) (string, error) {
 			part.prefix == "" &&
 			previousPart != nil &&
 			previousPart.pType == partFixedText &&
-			[]rune(previousPart.value)[len(previousPart.value)-1] == rune(options.prefixCodePoint) {
+			strings.HasSuffix(previousPart.value, string(options.prefixCodePoint)) {
 			needGrouping = true
 		}
 
-		// Assert: part’s name is not the empty string or null.
-		if part.name == "" {
-			return "", ErrInvalidPartName
-		}
-
 		if needGrouping {
 			result.WriteByte('{')
 		}
 
-		result.WriteString(escapePatternString(part.prefix))
+		result.WriteString(EscapePatternString(part.prefix))
 
 		if customName {
 			result.WriteByte(':')
@@ -277,11 +376,11 @@ func (pl partList) generatePatternString(options options) (string, error) {
 		if part.pType == partSegmentWildcard &&
 			customName &&
 			part.suffix != "" &&
-			isValidNameCodePoint([]rune(part.suffix)[0], false) {
+			isValidNameCodePoint(firstCodePoint(part.suffix), false) {
 			result.WriteByte('\\')
 		}
 
-		result.WriteString(escapePatternString(part.suffix))
+		result.WriteString(EscapePatternString(part.suffix))
 
 		if needGrouping {
 			result.WriteByte('}')
@@ -295,6 +394,13 @@ func (pl partList) generatePatternString(options options) (string, error) {
 	return result.String(), nil
 }
 
+// firstCodePoint returns the first code point of s, or utf8.RuneError if s is empty.
+func firstCodePoint(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+
+	return r
+}
+
 // https://urlpattern.spec.whatwg.org/#convert-a-modifier-to-a-string
 func convertModifierToString(m partModifier) byte {
 	switch m {
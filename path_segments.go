@@ -0,0 +1,52 @@
+package urlpattern
+
+import (
+	"iter"
+	"net/url"
+	"strings"
+)
+
+// PathSegments returns an iterator over the segments of the matched pathname,
+// percent-decoded, so that hierarchical paths can be walked without splitting
+// and decoding the pathname again:
+//
+//	for segment := range result.PathSegments() {
+//		node = node.Child(segment)
+//	}
+//
+// The pathname is split on the slashes of its canonical form: encoded slashes
+// ("%2F") are part of the segments. "/" and the empty pathname have no
+// segment, and a trailing slash yields a final empty segment. Opaque
+// pathnames, not starting with a slash (e.g. "text/plain,hello" for
+// "data:text/plain,hello"), are a single segment. Segments which can't be
+// decoded are yielded as is.
+func (r *URLPatternResult) PathSegments() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		path, hierarchical := strings.CutPrefix(r.Pathname.Input, "/")
+		if path == "" {
+			return
+		}
+
+		if !hierarchical {
+			yield(unescapeSegment(path))
+
+			return
+		}
+
+		for segment := range strings.SplitSeq(path, "/") {
+			if !yield(unescapeSegment(segment)) {
+				return
+			}
+		}
+	}
+}
+
+// unescapeSegment returns segment percent-decoded, or segment itself if it
+// can't be decoded.
+func unescapeSegment(segment string) string {
+	if decoded, err := url.PathUnescape(segment); err == nil {
+		return decoded
+	}
+
+	return segment
+}
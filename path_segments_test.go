@@ -0,0 +1,48 @@
+package urlpattern_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestPathSegments(t *testing.T) {
+	u, err := urlpattern.New("*:*", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		input    string
+		expected []string
+	}{
+		{"https://example.com/", nil},
+		{"https://example.com/docs/guide/intro", []string{"docs", "guide", "intro"}},
+		{"https://example.com/docs/", []string{"docs", ""}},
+		{"https://example.com/caf%C3%A9/a%2Fb", []string{"café", "a/b"}},
+		{"https://example.com/100%/x", []string{"100%", "x"}},
+		{"data:text/plain,hello", []string{"text/plain,hello"}},
+		{"foo://example.com", nil},
+	} {
+		r := u.Exec(tt.input, "")
+		if r == nil {
+			t.Fatalf("%s doesn't match", tt.input)
+		}
+
+		if segments := slices.Collect(r.PathSegments()); !slices.Equal(segments, tt.expected) {
+			t.Errorf("%s: got %q; want %q", tt.input, segments, tt.expected)
+		}
+	}
+
+	var segments []string
+	for segment := range u.Exec("https://example.com/a/b/c", "").PathSegments() {
+		if segment == "b" {
+			break
+		}
+		segments = append(segments, segment)
+	}
+	if !slices.Equal(segments, []string{"a"}) {
+		t.Errorf("got %q after break", segments)
+	}
+}
@@ -0,0 +1,45 @@
+package urlpattern
+
+// URLPatternMap maps URL patterns to values of type T, such as handlers or
+// configurations, without managing parallel slices of patterns and values:
+//
+//	var handlers urlpattern.URLPatternMap[http.Handler]
+//	if err := handlers.Add("https://example.com/books/:id", booksHandler); err != nil {
+//		// ...
+//	}
+//
+//	if handler, result, ok := handlers.Lookup(input); ok {
+//		// ...
+//	}
+//
+// Patterns are matched in insertion order, as in a URLPatternList. Use a
+// TypedList for patterns compiled with options, priorities or strategies.
+// The zero value is ready to use, and Add must not be called concurrently with
+// other methods.
+type URLPatternMap[T any] struct {
+	list TypedList[T]
+}
+
+// Add compiles pattern, a constructor string (see Compile), and appends it to
+// the map with value. It returns the compilation error, if any.
+func (m *URLPatternMap[T]) Add(pattern string, value T) error {
+	u, err := Compile(pattern)
+	if err != nil {
+		return err
+	}
+
+	m.list.Add(u, value)
+
+	return nil
+}
+
+// Lookup returns the value of the first pattern matching url, the result of
+// the match, and whether a pattern matches.
+func (m *URLPatternMap[T]) Lookup(url string) (T, *URLPatternResult, bool) {
+	return m.list.FirstMatch(url)
+}
+
+// Len returns the number of patterns in the map.
+func (m *URLPatternMap[T]) Len() int {
+	return m.list.List().Len()
+}
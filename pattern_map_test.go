@@ -0,0 +1,35 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestURLPatternMap(t *testing.T) {
+	var m urlpattern.URLPatternMap[string]
+	for pattern, value := range map[string]string{
+		"https://example.com/books/:id":     "book",
+		"https://example.com/authors/:name": "author",
+	} {
+		if err := m.Add(pattern, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := m.Add("https://example.com/(", "invalid"); err == nil {
+		t.Error("want error")
+	}
+	if m.Len() != 2 {
+		t.Errorf("want 2 patterns; got %d", m.Len())
+	}
+
+	value, result, ok := m.Lookup("https://example.com/books/42")
+	if !ok || value != "book" || result.Pathname.Groups["id"] != "42" {
+		t.Errorf("unexpected lookup %q, %v, %v", value, result, ok)
+	}
+
+	if value, result, ok := m.Lookup("https://example.com/about"); ok || value != "" || result != nil {
+		t.Errorf("want no match; got %q", value)
+	}
+}
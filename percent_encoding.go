@@ -0,0 +1,83 @@
+package urlpattern
+
+import "strings"
+
+// WithPercentEncodingNormalization normalizes equivalent percent-encodings
+// in the username, password, pathname, search and hash of the matched URLs
+// and of the fixed text of patterns, as described in RFC 3986 section 6.2.2:
+// percent-encoded unreserved characters are decoded ("%7E" becomes "~") and
+// the hexadecimal digits of the remaining percent-encodings are uppercased
+// ("%2f" becomes "%2F").
+//
+// This makes patterns behave consistently across clients that encode URLs
+// differently.
+func WithPercentEncodingNormalization() Option {
+	return func(c *config) {
+		c.normalizePercentEncoding = true
+	}
+}
+
+// normalizePercentEncoding decodes the percent-encoded unreserved characters
+// of s and uppercases the hexadecimal digits of other percent-encodings.
+//
+// https://www.rfc-editor.org/rfc/rfc3986#section-6.2.2
+func normalizePercentEncoding(s string) string {
+	i := strings.IndexByte(s, '%')
+	if i == -1 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	b.WriteString(s[:i])
+
+	for ; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) || !isHexDigit(s[i+1]) || !isHexDigit(s[i+2]) {
+			b.WriteByte(s[i])
+
+			continue
+		}
+
+		c := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteByte(upperHex(s[i+1]))
+			b.WriteByte(upperHex(s[i+2]))
+		}
+
+		i += 2
+	}
+
+	return b.String()
+}
+
+// https://www.rfc-editor.org/rfc/rfc3986#section-2.3
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c <= '9':
+		return c - '0'
+	case c <= 'F':
+		return c - 'A' + 10
+	default:
+		return c - 'a' + 10
+	}
+}
+
+func upperHex(c byte) byte {
+	if 'a' <= c && c <= 'f' {
+		return c - 'a' + 'A'
+	}
+
+	return c
+}
@@ -0,0 +1,43 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestPercentEncodingNormalization(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://example.com/%7Euser/caf%c3%a9/:rest*", urlpattern.WithPercentEncodingNormalization())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "/~user/caf%C3%A9/:rest*"; pattern.Pathname() != want {
+		t.Errorf("want %q; got %q", want, pattern.Pathname())
+	}
+
+	for _, input := range []string{
+		"https://example.com/~user/café/a",
+		"https://example.com/%7euser/caf%C3%A9/a",
+		"https://example.com/%7Euser/caf%c3%a9/a",
+	} {
+		r := pattern.Exec(input, "")
+		if r == nil {
+			t.Errorf("want %q to match", input)
+
+			continue
+		}
+
+		if want := "/~user/caf%C3%A9/a"; r.Pathname.Input != want {
+			t.Errorf("want %q; got %q", want, r.Pathname.Input)
+		}
+	}
+
+	pattern, err = urlpattern.Compile("https://example.com/~user/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pattern.Test("https://example.com/%7Euser/a", "") {
+		t.Error("want no match without normalization")
+	}
+}
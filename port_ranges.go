@@ -0,0 +1,93 @@
+package urlpattern
+
+import (
+	"strconv"
+	"strings"
+)
+
+// WithPortRanges enables an extension of the port pattern syntax allowing
+// lists and ranges of ports, as commonly found in firewall and gateway
+// configurations: "8000-8999", "{8080,8443}" or "{80,8000-8999}".
+//
+// Such patterns are compiled to numeric checks instead of regular
+// expressions. An empty port in the matched URL is treated as the default
+// port of its protocol (see DefaultPort). Other port patterns are compiled
+// as usual.
+func WithPortRanges() Option {
+	return func(c *config) {
+		c.portRanges = true
+	}
+}
+
+type portRange struct {
+	min, max uint16
+}
+
+// portRanges is a list of port ranges, the extended port syntax enabled by
+// WithPortRanges.
+type portRanges []portRange
+
+// parsePortRanges parses a port pattern using the extended port syntax. It
+// returns false if the pattern doesn't use this syntax, and ErrInvalidPort if
+// it does but a port is out of range.
+func parsePortRanges(pattern string) (portRanges, bool, error) {
+	list := pattern
+	if strings.HasPrefix(list, "{") && strings.HasSuffix(list, "}") {
+		list = list[1 : len(list)-1]
+	}
+
+	if !strings.ContainsAny(list, ",-") {
+		return nil, false, nil
+	}
+
+	var ranges portRanges
+	for item := range strings.SplitSeq(list, ",") {
+		lower, upper, isRange := strings.Cut(item, "-")
+		if !isRange {
+			upper = lower
+		}
+
+		if !isPortNumber(lower) || !isPortNumber(upper) {
+			return nil, false, nil
+		}
+
+		lo, errLo := strconv.ParseUint(lower, 10, 16)
+		hi, errHi := strconv.ParseUint(upper, 10, 16)
+		if errLo != nil || errHi != nil || lo > hi {
+			return nil, true, ErrInvalidPort
+		}
+
+		ranges = append(ranges, portRange{uint16(lo), uint16(hi)})
+	}
+
+	return ranges, true, nil
+}
+
+func isPortNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r portRanges) contains(port string) bool {
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return false
+	}
+
+	for _, pr := range r {
+		if uint16(p) >= pr.min && uint16(p) <= pr.max {
+			return true
+		}
+	}
+
+	return false
+}
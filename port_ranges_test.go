@@ -0,0 +1,47 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestPortRanges(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{"https://example.com:8000-8999/*", []string{"https://example.com:8000/", "https://example.com:8999/"}, []string{"https://example.com:9000/", "https://example.com/"}},
+		{"https://example.com:{8080,8443}/*", []string{"https://example.com:8080/", "https://example.com:8443/"}, []string{"https://example.com:8081/"}},
+		{"https://example.com:{443,8000-8999}/*", []string{"https://example.com/", "https://example.com:8500/"}, []string{"https://example.com:80/"}},
+		{"https://example.com:8080/*", []string{"https://example.com:8080/"}, []string{"https://example.com:8000/"}},
+	} {
+		t.Run(tc.pattern, func(t *testing.T) {
+			pattern, err := urlpattern.Compile(tc.pattern, urlpattern.WithPortRanges())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, input := range tc.match {
+				if !pattern.Test(input, "") {
+					t.Errorf("want %q to match", input)
+				}
+			}
+			for _, input := range tc.noMatch {
+				if pattern.Test(input, "") {
+					t.Errorf("want %q not to match", input)
+				}
+			}
+		})
+	}
+}
+
+func TestPortRangesInvalid(t *testing.T) {
+	for _, p := range []string{"https://example.com:9000-8000/*", "https://example.com:{80,70000}/*"} {
+		if _, err := urlpattern.Compile(p, urlpattern.WithPortRanges()); !errors.Is(err, urlpattern.ErrInvalidPort) {
+			t.Errorf("%s: want ErrInvalidPort; got %v", p, err)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package urlpattern
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ProxyTrust is the policy deciding which forwarding headers set by reverse
+// proxies are used to reconstruct the URL of a request, see
+// ExecRequestBehindProxy. The zero value trusts no proxy.
+type ProxyTrust struct {
+	// Proxies holds the networks of the trusted proxies. The forwarding
+	// headers are ignored unless the request comes from one of them (see
+	// http.Request.RemoteAddr).
+	Proxies []netip.Prefix
+}
+
+// trusts reports whether addr, an IP address optionally followed by a port,
+// belongs to a trusted proxy. Unknown and obfuscated addresses aren't
+// trusted.
+func (t ProxyTrust) trusts(addr string) bool {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	ip, err := netip.ParseAddr(strings.Trim(addr, "[]"))
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+
+	for _, p := range t.Proxies {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RequestURL returns the URL of r as requested by the client: the scheme,
+// host and port are taken from the forwarding headers set by the trusted
+// proxies, if any, and otherwise from the connection (r.TLS) and the Host
+// header.
+//
+// The Forwarded header (RFC 7239) is preferred. Its elements are read from
+// the last one, added by the proxy the request comes from, to the first one,
+// as long as they were added by trusted proxies: the proto and host of the
+// element added by the outermost trusted proxy are used. Without Forwarded
+// header, the last values of the X-Forwarded-Proto, X-Forwarded-Host and
+// X-Forwarded-Port headers, set by the proxy the request comes from, are
+// used.
+func (t ProxyTrust) RequestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+
+	if t.trusts(r.RemoteAddr) {
+		if forwarded := r.Header.Values("Forwarded"); len(forwarded) > 0 {
+			scheme, host = t.forwarded(forwarded, scheme, host)
+		} else {
+			scheme, host = forwardedFor(r.Header, scheme, host)
+		}
+	}
+
+	return scheme + "://" + host + r.URL.RequestURI()
+}
+
+// forwarded returns the scheme and the host of the Forwarded header values.
+func (t ProxyTrust) forwarded(values []string, scheme, host string) (string, string) {
+	elements := parseForwarded(values)
+	for i := len(elements) - 1; i >= 0; i-- {
+		e := elements[i]
+		if proto := e["proto"]; proto != "" {
+			scheme = strings.ToLower(proto)
+		}
+		if h := e["host"]; h != "" {
+			host = h
+		}
+
+		if !t.trusts(e["for"]) {
+			break
+		}
+	}
+
+	return scheme, host
+}
+
+// forwardedFor returns the scheme and the host of the X-Forwarded-* headers.
+func forwardedFor(h http.Header, scheme, host string) (string, string) {
+	if proto := lastHeaderValue(h, "X-Forwarded-Proto"); proto != "" {
+		scheme = strings.ToLower(proto)
+	}
+
+	if forwardedHost := lastHeaderValue(h, "X-Forwarded-Host"); forwardedHost != "" {
+		host = forwardedHost
+	}
+
+	if port := lastHeaderValue(h, "X-Forwarded-Port"); port != "" {
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		host = net.JoinHostPort(strings.Trim(host, "[]"), port)
+	}
+
+	return scheme, host
+}
+
+// lastHeaderValue returns the last element of the comma-separated lists of
+// the values of the header named name.
+func lastHeaderValue(h http.Header, name string) string {
+	values := h.Values(name)
+	if len(values) == 0 {
+		return ""
+	}
+
+	v := values[len(values)-1]
+	if i := strings.LastIndexByte(v, ','); i != -1 {
+		v = v[i+1:]
+	}
+
+	return strings.TrimSpace(v)
+}
+
+// parseForwarded parses the values of Forwarded headers into their elements,
+// with lowercase parameter names and unquoted values.
+func parseForwarded(values []string) []map[string]string {
+	var elements []map[string]string
+	for _, v := range values {
+		e := make(map[string]string)
+		for len(v) > 0 {
+			var pair string
+			pair, v = cutForwarded(v)
+
+			if name, value, ok := strings.Cut(pair, "="); ok {
+				e[strings.ToLower(strings.TrimSpace(name))] = unquoteForwarded(strings.TrimSpace(value))
+			}
+
+			if strings.HasPrefix(v, ",") {
+				elements = append(elements, e)
+				e = make(map[string]string)
+			}
+			if v != "" {
+				v = v[1:]
+			}
+		}
+		elements = append(elements, e)
+	}
+
+	return elements
+}
+
+// cutForwarded returns the pair starting s, up to the next ";" or "," outside
+// of a quoted string, and the rest of s starting with the separator.
+func cutForwarded(s string) (string, string) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && quoted:
+			i++
+		case c == '"':
+			quoted = !quoted
+		case (c == ';' || c == ',') && !quoted:
+			return s[:i], s[i:]
+		}
+	}
+
+	return s, ""
+}
+
+// unquoteForwarded returns the value of a quoted string, or s if it isn't
+// quoted.
+func unquoteForwarded(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s)-1; i++ {
+		if s[i] == '\\' && i+1 < len(s)-1 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// ExecRequestBehindProxy matches the URL of r as requested by the client,
+// reconstructed from the forwarding headers of the proxies trusted by trust
+// (see ProxyTrust.RequestURL), which is essential behind gateways terminating
+// TLS:
+//
+//	trust := urlpattern.ProxyTrust{Proxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}
+//	result := pattern.ExecRequestBehindProxy(r, trust)
+//
+// With the zero ProxyTrust, the forwarding headers are ignored.
+func (u *URLPattern) ExecRequestBehindProxy(r *http.Request, trust ProxyTrust) *URLPatternResult {
+	return u.Exec(trust.RequestURL(r), "")
+}
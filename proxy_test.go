@@ -0,0 +1,69 @@
+package urlpattern_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestProxyTrustRequestURL(t *testing.T) {
+	trust := urlpattern.ProxyTrust{Proxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8::/32")}}
+
+	for _, tt := range []struct {
+		name       string
+		remoteAddr string
+		tls        bool
+		headers    map[string][]string
+		expected   string
+	}{
+		{"direct", "192.0.2.1:1234", false, nil, "http://internal:8080/books/1?page=2"},
+		{"direct TLS", "192.0.2.1:1234", true, nil, "https://internal:8080/books/1?page=2"},
+		{"untrusted peer", "192.0.2.1:1234", false, map[string][]string{"Forwarded": {"proto=https;host=example.com"}, "X-Forwarded-Proto": {"https"}}, "http://internal:8080/books/1?page=2"},
+		{"forwarded", "10.0.0.1:1234", false, map[string][]string{"Forwarded": {`for=192.0.2.60;proto=HTTPS;host="example.com"`}}, "https://example.com/books/1?page=2"},
+		{"forwarded chain", "10.0.0.1:1234", false, map[string][]string{"Forwarded": {`for=192.0.2.60;proto=https;host=example.com, for="[2001:db8::1]:4711";proto=http;host=lb.internal`}}, "https://example.com/books/1?page=2"},
+		{"forwarded spoofed", "10.0.0.1:1234", false, map[string][]string{"Forwarded": {"for=10.0.0.2;host=evil.example", "for=192.0.2.60;proto=https;host=example.com"}}, "https://example.com/books/1?page=2"},
+		{"forwarded precedence", "10.0.0.1:1234", false, map[string][]string{"Forwarded": {"proto=https;host=example.com"}, "X-Forwarded-Host": {"other.example"}}, "https://example.com/books/1?page=2"},
+		{"x-forwarded", "[2001:db8::2]:1234", false, map[string][]string{"X-Forwarded-Proto": {"https"}, "X-Forwarded-Host": {"evil.example, example.com"}}, "https://example.com/books/1?page=2"},
+		{"x-forwarded port", "10.0.0.1:1234", false, map[string][]string{"X-Forwarded-Proto": {"https"}, "X-Forwarded-Host": {"example.com"}, "X-Forwarded-Port": {"8443"}}, "https://example.com:8443/books/1?page=2"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://internal:8080/books/1?page=2", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+			for name, values := range tt.headers {
+				r.Header[name] = values
+			}
+
+			if u := trust.RequestURL(r); u != tt.expected {
+				t.Errorf("got %q, want %q", u, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecRequestBehindProxy(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://example.com/books/:id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "http://10.0.0.5/books/42", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("X-Forwarded-Proto", "https")
+	r.Header.Set("X-Forwarded-Host", "example.com")
+
+	if pattern.ExecRequestBehindProxy(r, urlpattern.ProxyTrust{}) != nil {
+		t.Error("forwarding headers must be ignored without trusted proxies")
+	}
+
+	result := pattern.ExecRequestBehindProxy(r, urlpattern.ProxyTrust{Proxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.1/32")}})
+	if result == nil || result.Pathname.Groups["id"] != "42" {
+		t.Errorf("unexpected result %#v", result)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build publicsuffix
+
+package urlpattern
+
+import (
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// The "registrable" constraint matches exactly the registrable domain (the
+// public suffix plus one label, e.g. "example.co.uk") of the hostname, using
+// the Public Suffix List of golang.org/x/net/publicsuffix:
+//
+//	urlpattern.Compile("https://*.:domain<registrable>/*", urlpattern.WithConstraints())
+//
+// It must end the hostname pattern, and is only available when building with
+// the "publicsuffix" tag, as the list is large.
+func init() {
+	constraintRegexps["registrable"] = `\x00[^.]+(?:\.[^.]+)*`
+	constraintMarkers["registrable"] = markRegistrableDomain
+}
+
+// markRegistrableDomain inserts constraintMarker before the registrable domain
+// of hostname, if any.
+func markRegistrableDomain(hostname string) string {
+	domain, err := publicsuffix.EffectiveTLDPlusOne(hostname)
+	if err != nil || !strings.HasSuffix(hostname, domain) {
+		return hostname
+	}
+
+	return hostname[:len(hostname)-len(domain)] + constraintMarker + domain
+}
@@ -0,0 +1,42 @@
+//go:build publicsuffix
+
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestRegistrableConstraint(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://{*.}?:domain<registrable>/*", urlpattern.WithConstraints())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for input, domain := range map[string]string{
+		"https://example.com/":           "example.com",
+		"https://www.example.com/":       "example.com",
+		"https://a.b.example.co.uk/":     "example.co.uk",
+		"https://user.github.io/project": "user.github.io",
+	} {
+		r := pattern.Exec(input, "")
+		if r == nil || r.Hostname.Groups["domain"] != domain {
+			t.Errorf("%s: want domain %q; got %#v", input, domain, r)
+		}
+	}
+
+	for _, input := range []string{"https://co.uk/", "https://localhost/", "https://127.0.0.1/"} {
+		if pattern.Test(input, "") {
+			t.Errorf("want %q not to match", input)
+		}
+	}
+
+	pattern, err = urlpattern.Compile("https://:domain<registrable>/*", urlpattern.WithConstraints())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pattern.Test("https://www.example.com/", "") {
+		t.Error("want subdomains not to match")
+	}
+}
@@ -0,0 +1,42 @@
+package urlpattern
+
+import (
+	"regexp"
+	"runtime"
+	"sync"
+	"weak"
+)
+
+// regexps caches the regular expressions generated for the components, keyed
+// by their source, as route tables commonly repeat the same structures (e.g.
+// "/books/:id" and "/authors/:id" in several hosts): identical expressions
+// are compiled once and shared, which saves memory and compilation time.
+// *regexp.Regexp is safe for concurrent use.
+//
+// Values are weak pointers, so that the expressions of discarded patterns
+// (e.g. after reloading a route table) can be garbage collected, their entry
+// being removed by a cleanup.
+var regexps sync.Map // map[string]weak.Pointer[regexp.Regexp]
+
+// compileRegexp is like regexp.Compile, but returns the cached expression if
+// expr has already been compiled.
+func compileRegexp(expr string) (*regexp.Regexp, error) {
+	if wp, ok := regexps.Load(expr); ok {
+		if re := wp.(weak.Pointer[regexp.Regexp]).Value(); re != nil {
+			return re, nil
+		}
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	wp := weak.Make(re)
+	regexps.Store(expr, wp)
+	runtime.AddCleanup(re, func(expr string) {
+		regexps.CompareAndDelete(expr, wp)
+	}, expr)
+
+	return re, nil
+}
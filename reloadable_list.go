@@ -0,0 +1,163 @@
+package urlpattern
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// ReloadableList serves matches from a URLPatternList that can be atomically
+// replaced while serving traffic, e.g. to update the routes of a gateway
+// without restarting it.
+//
+// The lists stored in a ReloadableList must not be modified afterward.
+// ReloadableList is safe for concurrent use.
+type ReloadableList struct {
+	list atomic.Pointer[URLPatternList]
+}
+
+// NewReloadableList creates a ReloadableList serving list.
+func NewReloadableList(list *URLPatternList) *ReloadableList {
+	r := &ReloadableList{}
+	r.Store(list)
+
+	return r
+}
+
+// Load returns the current list.
+func (r *ReloadableList) Load() *URLPatternList {
+	return r.list.Load()
+}
+
+// Store replaces the current list by list.
+func (r *ReloadableList) Store(list *URLPatternList) {
+	if list == nil {
+		list = &URLPatternList{}
+	}
+
+	r.list.Store(list)
+}
+
+// Add appends route to the current list, see URLPatternList.WithRoute.
+// Concurrent readers keep matching against the previous list until the new
+// one is stored.
+func (r *ReloadableList) Add(route Route) {
+	r.update(func(list *URLPatternList) (*URLPatternList, bool) {
+		return list.WithRoute(route), true
+	})
+}
+
+// Remove removes the routes named name from the current list, and reports
+// whether such a route was found, see URLPatternList.WithoutRoute.
+func (r *ReloadableList) Remove(name string) bool {
+	return r.update(func(list *URLPatternList) (*URLPatternList, bool) {
+		return list.WithoutRoute(name)
+	})
+}
+
+// Replace replaces the first route named name of the current list by route,
+// and reports whether such a route was found, see
+// URLPatternList.WithReplacedRoute.
+func (r *ReloadableList) Replace(name string, route Route) bool {
+	return r.update(func(list *URLPatternList) (*URLPatternList, bool) {
+		return list.WithReplacedRoute(name, route)
+	})
+}
+
+// update stores the copy of the current list returned by modify, if it
+// reports a change. modify is called again if the current list is replaced
+// concurrently, so that no update is lost.
+func (r *ReloadableList) update(modify func(*URLPatternList) (*URLPatternList, bool)) bool {
+	for {
+		current := r.list.Load()
+
+		list := current
+		if list == nil {
+			list = &URLPatternList{}
+		}
+
+		updated, changed := modify(list)
+		if !changed {
+			return false
+		}
+
+		if r.list.CompareAndSwap(current, updated) {
+			return true
+		}
+	}
+}
+
+// Match matches input against the current list, see URLPatternList.Match.
+func (r *ReloadableList) Match(input string) (*URLPattern, *URLPatternResult) {
+	list := r.list.Load()
+	if list == nil {
+		return nil, nil
+	}
+
+	return list.Match(input)
+}
+
+// Reload replaces the current list by the one returned by load. If load
+// returns an error, the current list is kept.
+func (r *ReloadableList) Reload(load func() (*URLPatternList, error)) error {
+	list, err := load()
+	if err != nil {
+		return err
+	}
+
+	r.Store(list)
+
+	return nil
+}
+
+// ReloadFile replaces the current list by the one parsed from the file at
+// path. If the file cannot be read or parsed, the current list is kept.
+func (r *ReloadableList) ReloadFile(path string, parse func(io.Reader) (*URLPatternList, error)) error {
+	return r.Reload(func() (*URLPatternList, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return parse(f)
+	})
+}
+
+// WatchFile checks the modification time of the file at path every interval,
+// and reloads the list with ReloadFile when it changes. Errors are passed to
+// onError, if not nil, and the current list is kept. WatchFile blocks until
+// ctx is done and returns ctx.Err().
+func (r *ReloadableList) WatchFile(ctx context.Context, path string, interval time.Duration, parse func(io.Reader) (*URLPatternList, error), onError func(error)) error {
+	var modTime time.Time
+	if fi, err := os.Stat(path); err == nil {
+		modTime = fi.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		fi, err := os.Stat(path)
+		if err == nil && fi.ModTime().Equal(modTime) {
+			continue
+		}
+
+		if err == nil {
+			modTime = fi.ModTime()
+			err = r.ReloadFile(path, parse)
+		}
+
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
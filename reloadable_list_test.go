@@ -0,0 +1,173 @@
+package urlpattern_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+// parseLines parses a list of constructor strings, one per line.
+func parseLines(r io.Reader) (*urlpattern.URLPatternList, error) {
+	list := &urlpattern.URLPatternList{}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		pattern, err := urlpattern.Compile(s.Text())
+		if err != nil {
+			return nil, err
+		}
+
+		list.Add(pattern)
+	}
+
+	return list, s.Err()
+}
+
+func TestReloadableList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.txt")
+	if err := os.WriteFile(path, []byte("https://example.com/books/:id\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	list := urlpattern.NewReloadableList(nil)
+	if p, _ := list.Match("https://example.com/books/1"); p != nil {
+		t.Errorf("empty list must not match")
+	}
+
+	if err := list.ReloadFile(path, parseLines); err != nil {
+		t.Fatal(err)
+	}
+
+	if p, _ := list.Match("https://example.com/books/1"); p == nil {
+		t.Errorf("reloaded list must match")
+	}
+
+	if err := os.WriteFile(path, []byte("https://example.com/books/:id(\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := list.ReloadFile(path, parseLines); !errors.Is(err, urlpattern.ErrTypeError) {
+		t.Errorf("want ErrTypeError; got %v", err)
+	}
+
+	if p, _ := list.Match("https://example.com/books/1"); p == nil {
+		t.Errorf("the previous list must be kept on error")
+	}
+}
+
+func TestReloadableListWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.txt")
+	if err := os.WriteFile(path, []byte("https://example.com/books/:id\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	list := urlpattern.NewReloadableList(nil)
+	if err := list.ReloadFile(path, parseLines); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error)
+	go func() {
+		done <- list.WatchFile(ctx, path, time.Millisecond, parseLines, func(err error) { t.Error(err) })
+	}()
+
+	if err := os.WriteFile(path, []byte("https://example.com/authors/:id\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for i := 1; ; i++ {
+		if p, _ := list.Match("https://example.com/authors/1"); p != nil {
+			break
+		}
+
+		// The watcher may have started after the write, change the
+		// modification time until it notices.
+		future := time.Now().Add(time.Duration(i) * time.Hour)
+		if err := os.Chtimes(path, future, future); err != nil {
+			t.Fatal(err)
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("the list has not been reloaded")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled; got %v", err)
+	}
+}
+
+func TestReloadableListUpdates(t *testing.T) {
+	var patterns []*urlpattern.URLPattern
+	for _, p := range []string{"https://example.com/books/:id", "https://example.com/authors/:id", "https://example.com/books/:id/reviews"} {
+		u, err := urlpattern.Compile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		patterns = append(patterns, u)
+	}
+
+	list := urlpattern.NewReloadableList(nil)
+	initial := list.Load()
+
+	list.Add(urlpattern.Route{Pattern: patterns[0], Name: "book"})
+	list.Add(urlpattern.Route{Pattern: patterns[1], Name: "author"})
+	if initial.Len() != 0 || list.Load().Len() != 2 {
+		t.Fatalf("lists must be copied on write, got %d and %d routes", initial.Len(), list.Load().Len())
+	}
+
+	if !list.Replace("book", urlpattern.Route{Pattern: patterns[2], Name: "reviews"}) {
+		t.Fatal("book must be replaced")
+	}
+	if routes := list.Load().Routes(); routes[0].Name != "reviews" || routes[1].Name != "author" {
+		t.Errorf("the insertion order must be kept, got %v", routes)
+	}
+	if p, _ := list.Match("https://example.com/books/1/reviews"); p != patterns[2] {
+		t.Error("the replacement must match")
+	}
+	if p, _ := list.Match("https://example.com/books/1"); p != nil {
+		t.Error("the replaced route must not match")
+	}
+
+	before := list.Load()
+	if !list.Remove("author") || list.Remove("author") || list.Replace("author", urlpattern.Route{Pattern: patterns[1]}) {
+		t.Error("author must be removed once")
+	}
+	if p, _ := list.Match("https://example.com/authors/1"); p != nil {
+		t.Error("the removed route must not match")
+	}
+	if p, _ := before.Match("https://example.com/authors/1"); p != patterns[1] {
+		t.Error("readers of the previous list must be unaffected")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for range 1000 {
+			list.Match("https://example.com/books/1/reviews")
+		}
+	}()
+
+	for i := range 100 {
+		list.Add(urlpattern.Route{Pattern: patterns[0], Name: strconv.Itoa(i)})
+	}
+	<-done
+
+	if n := list.Load().Len(); n != 101 {
+		t.Errorf("no update must be lost, got %d routes", n)
+	}
+}
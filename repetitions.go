@@ -0,0 +1,73 @@
+package urlpattern
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// maxRepetitionsLimit is the largest repetition count supported by RE2.
+const maxRepetitionsLimit = 1000
+
+// ErrInvalidMaxRepetitions is returned by Compile when the limit passed to
+// WithMaxRepetitions is out of range.
+var ErrInvalidMaxRepetitions = errors.New("invalid max repetitions")
+
+// WithMaxRepetitions limits to n the number of times the groups with a
+// one-or-more ("+") or zero-or-more ("*") modifier may repeat: with n set to
+// 10, "/files/:path+" matches paths up to 10 segments below "/files" and
+// nothing deeper, protecting services from the cost of matching and
+// extracting the groups of arbitrarily nested paths.
+//
+// The groups are compiled with bounded repetitions (e.g. "{1,10}") instead of
+// "+" and "*", so matching keeps the linear time guarantees of RE2. The
+// pattern strings are unchanged. As bounded repetitions are expanded by the
+// regexp compiler, n should be kept small. Compile returns
+// ErrInvalidMaxRepetitions if n isn't between 1 and 1000.
+func WithMaxRepetitions(n int) Option {
+	return func(c *config) {
+		if n < 1 || n > maxRepetitionsLimit {
+			c.setErr(ErrInvalidMaxRepetitions)
+
+			return
+		}
+
+		c.maxRepetitions = n
+	}
+}
+
+// writeModifier writes the regexp quantifier corresponding to modifier, see
+// convertModifierToString. Repetitions are bounded if options limits them.
+func writeModifier(result *strings.Builder, modifier partModifier, options options) {
+	switch {
+	case modifier == partModifierZeroOrMore && options.maxRepetitions > 0:
+		writeBoundedRepetition(result, 0, options.maxRepetitions)
+	case modifier == partModifierOneOrMore && options.maxRepetitions > 0:
+		writeBoundedRepetition(result, 1, options.maxRepetitions)
+	default:
+		if modifierToString := convertModifierToString(modifier); modifierToString != 0 {
+			result.WriteByte(modifierToString)
+		}
+	}
+}
+
+// writeFollowingRepetitions writes the quantifier of the repetitions following
+// the first one of a group with a one-or-more or zero-or-more modifier and a
+// prefix or a suffix.
+func writeFollowingRepetitions(result *strings.Builder, options options) {
+	if options.maxRepetitions == 0 {
+		result.WriteByte('*')
+
+		return
+	}
+
+	writeBoundedRepetition(result, 0, options.maxRepetitions-1)
+}
+
+func writeBoundedRepetition(result *strings.Builder, lower, upper int) {
+	result.WriteByte('{')
+	result.WriteString(strconv.Itoa(lower))
+	result.WriteByte(',')
+	result.WriteString(strconv.Itoa(upper))
+	result.WriteByte('}')
+}
@@ -0,0 +1,54 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestWithMaxRepetitions(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, input string
+		match          bool
+	}{
+		{"https://example.com/files/:path+", "https://example.com/files/a/b/c", true},
+		{"https://example.com/files/:path+", "https://example.com/files/a/b/c/d", false},
+		{"https://example.com/files/:path*", "https://example.com/files", true},
+		{"https://example.com/files/:path*", "https://example.com/files/a/b/c", true},
+		{"https://example.com/files/:path*", "https://example.com/files/a/b/c/d", false},
+		{"https://example.com/{(\\d)}+", "https://example.com/123", true},
+		{"https://example.com/{(\\d)}+", "https://example.com/1234", false},
+		{"https://example.com/{ab}*", "https://example.com/ababab", true},
+		{"https://example.com/{ab}*", "https://example.com/abababab", false},
+		{"https://example.com/files/*", "https://example.com/files/a/b/c/d", true},
+	} {
+		t.Run(tc.pattern+" "+tc.input, func(t *testing.T) {
+			pattern, err := urlpattern.Compile(tc.pattern, urlpattern.WithMaxRepetitions(3))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := pattern.Test(tc.input, ""); got != tc.match {
+				t.Errorf("want %v; got %v", tc.match, got)
+			}
+		})
+	}
+
+	pattern, err := urlpattern.Compile("https://example.com/files/:path+", urlpattern.WithMaxRepetitions(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pattern.Pathname(); got != "/files/:path+" {
+		t.Errorf("want pathname %q; got %q", "/files/:path+", got)
+	}
+	if got := pattern.Exec("https://example.com/files/a/b/c", "").Pathname.Groups["path"]; got != "a/b/c" {
+		t.Errorf("want %q; got %q", "a/b/c", got)
+	}
+
+	for _, n := range []int{0, -1, 1001} {
+		if _, err := urlpattern.Compile("https://example.com/*", urlpattern.WithMaxRepetitions(n)); !errors.Is(err, urlpattern.ErrInvalidMaxRepetitions) {
+			t.Errorf("WithMaxRepetitions(%d): want ErrInvalidMaxRepetitions; got %v", n, err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package urlpattern
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalJSON encodes the result with the structure returned by exec() in
+// browsers, so that snapshots and test fixtures can be shared with other
+// implementations:
+//
+//	{"inputs":["https://example.com/books/42"],"protocol":{"input":"https","groups":{}},...,"pathname":{"input":"/books/42","groups":{"id":"42"}},...}
+//
+// The inputs hold the URL strings or the URLPatternInit dictionaries, followed
+// by the base URL if any. Groups are in pattern order, and groups with an
+// optional or zero-or-more modifier which matched the empty string are null,
+// as they are undefined in browsers. SearchParams isn't encoded.
+func (r *URLPatternResult) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString(`{"inputs":[`)
+	if len(r.InitInputs) > 0 {
+		for i, init := range r.InitInputs {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := init.writeJSON(&b); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for i, input := range r.Inputs {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeJSON(&b, input); err != nil {
+				return nil, err
+			}
+		}
+	}
+	b.WriteByte(']')
+
+	for _, name := range componentNames {
+		b.WriteString(`,"` + name + `":`)
+		if err := r.component(name).writeJSON(&b); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
+
+func (r *URLPatternComponentResult) writeJSON(b *bytes.Buffer) error {
+	b.WriteString(`{"input":`)
+	if err := writeJSON(b, r.Input); err != nil {
+		return err
+	}
+
+	b.WriteString(`,"groups":{`)
+	first := true
+	for _, name := range r.groupNames {
+		if name == "" {
+			continue
+		}
+
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+
+		if err := writeJSON(b, name); err != nil {
+			return err
+		}
+		b.WriteByte(':')
+
+		// Optional groups which didn't participate are undefined in browsers.
+		if !r.Has(name) {
+			b.WriteString("null")
+
+			continue
+		}
+		// Groups is nil when all the groups matched the empty string.
+		if err := writeJSON(b, r.Groups[name]); err != nil {
+			return err
+		}
+	}
+	b.WriteString("}}")
+
+	return nil
+}
+
+// writeJSON writes the dictionary of init, without its nil members.
+func (init *URLPatternInit) writeJSON(b *bytes.Buffer) error {
+	b.WriteByte('{')
+	first := true
+	for _, m := range []struct {
+		name  string
+		value *string
+	}{
+		{"protocol", init.Protocol},
+		{"username", init.Username},
+		{"password", init.Password},
+		{"hostname", init.Hostname},
+		{"port", init.Port},
+		{"pathname", init.Pathname},
+		{"search", init.Search},
+		{"hash", init.Hash},
+		{"baseURL", init.BaseURL},
+	} {
+		if m.value == nil {
+			continue
+		}
+
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+
+		b.WriteString(`"` + m.name + `":`)
+		if err := writeJSON(b, *m.value); err != nil {
+			return err
+		}
+	}
+	b.WriteByte('}')
+
+	return nil
+}
+
+// writeJSON writes the JSON encoding of s, without escaping HTML characters as
+// browsers do.
+func writeJSON(b *bytes.Buffer, s string) error {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+
+	// Remove the newline added by Encode.
+	b.Truncate(b.Len() - 1)
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package urlpattern_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func ExampleURLPatternResult_MarshalJSON() {
+	pattern, err := urlpattern.Compile("https://example.com/books/:id{.:format}?")
+	if err != nil {
+		panic(err)
+	}
+
+	data, err := json.Marshal(pattern.Exec("https://example.com/books/42", ""))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(string(data))
+	// Output:
+	// {"inputs":["https://example.com/books/42"],"protocol":{"input":"https","groups":{}},"username":{"input":"","groups":{"0":""}},"password":{"input":"","groups":{"0":""}},"hostname":{"input":"example.com","groups":{}},"port":{"input":"","groups":{}},"pathname":{"input":"/books/42","groups":{"id":"42","format":null}},"search":{"input":"","groups":{"0":""}},"hash":{"input":"","groups":{"0":""}}}
+}
+
+func TestResultMarshalJSONInit(t *testing.T) {
+	pathname := "/books/:id"
+	pattern, err := urlpattern.Compile(&urlpattern.URLPatternInit{Pathname: &pathname})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputPathname, baseURL := "/books/<42>", "https://example.com"
+	r := pattern.ExecInit(&urlpattern.URLPatternInit{Pathname: &inputPathname, BaseURL: &baseURL})
+	if r == nil {
+		t.Fatal("want match")
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Inputs   []map[string]string
+		Pathname struct {
+			Input  string
+			Groups map[string]*string
+		}
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got.Inputs) != 1 || got.Inputs[0]["pathname"] != "/books/<42>" || got.Inputs[0]["baseURL"] != baseURL {
+		t.Errorf("unexpected inputs %v", got.Inputs)
+	}
+	if id := got.Pathname.Groups["id"]; id == nil || *id != "%3C42%3E" {
+		t.Errorf("unexpected pathname %s", data)
+	}
+}
+
+func TestResultMarshalJSONEmptyOptionalGroup(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://example.com/books/:id(x*)?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for input, want := range map[string]string{
+		"https://example.com/books/": `{"id":""}`,
+		"https://example.com/books":  `{"id":null}`,
+	} {
+		data, err := json.Marshal(pattern.Exec(input, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got struct {
+			Pathname struct {
+				Groups json.RawMessage
+			}
+		}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got.Pathname.Groups) != want {
+			t.Errorf("%s: want groups %s; got %s", input, want, got.Pathname.Groups)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidResultMapper is returned by Compile when the chain passed to
+// WithResultMapper is empty or contains nil mappers, or when its group name
+// is invalid or not found in the pattern.
+var ErrInvalidResultMapper = errors.New("invalid result mapper")
+
+// ResultMapper transforms the value of a group, see WithResultMapper. The
+// first mapper of a chain receives the string value of the group, the next
+// ones the value returned by the previous mapper.
+type ResultMapper func(value any) (any, error)
+
+// WithResultMapper transforms the values of the groups named name when
+// matching, by passing them through chain in order. The final values are
+// stored in the TypedGroups field of the component results, the Groups field
+// is unchanged. If a mapper returns an error, the URL doesn't match. For
+// instance, to get identifiers as integers:
+//
+//	pattern, err := urlpattern.Compile("https://example.com/books/:id", urlpattern.WithResultMapper("id", urlpattern.MapTrimSpace, urlpattern.MapAtoi))
+//	result := pattern.Exec("https://example.com/books/42", "")
+//	id := result.Pathname.TypedGroups["id"].(int)
+//
+// Mappers are applied after the validators added with WithGroupValidator.
+// Groups without mappers are stored in TypedGroups as strings, and optional
+// groups which didn't participate in the match (see
+// URLPatternComponentResult.Has) as empty strings: check Has before asserting
+// the type of their values. Required groups are mapped even when they matched
+// the empty string. Adding a chain for a group
+// which already has one replaces it.
+//
+// Compile returns ErrInvalidResultMapper if chain is empty or contains nil
+// mappers, if name isn't a valid group name, or if the pattern has no group
+// named name.
+func WithResultMapper(name string, chain ...ResultMapper) Option {
+	return func(c *config) {
+		if len(chain) == 0 || slices.ContainsFunc(chain, func(m ResultMapper) bool { return m == nil }) || (!isValidName(name) && !IsAnonymousGroupName(name)) {
+			c.setErr(fmt.Errorf("%w: %q", ErrInvalidResultMapper, name))
+
+			return
+		}
+
+		if c.resultMappers == nil {
+			c.resultMappers = make(map[string][]ResultMapper)
+		}
+		c.resultMappers[name] = slices.Clone(chain)
+	}
+}
+
+// MapTrimSpace removes the leading and trailing white space of a string.
+func MapTrimSpace(value any) (any, error) {
+	s, err := mappedString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.TrimSpace(s), nil
+}
+
+// MapToLower converts a string to lowercase.
+func MapToLower(value any) (any, error) {
+	s, err := mappedString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.ToLower(s), nil
+}
+
+// MapPathUnescape decodes the percent-encoded sequences of a string, see
+// net/url.PathUnescape.
+func MapPathUnescape(value any) (any, error) {
+	s, err := mappedString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return url.PathUnescape(s)
+}
+
+// MapAtoi parses a decimal integer into an int, see strconv.Atoi.
+func MapAtoi(value any) (any, error) {
+	s, err := mappedString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return strconv.Atoi(s)
+}
+
+// MapParseBool parses a boolean, see strconv.ParseBool.
+func MapParseBool(value any) (any, error) {
+	s, err := mappedString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return strconv.ParseBool(s)
+}
+
+// mappedString returns value, which must be a string.
+func mappedString(value any) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %T is not a string", ErrInvalidResultMapper, value)
+	}
+
+	return s, nil
+}
+
+// checkResultMappers returns ErrInvalidResultMapper if a chain of mappers of u
+// applies to no group.
+func (u *URLPattern) checkResultMappers() error {
+	for name := range u.config.resultMappers {
+		if !slices.ContainsFunc(componentNames[:], func(component string) bool {
+			return slices.Contains(u.component(component).groupNames, name)
+		}) {
+			return fmt.Errorf("%w: group %q not found", ErrInvalidResultMapper, name)
+		}
+	}
+
+	return nil
+}
+
+// mapGroups sets the TypedGroups of the components of r, and reports whether
+// all the mappers of u succeeded.
+func (u *URLPattern) mapGroups(r *URLPatternResult) bool {
+	if u.config.resultMappers == nil {
+		return true
+	}
+
+	for _, name := range componentNames {
+		// Groups is nil when the component matched the empty string, so the
+		// groups are looked up by name.
+		c := r.component(name)
+		if len(c.groupNames) < 2 {
+			continue
+		}
+
+		c.TypedGroups = make(map[string]any, len(c.groupNames)-1)
+		for _, group := range c.groupNames {
+			if group == "" {
+				continue
+			}
+
+			value := c.Groups[group]
+			chain, ok := u.config.resultMappers[group]
+			if !ok || !c.Has(group) {
+				c.TypedGroups[group] = value
+
+				continue
+			}
+
+			var typed any = value
+			for _, m := range chain {
+				var err error
+				if typed, err = m(typed); err != nil {
+					return false
+				}
+			}
+			c.TypedGroups[group] = typed
+		}
+	}
+
+	return true
+}
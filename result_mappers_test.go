@@ -0,0 +1,91 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestWithResultMapper(t *testing.T) {
+	u, err := urlpattern.Compile("https://example.com/:lang/books/:id/:title{/:draft}?",
+		urlpattern.WithResultMapper("lang", urlpattern.MapToLower),
+		urlpattern.WithResultMapper("id", urlpattern.MapAtoi),
+		urlpattern.WithResultMapper("title", urlpattern.MapPathUnescape, urlpattern.MapTrimSpace),
+		urlpattern.WithResultMapper("draft", urlpattern.MapParseBool),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := u.Exec("https://example.com/EN/books/42/%20Les%20Mis%C3%A9rables/true", "")
+	if r == nil {
+		t.Fatal("must match")
+	}
+	if r.Pathname.Groups["id"] != "42" {
+		t.Errorf("groups must not be transformed, got %q", r.Pathname.Groups)
+	}
+	for name, expected := range map[string]any{"lang": "en", "id": 42, "title": "Les Misérables", "draft": true} {
+		if r.Pathname.TypedGroups[name] != expected {
+			t.Errorf("%s: got %#v, want %#v", name, r.Pathname.TypedGroups[name], expected)
+		}
+	}
+	if len(r.Hostname.TypedGroups) != 0 {
+		t.Errorf("unexpected hostname groups %#v", r.Hostname.TypedGroups)
+	}
+
+	r = u.Exec("https://example.com/en/books/42/title", "")
+	if r == nil || r.Pathname.TypedGroups["draft"] != "" {
+		t.Errorf("unexpected result %#v", r)
+	}
+
+	r.Pathname.RenameGroups(map[string]string{"id": "book"})
+	if r.Pathname.TypedGroups["book"] != 42 {
+		t.Errorf("typed groups must be renamed, got %#v", r.Pathname.TypedGroups)
+	}
+
+	for _, input := range []string{"https://example.com/en/books/abc/title", "https://example.com/en/books/42/title/maybe"} {
+		if u.Test(input, "") {
+			t.Errorf("%q must not match", input)
+		}
+	}
+
+	u, err = urlpattern.Compile("https://example.com/books/:id", urlpattern.WithResultMapper("id", urlpattern.MapAtoi, urlpattern.MapToLower))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Test("https://example.com/books/42", "") {
+		t.Error("mappers expecting strings must fail on other types")
+	}
+
+	for _, opt := range []urlpattern.Option{
+		urlpattern.WithResultMapper("id"),
+		urlpattern.WithResultMapper("id", nil),
+		urlpattern.WithResultMapper("a-b", urlpattern.MapAtoi),
+		urlpattern.WithResultMapper("slug", urlpattern.MapAtoi),
+	} {
+		if _, err := urlpattern.Compile("https://example.com/books/:id", opt); !errors.Is(err, urlpattern.ErrInvalidResultMapper) {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+
+	// Components matching the empty string have required groups too.
+	u, err = urlpattern.Compile("https://example.com/books?:q(\\d*)", urlpattern.WithResultMapper("q", urlpattern.MapAtoi))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Test("https://example.com/books", "") {
+		t.Error("mappers must apply to groups matching the empty string")
+	}
+	if r := u.Exec("https://example.com/books?12", ""); r == nil || r.Search.TypedGroups["q"] != 12 {
+		t.Errorf("unexpected result %v", r)
+	}
+
+	u, err = urlpattern.Compile("https://example.com/books/:id?", urlpattern.WithResultMapper("id", urlpattern.MapAtoi))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := u.Exec("https://example.com/books", ""); r == nil || r.Pathname.Has("id") || r.Pathname.TypedGroups["id"] != "" {
+		t.Errorf("missing optional groups must be stored as empty strings, got %v", r)
+	}
+}
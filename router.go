@@ -0,0 +1,167 @@
+package urlpattern
+
+import (
+	"errors"
+	"maps"
+)
+
+// ErrInvalidMountPattern is returned by Router.Mount when the pathname
+// pattern doesn't end with a "*" wildcard.
+var ErrInvalidMountPattern = errors.New(`mount pattern must end with a "*" wildcard`)
+
+// Router is a hierarchical router: patterns are matched in insertion order,
+// and sub-routers can be mounted under a pathname prefix, as in chi or echo.
+//
+// The zero value is ready to use. Add and Mount must not be called
+// concurrently with other methods.
+type Router struct {
+	entries []routerEntry
+}
+
+type routerEntry struct {
+	pattern *URLPattern
+	// sub is the router mounted under pattern, nil for routes.
+	sub *Router
+	// remainder is the name of the group capturing the remainder of the
+	// pathname, for mounts.
+	remainder string
+}
+
+// Add appends a route to the router.
+func (r *Router) Add(pattern *URLPattern) {
+	r.entries = append(r.entries, routerEntry{pattern: pattern})
+}
+
+// Mount mounts sub under pathname, a pathname pattern ending with a "*"
+// wildcard such as "/api/*". The remainder of the pathname captured by the
+// wildcard, prefixed by "/", is matched against the routes of sub: mounted
+// under "/api/*", a route of sub with the pathname "/users/:id" matches
+// "https://example.com/api/users/42".
+//
+// The other components of the URL are matched as is against sub. Options
+// apply to the compilation of the mount pattern.
+func (r *Router) Mount(pathname string, sub *Router, opts ...Option) error {
+	pattern, err := Compile(&URLPatternInit{Pathname: &pathname}, opts...)
+	if err != nil {
+		return err
+	}
+
+	pl := pattern.pathname.partList
+	if len(pl) == 0 {
+		return ErrInvalidMountPattern
+	}
+
+	last := pl[len(pl)-1]
+	if last.pType != partFullWildcard || last.suffix != "" || last.modifier != partModifierNone {
+		return ErrInvalidMountPattern
+	}
+
+	r.entries = append(r.entries, routerEntry{pattern: pattern, sub: sub, remainder: last.name})
+
+	return nil
+}
+
+// Match returns the first route matching input, and the result of the match.
+// The groups captured by the mount patterns leading to the route are merged
+// in the result, the groups of the route taking precedence. The Input of the
+// pathname result is the pathname of input. If no route matches, it returns
+// nil and a nil result.
+func (r *Router) Match(input string) (*URLPattern, *URLPatternResult) {
+	pattern, result := r.match(func(u *URLPattern) *URLPatternResult {
+		return u.Exec(input, "")
+	})
+	if result != nil {
+		result.Inputs = []string{input}
+	}
+
+	return pattern, result
+}
+
+// match returns the first route matching according to exec.
+func (r *Router) match(exec func(*URLPattern) *URLPatternResult) (*URLPattern, *URLPatternResult) {
+	for _, e := range r.entries {
+		result := exec(e.pattern)
+		if result == nil {
+			continue
+		}
+
+		if e.sub == nil {
+			return e.pattern, result
+		}
+
+		input := CanonicalURL{
+			Protocol: result.Protocol.Input,
+			Username: result.Username.Input,
+			Password: result.Password.Input,
+			Hostname: result.Hostname.Input,
+			Port:     result.Port.Input,
+			Pathname: "/" + result.Pathname.Groups[e.remainder],
+			Search:   result.Search.Input,
+			Hash:     result.Hash.Input,
+		}
+
+		pattern, subResult := e.sub.match(func(u *URLPattern) *URLPatternResult {
+			return u.ExecCanonical(input)
+		})
+		if subResult == nil {
+			continue
+		}
+
+		delete(result.Pathname.Groups, e.remainder)
+		mergeGroups(&subResult.Protocol, result.Protocol)
+		mergeGroups(&subResult.Username, result.Username)
+		mergeGroups(&subResult.Password, result.Password)
+		mergeGroups(&subResult.Hostname, result.Hostname)
+		mergeGroups(&subResult.Port, result.Port)
+		mergeGroups(&subResult.Pathname, result.Pathname)
+		mergeGroups(&subResult.Search, result.Search)
+		mergeGroups(&subResult.Hash, result.Hash)
+		subResult.Pathname.Input = result.Pathname.Input
+
+		return pattern, subResult
+	}
+
+	return nil, nil
+}
+
+// mergeGroups adds the groups of parent missing from r, before the groups of
+// r in the order returned by OrderedGroups.
+func mergeGroups(r *URLPatternComponentResult, parent URLPatternComponentResult) {
+	if len(parent.Groups) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(parent.groupNames)+len(r.groupNames))
+	for _, name := range parent.groupNames {
+		if _, ok := parent.Groups[name]; ok {
+			if _, ok := r.Groups[name]; !ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	groups := maps.Clone(parent.Groups)
+	maps.Copy(groups, r.Groups)
+	r.Groups = groups
+
+	if parent.TypedGroups != nil {
+		typedGroups := maps.Clone(parent.TypedGroups)
+		maps.Copy(typedGroups, r.TypedGroups)
+		r.TypedGroups = typedGroups
+	}
+
+	if parent.repeatSeparators != nil {
+		// repeatSeparators is shared with the component of r.
+		repeatSeparators := maps.Clone(r.repeatSeparators)
+		if repeatSeparators == nil {
+			repeatSeparators = make(map[string]string)
+		}
+		for _, name := range names {
+			if separator, ok := parent.repeatSeparators[name]; ok {
+				repeatSeparators[name] = separator
+			}
+		}
+		r.repeatSeparators = repeatSeparators
+	}
+	r.groupNames = append(names, r.groupNames...)
+}
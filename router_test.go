@@ -0,0 +1,59 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestRouter(t *testing.T) {
+	mustCompile := func(pathname string) *urlpattern.URLPattern {
+		p, err := urlpattern.Compile(&urlpattern.URLPatternInit{Pathname: &pathname})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return p
+	}
+
+	var users urlpattern.Router
+	user := mustCompile("/users/:id")
+	users.Add(user)
+
+	var api urlpattern.Router
+	if err := api.Mount("/v:version/*", &users); err != nil {
+		t.Fatal(err)
+	}
+
+	var root urlpattern.Router
+	home := mustCompile("/")
+	root.Add(home)
+	if err := root.Mount("/api/*", &api); err != nil {
+		t.Fatal(err)
+	}
+
+	pattern, r := root.Match("https://example.com/api/v2/users/42?q")
+	if pattern != user {
+		t.Fatalf("want the user pattern; got %v", pattern)
+	}
+
+	if want := []urlpattern.Group{{Name: "version", Value: "2"}, {Name: "id", Value: "42"}}; !reflect.DeepEqual(want, r.Pathname.OrderedGroups()) {
+		t.Errorf("want %#v; got %#v", want, r.Pathname.OrderedGroups())
+	}
+	if r.Pathname.Input != "/api/v2/users/42" || r.Search.Input != "q" || r.Inputs[0] != "https://example.com/api/v2/users/42?q" {
+		t.Errorf("unexpected result %#v", r)
+	}
+
+	if pattern, _ := root.Match("https://example.com/"); pattern != home {
+		t.Errorf("want the home pattern; got %v", pattern)
+	}
+	if pattern, r := root.Match("https://example.com/api/v2/books/42"); pattern != nil || r != nil {
+		t.Errorf("want no match; got %v", pattern)
+	}
+
+	if err := root.Mount("/api/*/v1", &api); !errors.Is(err, urlpattern.ErrInvalidMountPattern) {
+		t.Errorf("want ErrInvalidMountPattern; got %v", err)
+	}
+}
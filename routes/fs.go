@@ -0,0 +1,121 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+// ErrNoFiles is returned by LoadFS when a glob matches no file.
+var ErrNoFiles = errors.New("no route files")
+
+// LoadFS loads the route tables of fsys matching glob (see fs.Glob), in
+// lexical order, into a single URLPatternList. The format of each table is
+// deduced from its extension: ".yaml", ".yml" or ".toml", see Load.
+// This allows binaries to ship their routing tables with go:embed:
+//
+//	//go:embed routes
+//	var tables embed.FS
+//
+//	list, err := routes.LoadFS(tables, "routes/*.yaml")
+//
+// Tables can include other tables with a list of globs, relative to the
+// directory of the including table. The routes of the included tables are
+// added before the routes of the including one. In YAML:
+//
+//	include:
+//	  - admin/*.yaml
+//	routes:
+//	  - pattern: https://example.com/books/:id
+//
+// In TOML:
+//
+//	include = ["admin/*.toml"]
+//
+// Each table is loaded once, even if it is matched or included several times.
+// ErrNoFiles is returned if glob or an include matches no file. The errors of
+// all the invalid routes are aggregated in an Errors, whose *Error report the
+// path of their table.
+func LoadFS(fsys fs.FS, glob string) (*urlpattern.URLPatternList, error) {
+	l := loader{fsys: fsys, list: &urlpattern.URLPatternList{}, loaded: make(map[string]bool)}
+	if err := l.loadGlob(glob); err != nil {
+		return nil, err
+	}
+
+	if err := l.errs.err(); err != nil {
+		return nil, err
+	}
+
+	return l.list, nil
+}
+
+// loader loads route tables and their includes from a file system.
+type loader struct {
+	fsys   fs.FS
+	list   *urlpattern.URLPatternList
+	loaded map[string]bool
+	// errs holds the invalid routes of all the tables.
+	errs Errors
+}
+
+func (l *loader) loadGlob(glob string) error {
+	names, err := fs.Glob(l.fsys, glob)
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("%w matching %q", ErrNoFiles, glob)
+	}
+
+	for _, name := range names {
+		if err := l.load(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *loader) load(name string) error {
+	if l.loaded[name] {
+		return nil
+	}
+	l.loaded[name] = true
+
+	f, err := l.fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	table, err := decodeRoutes(f, strings.TrimPrefix(path.Ext(name), "."))
+
+	// The includes of tables with invalid routes are still loaded, to report
+	// the errors of all the tables at once.
+	var routeErrs Errors
+	if errors.As(err, &routeErrs) {
+		for _, e := range routeErrs {
+			e.File = name
+		}
+		l.errs = append(l.errs, routeErrs...)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	for _, include := range table.include {
+		if err := l.loadGlob(path.Join(path.Dir(name), include)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	for _, route := range table.routes {
+		l.list.AddRoute(route)
+	}
+
+	return nil
+}
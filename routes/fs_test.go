@@ -0,0 +1,74 @@
+package routes_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dunglas/go-urlpattern/routes"
+)
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"routes/books.yaml":      {Data: []byte("include:\n  - admin/*\nroutes:\n  - pattern: https://example.com/books/:id\n    name: book\n")},
+		"routes/authors.toml":    {Data: []byte("include = [\"books.yaml\"]\n\n[[routes]]\npattern = \"https://example.com/authors/:id\"\nname = \"author\"\n")},
+		"routes/admin/users.yml": {Data: []byte("include:\n  - ../books.yaml\nroutes:\n  - pattern: https://example.com/admin/users\n    name: users\n")},
+		"routes/README.md":       {Data: []byte("not a table")},
+	}
+
+	list, err := routes.LoadFS(fsys, "routes/*.[ty][oa]ml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, r := range list.Routes() {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "users,book,author" {
+		t.Errorf("unexpected routes %s", got)
+	}
+
+	if m := list.Exec("https://example.com/books/1"); m == nil || m.Route.Name != "book" {
+		t.Errorf("unexpected match %v", m)
+	}
+}
+
+func TestLoadFSErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.yaml": {Data: []byte("routes:\n  - pattern: https://example.com/(\n")},
+		"b.toml": {Data: []byte("[[routes]]\nname = \"book\"\n")},
+		"c.yaml": {Data: []byte("include:\n  - missing/*.yaml\n")},
+		"d.json": {Data: []byte("{}")},
+		"e.yaml": {Data: []byte("include:\n  - b.toml\nroutes:\n  - pattern: https://example.com/(\n")},
+	}
+
+	_, err := routes.LoadFS(fsys, "[ab].*")
+
+	var routeErrors routes.Errors
+	if !errors.As(err, &routeErrors) || len(routeErrors) != 2 {
+		t.Fatalf("want 2 route errors; got %v", err)
+	}
+	if routeErrors[0].File != "a.yaml" || routeErrors[0].Line != 2 || routeErrors[1].File != "b.toml" {
+		t.Errorf("unexpected errors %q", err)
+	}
+	if !strings.HasPrefix(routeErrors[0].Error(), "a.yaml: line 2: route #0: ") {
+		t.Errorf("unexpected error %q", routeErrors[0])
+	}
+
+	_, err = routes.LoadFS(fsys, "e.yaml")
+	if !errors.As(err, &routeErrors) || len(routeErrors) != 2 || routeErrors[0].File != "e.yaml" || routeErrors[1].File != "b.toml" {
+		t.Errorf("want the errors of the included tables; got %v", err)
+	}
+
+	if _, err := routes.LoadFS(fsys, "c.yaml"); !errors.Is(err, routes.ErrNoFiles) {
+		t.Errorf("want ErrNoFiles; got %v", err)
+	}
+	if _, err := routes.LoadFS(fsys, "d.json"); !errors.Is(err, routes.ErrUnknownFormat) {
+		t.Errorf("want ErrUnknownFormat; got %v", err)
+	}
+	if _, err := routes.Load(strings.NewReader("include:\n  - a.yaml\n"), "yaml"); !errors.Is(err, routes.ErrUnsupportedInclude) {
+		t.Errorf("want ErrUnsupportedInclude; got %v", err)
+	}
+}
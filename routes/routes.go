@@ -0,0 +1,304 @@
+// Package routes loads YAML and TOML route tables into URLPatternList. It is
+// separate from the urlpattern package so that programs which don't load
+// route tables don't depend on the YAML and TOML decoders.
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/dunglas/go-urlpattern"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrUnknownFormat is returned by Load for unsupported formats.
+	ErrUnknownFormat = errors.New(`unknown routes format, must be "yaml" or "toml"`)
+	// ErrMissingPattern is returned by Load for routes without pattern.
+	ErrMissingPattern = errors.New("missing route pattern")
+	// ErrUnsupportedInclude is returned by Load for tables including other
+	// files, which are only supported by LoadFS.
+	ErrUnsupportedInclude = errors.New("includes are only supported by LoadFS")
+)
+
+// Error is returned by Load when a route is invalid.
+type Error struct {
+	// File is the path of the table in the file system passed to LoadFS,
+	// or the empty string for Load.
+	File string
+	// Line is the line of the route pattern, starting at 1, or 0 if
+	// unknown.
+	Line int
+	// Index is the index of the route in the table.
+	Index int
+	Name  string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	route := fmt.Sprintf("route #%d", e.Index)
+	if e.Name != "" {
+		route = fmt.Sprintf("route %q", e.Name)
+	}
+
+	if e.Line != 0 {
+		route = fmt.Sprintf("line %d: %s", e.Line, route)
+	}
+	if e.File != "" {
+		route = e.File + ": " + route
+	}
+
+	return fmt.Sprintf("%s: %s", route, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errors aggregates the errors of all the invalid routes of a table, in
+// the order of the table, so that a whole file can be fixed in one pass.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	var b strings.Builder
+	for i, err := range e {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(err.Error())
+	}
+
+	return b.String()
+}
+
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// err returns e as an error, or nil if empty.
+func (e Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// routeDefinition is the schema of a route in a route table.
+type routeDefinition struct {
+	Pattern    string         `toml:"pattern"    yaml:"pattern"`
+	Name       string         `toml:"name"       yaml:"name"`
+	Priority   int            `toml:"priority"   yaml:"priority"`
+	IgnoreCase bool           `toml:"ignoreCase" yaml:"ignoreCase"`
+	Metadata   map[string]any `toml:"metadata"   yaml:"metadata"`
+}
+
+// route compiles the route.
+func (d *routeDefinition) route() (urlpattern.Route, error) {
+	if d.Pattern == "" {
+		return urlpattern.Route{}, ErrMissingPattern
+	}
+
+	var opts []urlpattern.Option
+	if d.IgnoreCase {
+		opts = append(opts, urlpattern.WithIgnoreCase())
+	}
+
+	pattern, err := urlpattern.Compile(d.Pattern, opts...)
+	if err != nil {
+		return urlpattern.Route{}, err
+	}
+
+	route := urlpattern.Route{Pattern: pattern, Name: d.Name, Priority: d.Priority}
+	if d.Metadata != nil {
+		route.Metadata = d.Metadata
+	}
+
+	return route, nil
+}
+
+// Load parses a route table in the given format, "yaml" (or "yml") or
+// "toml", into a URLPatternList. Patterns are constructor strings, and the
+// metadata of the routes are of type map[string]any. In YAML:
+//
+//	routes:
+//	  - pattern: https://example.com/books/:id
+//	    name: book           # optional
+//	    priority: 10         # optional, routes with a higher priority are matched first
+//	    ignoreCase: true     # optional, see urlpattern.WithIgnoreCase
+//	    metadata:            # optional
+//	      cache: 3600
+//
+// In TOML:
+//
+//	[[routes]]
+//	pattern = "https://example.com/books/:id"
+//	name = "book"
+//	priority = 10
+//	ignoreCase = true
+//	metadata = { cache = 3600 }
+//
+// Invalid routes are reported with an Errors containing an *Error, with
+// the line of the route, for each invalid route.
+//
+// Tables including other files (see LoadFS) are rejected with
+// ErrUnsupportedInclude.
+func Load(r io.Reader, format string) (*urlpattern.URLPatternList, error) {
+	table, err := decodeRoutes(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(table.include) > 0 {
+		return nil, ErrUnsupportedInclude
+	}
+
+	list := &urlpattern.URLPatternList{}
+	for _, route := range table.routes {
+		list.AddRoute(route)
+	}
+
+	return list, nil
+}
+
+// routeTable is a decoded route table.
+type routeTable struct {
+	routes []urlpattern.Route
+	// include holds the globs of the included tables.
+	include []string
+}
+
+// decodeRoutes decodes and compiles a route table in the given format, see
+// Load. Invalid routes are reported with an Errors, along with the valid
+// routes and the includes of the table.
+func decodeRoutes(r io.Reader, format string) (routeTable, error) {
+	switch format {
+	case "yaml", "yml":
+		return decodeYAMLRoutes(r)
+	case "toml":
+		return decodeTOMLRoutes(r)
+	}
+
+	return routeTable{}, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+func decodeYAMLRoutes(r io.Reader) (routeTable, error) {
+	var table struct {
+		Include []string    `yaml:"include"`
+		Routes  []yaml.Node `yaml:"routes"`
+	}
+	if err := yaml.NewDecoder(r).Decode(&table); err != nil && !errors.Is(err, io.EOF) {
+		return routeTable{}, err
+	}
+
+	var errs Errors
+	result := routeTable{include: table.Include}
+	for i, node := range table.Routes {
+		var d routeDefinition
+		if err := node.Decode(&d); err != nil {
+			errs = append(errs, &Error{Line: node.Line, Index: i, Err: err})
+
+			continue
+		}
+
+		route, err := d.route()
+		if err != nil {
+			line := node.Line
+			for j := 0; j+1 < len(node.Content); j += 2 {
+				if node.Content[j].Value == "pattern" {
+					line = node.Content[j+1].Line
+				}
+			}
+
+			errs = append(errs, &Error{Line: line, Index: i, Name: d.Name, Err: err})
+
+			continue
+		}
+
+		result.routes = append(result.routes, route)
+	}
+
+	return result, errs.err()
+}
+
+func decodeTOMLRoutes(r io.Reader) (routeTable, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return routeTable{}, err
+	}
+
+	var table struct {
+		Include []string          `toml:"include"`
+		Routes  []routeDefinition `toml:"routes"`
+	}
+	if _, err := toml.Decode(string(data), &table); err != nil {
+		return routeTable{}, err
+	}
+
+	var errs Errors
+	result := routeTable{include: table.Include}
+	for i, d := range table.Routes {
+		route, err := d.route()
+		if err != nil {
+			errs = append(errs, &Error{Index: i, Name: d.Name, Err: err})
+
+			continue
+		}
+
+		result.routes = append(result.routes, route)
+	}
+
+	if len(errs) == 0 {
+		return result, nil
+	}
+
+	lines := tomlPatternLines(data, len(table.Routes))
+	for _, err := range errs {
+		if !errors.Is(err, ErrMissingPattern) {
+			err.Line = lines[err.Index]
+		}
+	}
+
+	return result, errs
+}
+
+// tomlPatternLines returns the line of the pattern of each of the n routes
+// of the table in data, or 0 if unknown. The TOML decoder doesn't report the
+// position of the values, so the lines are scanned once for the pattern key
+// of each [[routes]] table. Routes declared with inline tables aren't found.
+func tomlPatternLines(data []byte, n int) []int {
+	lines := make([]int, n)
+
+	var (
+		route   = -1
+		inRoute bool
+	)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "[["):
+			header, _, _ := strings.Cut(line[2:], "]]")
+			if inRoute = strings.TrimSpace(header) == "routes"; inRoute {
+				route++
+			}
+		case strings.HasPrefix(line, "["):
+			inRoute = false
+		case inRoute && route < n && lines[route] == 0:
+			key, _, ok := strings.Cut(line, "=")
+			if ok && strings.Trim(strings.TrimSpace(key), `"'`) == "pattern" {
+				lines[route] = i + 1
+			}
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,128 @@
+package routes_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+	"github.com/dunglas/go-urlpattern/routes"
+)
+
+const yamlRoutes = `routes:
+  - pattern: https://example.com/*
+    name: fallback
+  - pattern: https://example.com/books/:id
+    name: book
+    priority: 10
+    metadata:
+      cache: 3600
+  - pattern: https://example.com/AUTHORS/:id
+    ignoreCase: true
+`
+
+const tomlRoutes = `[[routes]]
+pattern = "https://example.com/*"
+name = "fallback"
+
+[[routes]]
+pattern = "https://example.com/books/:id"
+name = "book"
+priority = 10
+metadata = { cache = 3600 }
+
+[[routes]]
+pattern = "https://example.com/AUTHORS/:id"
+ignoreCase = true
+`
+
+func TestLoad(t *testing.T) {
+	for format, table := range map[string]string{"yaml": yamlRoutes, "toml": tomlRoutes} {
+		t.Run(format, func(t *testing.T) {
+			list, err := routes.Load(strings.NewReader(table), format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded := list.Routes()
+			if len(loaded) != 3 || loaded[1].Name != "book" || loaded[1].Priority != 10 {
+				t.Fatalf("unexpected routes %#v", loaded)
+			}
+
+			if cache := loaded[1].Metadata.(map[string]any)["cache"]; reflect.ValueOf(cache).Int() != 3600 {
+				t.Errorf("unexpected metadata %#v", loaded[1].Metadata)
+			}
+
+			if p, _ := list.Match("https://example.com/books/1"); p != loaded[1].Pattern {
+				t.Errorf("the route with the highest priority must match first, got %v", p)
+			}
+
+			if p, _ := list.Match("https://example.com/authors/1"); p != loaded[0].Pattern {
+				t.Errorf("the first route must match, got %v", p)
+			}
+
+			if p, _ := list.Match("https://example.org/authors/1"); p != nil {
+				t.Errorf("no route must match, got %v", p)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, tc := range []struct {
+		format, table string
+		line          int
+		want          error
+		message       string
+	}{
+		{"yaml", "routes:\n  - pattern: https://example.com/\n  - name: book\n    pattern: https://example.com/books/:id(\n", 4, urlpattern.ErrTypeError, `line 4: route "book": `},
+		{"yaml", "routes:\n  - pattern: https://example.com/\n  - name: book\n", 3, routes.ErrMissingPattern, `line 3: route "book": `},
+		{"toml", "[[routes]]\npattern = \"https://example.com/\"\n\n[[routes]]\npattern = \"https://example.com/books/:id(\"\n", 5, urlpattern.ErrTypeError, "line 5: route #1: "},
+		{"toml", "[[routes]] # home\npattern = \"https://example.com/\"\n[routes.metadata]\npattern = \"(\"\n\n[[ routes ]]\n\"pattern\" = \"https://example.com/books/:id(\"\n", 7, urlpattern.ErrTypeError, "line 7: route #1: "},
+		{"toml", "[[routes]]\nname = \"book\"\n", 0, routes.ErrMissingPattern, `route "book": `},
+	} {
+		t.Run(tc.message, func(t *testing.T) {
+			_, err := routes.Load(strings.NewReader(tc.table), tc.format)
+
+			var routeError *routes.Error
+			if !errors.As(err, &routeError) || !errors.Is(err, tc.want) {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if routeError.Line != tc.line || !strings.HasPrefix(err.Error(), tc.message) {
+				t.Errorf("unexpected error %q", err)
+			}
+		})
+	}
+
+	if _, err := routes.Load(strings.NewReader(""), "json"); !errors.Is(err, routes.ErrUnknownFormat) {
+		t.Errorf("want ErrUnknownFormat; got %v", err)
+	}
+}
+
+func TestLoadAggregatesErrors(t *testing.T) {
+	for format, table := range map[string]string{
+		"yaml": "routes:\n  - pattern: https://example.com/(\n  - pattern: https://example.com/\n  - name: book\n    pattern: https://example.com/books/:id(\n",
+		"toml": "[[routes]]\npattern = \"https://example.com/(\"\n\n[[routes]]\npattern = \"https://example.com/\"\n\n[[routes]]\nname = \"book\"\npattern = \"https://example.com/books/:id(\"\n",
+	} {
+		t.Run(format, func(t *testing.T) {
+			_, err := routes.Load(strings.NewReader(table), format)
+
+			var routeErrors routes.Errors
+			if !errors.As(err, &routeErrors) || len(routeErrors) != 2 {
+				t.Fatalf("want 2 route errors; got %v", err)
+			}
+
+			first, second := routeErrors[0], routeErrors[1]
+			if first.Index != 0 || second.Index != 2 || second.Name != "book" {
+				t.Errorf("unexpected errors %q", err)
+			}
+
+			wantLines := map[string][2]int{"yaml": {2, 5}, "toml": {2, 9}}[format]
+			if first.Line != wantLines[0] || second.Line != wantLines[1] {
+				t.Errorf("want lines %v; got %d and %d", wantLines, first.Line, second.Line)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInternal is returned by SafeNew and SafeExec when the package panics,
+// which is a bug that should be reported.
+//
+// Patterns and URLs are untrusted input for many applications: all the
+// indexing of the parser, tokenizer and matchers is bounds-checked against
+// them, and the package is randomly tested for panics. SafeNew and SafeExec
+// are a last line of defense for callers which cannot afford a crash.
+var ErrInternal = errors.New("internal error")
+
+// SafeNew is like New, but returns an error wrapping ErrInternal instead of
+// panicking.
+func SafeNew(input, baseURL string, options *Options) (u *URLPattern, err error) {
+	defer recoverInternal(&err)
+
+	return New(input, baseURL, options)
+}
+
+// SafeExec is like Exec, but returns an error wrapping ErrInternal instead of
+// panicking. The result is nil if input doesn't match.
+func (u *URLPattern) SafeExec(input, baseURL string) (result *URLPatternResult, err error) {
+	defer recoverInternal(&err)
+
+	return u.Exec(input, baseURL), nil
+}
+
+// recoverInternal converts a panic into an error wrapping ErrInternal stored
+// in err. It must be deferred.
+func recoverInternal(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	if e, ok := r.(error); ok {
+		*err = fmt.Errorf("%w: %w", ErrInternal, e)
+
+		return
+	}
+
+	*err = fmt.Errorf("%w: %v", ErrInternal, r)
+}
@@ -0,0 +1,85 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestSafeNew(t *testing.T) {
+	// These patterns used to panic.
+	for _, pattern := range []string{
+		"https://example.com/([).\\://:id@0",
+		":id@'self'.*./..*",
+	} {
+		if _, err := urlpattern.SafeNew(pattern, "https://example.com", nil); errors.Is(err, urlpattern.ErrInternal) {
+			t.Errorf("%q: unexpected internal error %v", pattern, err)
+		}
+	}
+
+	pattern, err := urlpattern.SafeNew("/books/:id", "https://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := pattern.SafeExec("https://example.com/books/1", "")
+	if err != nil || r == nil || r.Pathname.Groups["id"] != "1" {
+		t.Errorf("unexpected result %v, %v", r, err)
+	}
+
+	r, err = pattern.SafeExec("https://example.com/authors/1", "")
+	if err != nil || r != nil {
+		t.Errorf("unexpected result %v, %v", r, err)
+	}
+
+	// A nil pattern is a programming error, reported as an internal error.
+	var nilPattern *urlpattern.URLPattern
+	if _, err := nilPattern.SafeExec("https://example.com/", ""); !errors.Is(err, urlpattern.ErrInternal) {
+		t.Errorf("want ErrInternal; got %v", err)
+	}
+}
+
+// TestRandomPatternsDontPanic compiles and matches random combinations of
+// the special characters of the syntax.
+func TestRandomPatternsDontPanic(t *testing.T) {
+	alphabet := []string{
+		"a", ":", "/", "?", "#", "*", "+", "(", ")", "{", "}", "\\", ".", "..", "[", "]", "@",
+		"https", "://", "é", "\xff", "<int>", ":id", "0", "8080", "%", "%2F", "=", "&", "|",
+	}
+	opts := [][]urlpattern.Option{
+		{urlpattern.WithBaseURL("https://example.com")},
+		{urlpattern.WithBaseURL("https://example.com"), urlpattern.WithConstraints(), urlpattern.WithWildcardNames("rest"), urlpattern.WithMaxRepetitions(5), urlpattern.WithPortRanges(), urlpattern.WithIgnoreCase()},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for range 5000 {
+		var b strings.Builder
+		for range r.Intn(16) {
+			b.WriteString(alphabet[r.Intn(len(alphabet))])
+		}
+		s := b.String()
+
+		for _, opts := range opts {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Errorf("%q: panic: %v", s, r)
+					}
+				}()
+
+				pattern, err := urlpattern.Compile(s, opts...)
+				if err != nil {
+					return
+				}
+
+				pattern.Exec("https://example.com/"+s, "")
+				pattern.Exec(s, "https://example.com/")
+				pattern.Simplify()
+				urlpattern.Lint(s, opts...)
+			}()
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package urlpattern
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// SearchParamsPattern matches the parameters of a query string (the search
+// component of a URL) independently of their order, with a pattern per
+// parameter:
+//
+//	p, err := urlpattern.NewSearchParamsPattern(map[string]string{"page": `:n(\d+)`, "sort": "(asc|desc)"})
+//
+// Values are matched after being decoded (see url.ParseQuery) with the pattern
+// syntax: alternatives must be written in a regexp group. Only the first
+// value of a parameter is matched, and missing parameters are matched as the
+// empty string, so optional parameters can be declared with the "?" modifier
+// (e.g. ":n(\d+)?"). Unlisted parameters are ignored, as are the parameters
+// which can't be decoded, such as "x=%zz" or "x=a;b": they are missing.
+type SearchParamsPattern struct {
+	params []searchParamPattern
+}
+
+type searchParamPattern struct {
+	key       string
+	component *component
+}
+
+// NewSearchParamsPattern compiles a pattern for each value of params, keyed by
+// the name of the parameter. WithIgnoreCase is the only supported option.
+func NewSearchParamsPattern(params map[string]string, opts ...Option) (*SearchParamsPattern, error) {
+	c := newConfig(opts)
+	if c.err != nil {
+		return nil, typeError(c.err)
+	}
+
+	p := &SearchParamsPattern{params: make([]searchParamPattern, 0, len(params))}
+	for key, value := range params {
+		component, err := compileComponent("search", value, func(s string) (string, error) { return s, nil }, options{ignoreCase: c.ignoreCase})
+		if err != nil {
+			return nil, typeError(fmt.Errorf("search parameter %q: %w", key, err))
+		}
+
+		p.params = append(p.params, searchParamPattern{key, component})
+	}
+
+	slices.SortFunc(p.params, func(a, b searchParamPattern) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	return p, nil
+}
+
+// Exec matches search, a query string without the leading "?", and returns
+// the result of each parameter keyed by its name, or nil if a parameter
+// doesn't match.
+func (p *SearchParamsPattern) Exec(search string) map[string]URLPatternComponentResult {
+	// ParseQuery returns the parameters it could decode even on error: an
+	// invalid unrelated parameter mustn't prevent matching.
+	values, _ := url.ParseQuery(search)
+
+	results := make(map[string]URLPatternComponentResult, len(p.params))
+	for _, param := range p.params {
+		var value string
+		if v := values[param.key]; len(v) > 0 {
+			value = v[0]
+		}
+
+		execResult := param.component.exec(value)
+		if execResult == nil {
+			return nil
+		}
+
+		results[param.key] = createComponentMatchResult(*param.component, value, execResult)
+	}
+
+	return results
+}
+
+// Test reports whether search matches the pattern, see Exec.
+func (p *SearchParamsPattern) Test(search string) bool {
+	return p.Exec(search) != nil
+}
+
+// WithSearchParams requires the parameters of the search component of the
+// matched URLs to also match p. The results of the parameters are stored in
+// URLPatternResult.SearchParams.
+func WithSearchParams(p *SearchParamsPattern) Option {
+	return func(c *config) {
+		c.searchParams = p
+	}
+}
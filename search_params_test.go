@@ -0,0 +1,54 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestSearchParamsPattern(t *testing.T) {
+	params, err := urlpattern.NewSearchParamsPattern(map[string]string{"page": `:n(\d+)?`, "sort": "(asc|desc)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for search, want := range map[string]bool{
+		"sort=asc&page=2":       true,
+		"utm_source=x&sort=asc": true,
+		"page=2":                false,
+		"sort=up&page=2":        false,
+		"sort=asc&page=two":     false,
+	} {
+		if got := params.Test(search); got != want {
+			t.Errorf("%s: want %t; got %t", search, want, got)
+		}
+	}
+
+	pattern, err := urlpattern.Compile("https://example.com/books", urlpattern.WithSearchParams(params))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := pattern.Exec("https://example.com/books?sort=desc&page=3", "")
+	if r == nil {
+		t.Fatal("want match")
+	}
+	if r.SearchParams["page"].Groups["n"] != "3" || r.SearchParams["sort"].Input != "desc" {
+		t.Errorf("unexpected search params %#v", r.SearchParams)
+	}
+
+	if pattern.Test("https://example.com/books?sort=up", "") {
+		t.Error("want no match")
+	}
+
+	// Invalid unrelated parameters are ignored.
+	for _, input := range []string{"https://example.com/books?page=2&x=a;b&sort=asc", "https://example.com/books?page=2&x=%zz&sort=asc"} {
+		if r := pattern.Exec(input, ""); r == nil || r.SearchParams["page"].Groups["n"] != "2" {
+			t.Errorf("%s: want match", input)
+		}
+	}
+
+	if _, err := urlpattern.NewSearchParamsPattern(map[string]string{"page": ":n(\\d+"}); err == nil {
+		t.Error("want error")
+	}
+}
@@ -0,0 +1,281 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrInvalidServeMuxPattern is returned by ParseServeMuxPattern for
+	// invalid http.ServeMux patterns.
+	ErrInvalidServeMuxPattern = errors.New("invalid ServeMux pattern")
+	// ErrNotServeMuxPattern is returned by MethodPattern.ServeMuxPattern when
+	// the pattern cannot be expressed as an http.ServeMux pattern.
+	ErrNotServeMuxPattern = errors.New("pattern cannot be expressed as a ServeMux pattern")
+)
+
+// ParseServeMuxPattern converts a pattern of http.ServeMux, such as
+// "GET example.com/items/{id}/{rest...}", to a MethodPattern, so that the same
+// route declarations can be shared between the standard library and tools
+// based on URLPattern.
+//
+// Wildcards are converted to named groups ("{id}" to ":id", "{rest...}" to
+// ":rest(.*)"), a trailing slash to a "*" wildcard, and "{$}" to the end of
+// the pathname. The protocol, search and hash match anything, as well as the
+// hostname and the port if the pattern has no host.
+func ParseServeMuxPattern(pattern string, opts ...Option) (*MethodPattern, error) {
+	var methods []string
+	rest := pattern
+	if i := strings.IndexAny(rest, " \t"); i != -1 {
+		method := rest[:i]
+		if method == "" || strings.ContainsFunc(method, func(r rune) bool { return !isTokenChar(r) }) {
+			return nil, fmt.Errorf("%w: bad method %q", ErrInvalidServeMuxPattern, method)
+		}
+
+		methods = []string{method}
+		rest = strings.TrimLeft(rest[i+1:], " \t")
+	}
+
+	i := strings.IndexByte(rest, '/')
+	if i == -1 {
+		return nil, fmt.Errorf("%w: host/path missing /", ErrInvalidServeMuxPattern)
+	}
+
+	init := &URLPatternInit{}
+	if host := rest[:i]; host != "" {
+		hostname, port, err := net.SplitHostPort(host)
+		if err != nil {
+			hostname, port = host, ""
+		}
+
+		hostname = EscapePatternString(hostname)
+		init.Hostname = &hostname
+		init.Port = &port
+	}
+
+	pathname, err := serveMuxPathname(rest[i:])
+	if err != nil {
+		return nil, err
+	}
+	init.Pathname = &pathname
+
+	u, err := Compile(init, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMethodPattern(u, methods...), nil
+}
+
+// serveMuxPathname converts the path of a ServeMux pattern to a pathname
+// pattern.
+func serveMuxPathname(path string) (string, error) {
+	var b strings.Builder
+
+	segments := strings.Split(path[1:], "/")
+	for i, seg := range segments {
+		last := i == len(segments)-1
+
+		switch {
+		case seg == "" && last:
+			// A trailing slash matches every path below.
+			b.WriteString("/*")
+
+		case seg == "{$}":
+			if !last {
+				return "", fmt.Errorf("%w: {$} not at end", ErrInvalidServeMuxPattern)
+			}
+			b.WriteByte('/')
+
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+			name, multi := strings.CutSuffix(seg[1:len(seg)-1], "...")
+			if multi && !last {
+				return "", fmt.Errorf("%w: {...} wildcard not at end", ErrInvalidServeMuxPattern)
+			}
+			if !isServeMuxWildcardName(name) {
+				return "", fmt.Errorf("%w: bad wildcard name %q", ErrInvalidServeMuxPattern, name)
+			}
+
+			b.WriteString("/:")
+			b.WriteString(name)
+			if multi {
+				b.WriteString("(.*)")
+			}
+
+		case strings.ContainsAny(seg, "{}"):
+			return "", fmt.Errorf("%w: bad wildcard segment %q", ErrInvalidServeMuxPattern, seg)
+
+		default:
+			b.WriteByte('/')
+			b.WriteString(EscapePatternString(seg))
+		}
+	}
+
+	return b.String(), nil
+}
+
+// ServeMuxPattern converts m to an http.ServeMux pattern, the reverse of
+// ParseServeMuxPattern. ErrNotServeMuxPattern is returned if m cannot be
+// expressed as such: it must have at most one method (GET implying HEAD), its
+// protocol, username, password, search and hash must be "*", its hostname and
+// port fixed text or "*", and its pathname must only contain fixed text,
+// named groups spanning whole segments, and a final "*" wildcard or ":name(.*)"
+// group. Patterns ignoring case cannot be converted either.
+func (m *MethodPattern) ServeMuxPattern() (string, error) {
+	u := m.pattern
+
+	var b strings.Builder
+
+	methods := m.methods
+	if len(methods) == 2 && slices.Contains(methods, http.MethodGet) && slices.Contains(methods, http.MethodHead) {
+		methods = []string{http.MethodGet}
+	}
+	switch len(methods) {
+	case 0:
+	case 1:
+		b.WriteString(methods[0])
+		b.WriteByte(' ')
+	default:
+		return "", fmt.Errorf("%w: several methods", ErrNotServeMuxPattern)
+	}
+
+	if u.config.ignoreCase {
+		return "", fmt.Errorf("%w: case-insensitive pattern", ErrNotServeMuxPattern)
+	}
+
+	for _, c := range []*component{u.protocol, u.username, u.password, u.search, u.hash} {
+		if c.patternString != "*" {
+			return "", fmt.Errorf("%w: %q must be \"*\"", ErrNotServeMuxPattern, c.patternString)
+		}
+	}
+
+	hostname, ok := fixedText(u.hostname)
+	if !ok {
+		return "", fmt.Errorf("%w: hostname %q", ErrNotServeMuxPattern, u.hostname.patternString)
+	}
+	port, ok := fixedText(u.port)
+	if !ok || (port != "" && hostname == "") {
+		return "", fmt.Errorf("%w: port %q", ErrNotServeMuxPattern, u.port.patternString)
+	}
+	if port == "" {
+		b.WriteString(hostname)
+	} else {
+		b.WriteString(net.JoinHostPort(hostname, port))
+	}
+
+	if u.pathname.patternString == "*" {
+		b.WriteByte('/')
+
+		return b.String(), nil
+	}
+
+	path, err := serveMuxPath(u.pathname.partList)
+	if err != nil {
+		return "", err
+	}
+	b.WriteString(path)
+
+	return b.String(), nil
+}
+
+// fixedText returns the text matched by c, the empty string if c matches
+// anything, and false if c contains groups.
+func fixedText(c *component) (string, bool) {
+	if c.patternString == "*" {
+		return "", true
+	}
+
+	var b strings.Builder
+	for _, p := range c.partList {
+		if p.pType != partFixedText || p.modifier != partModifierNone {
+			return "", false
+		}
+		b.WriteString(p.value)
+	}
+
+	return b.String(), true
+}
+
+// serveMuxPath converts a pathname part list to the path of a ServeMux
+// pattern.
+func serveMuxPath(pl partList) (string, error) {
+	var b strings.Builder
+
+	for i, p := range pl {
+		last := i == len(pl)-1
+
+		if p.pType == partFixedText {
+			if p.modifier != partModifierNone || strings.ContainsAny(p.value, "{}") {
+				return "", fmt.Errorf("%w: fixed text %q", ErrNotServeMuxPattern, p.value)
+			}
+			b.WriteString(p.value)
+
+			continue
+		}
+
+		// Groups must span whole segments.
+		if p.modifier != partModifierNone || p.suffix != "" ||
+			(p.prefix != "/" && (p.prefix != "" || !strings.HasSuffix(b.String(), "/"))) ||
+			(!last && !startsSegment(pl[i+1])) {
+			return "", fmt.Errorf("%w: group %q not spanning a segment", ErrNotServeMuxPattern, p.name)
+		}
+		b.WriteString(p.prefix)
+
+		switch {
+		case p.pType == partFullWildcard && last && !isServeMuxWildcardName(p.name):
+			// A trailing slash matches every path below.
+		case p.pType == partFullWildcard && last:
+			b.WriteString("{" + p.name + "...}")
+		case p.pType == partSegmentWildcard && isServeMuxWildcardName(p.name):
+			b.WriteString("{" + p.name + "}")
+		default:
+			return "", fmt.Errorf("%w: group %q", ErrNotServeMuxPattern, p.name)
+		}
+	}
+
+	path := b.String()
+	switch {
+	case !strings.HasPrefix(path, "/"):
+		return "", fmt.Errorf("%w: relative pathname", ErrNotServeMuxPattern)
+	case strings.HasSuffix(path, "/") && (len(pl) == 0 || pl[len(pl)-1].pType == partFixedText):
+		// Paths with a trailing slash are prefixes in ServeMux patterns.
+		return path + "{$}", nil
+	}
+
+	return path, nil
+}
+
+// startsSegment reports whether p starts with a slash.
+func startsSegment(p part) bool {
+	if p.pType == partFixedText {
+		return strings.HasPrefix(p.value, "/")
+	}
+
+	return p.prefix == "/"
+}
+
+// isServeMuxWildcardName reports whether name is a valid wildcard name in
+// ServeMux patterns: a Go identifier.
+func isServeMuxWildcardName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isTokenChar reports whether r may appear in an HTTP token, such as a method.
+func isTokenChar(r rune) bool {
+	return r < unicode.MaxASCII && (isASCIIAlpha(byte(r)) || (r >= '0' && r <= '9') || strings.ContainsRune("!#$%&'*+-.^_`|~", r))
+}
@@ -0,0 +1,70 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestServeMuxPattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{"GET /items/{id}/{rest...}", []string{"https://example.com/items/1/", "https://example.com/items/1/a/b"}, []string{"https://example.com/items/1"}},
+		{"example.com/static/", []string{"https://example.com/static/", "http://example.com/static/css/app.css"}, []string{"https://example.org/static/", "https://example.com/static"}},
+		{"/static/{$}", []string{"https://example.com/static/"}, []string{"https://example.com/static/app.css"}},
+		{"/", []string{"https://example.com/", "https://example.com/a/b"}, nil},
+		{"POST example.com:8080/users/{id}", []string{"https://example.com:8080/users/42"}, []string{"https://example.com/users/42", "https://example.com:8080/users/"}},
+	} {
+		t.Run(tc.pattern, func(t *testing.T) {
+			m, err := urlpattern.ParseServeMuxPattern(tc.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, input := range tc.match {
+				if !m.Pattern().Test(input, "") {
+					t.Errorf("want %q to match", input)
+				}
+			}
+			for _, input := range tc.noMatch {
+				if m.Pattern().Test(input, "") {
+					t.Errorf("want %q not to match", input)
+				}
+			}
+
+			got, err := m.ServeMuxPattern()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.pattern {
+				t.Errorf("want %q; got %q", tc.pattern, got)
+			}
+		})
+	}
+
+	m, _ := urlpattern.ParseServeMuxPattern("GET /items/{id}")
+	if r := m.Match("HEAD", "https://example.com/items/42"); r == nil || r.Pathname.Groups["id"] != "42" {
+		t.Errorf("unexpected result %#v", r)
+	}
+
+	for _, p := range []string{"items", "/{rest...}/a", "/{$}/a", "/a{b}", "/{1a}", "GE(T /"} {
+		if _, err := urlpattern.ParseServeMuxPattern(p); !errors.Is(err, urlpattern.ErrInvalidServeMuxPattern) {
+			t.Errorf("%s: want ErrInvalidServeMuxPattern; got %v", p, err)
+		}
+	}
+
+	for _, p := range []string{"https://example.com/*", "/items/:id?", "/items/(\\d+)", "/items/:id.json"} {
+		u, err := urlpattern.Compile(p, urlpattern.WithBaseURL("https://example.com"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := urlpattern.NewMethodPattern(u).ServeMuxPattern(); !errors.Is(err, urlpattern.ErrNotServeMuxPattern) {
+			t.Errorf("%s: want ErrNotServeMuxPattern; got %v", p, err)
+		}
+	}
+}
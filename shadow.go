@@ -0,0 +1,86 @@
+package urlpattern
+
+import "maps"
+
+// ShadowMatcher matches URLs against a primary pattern, and against a shadow
+// pattern compiled from the same pattern with another configuration (e.g.
+// WithStdlibURL, WithIDNAMode or a new set of options), reporting the URLs
+// for which they disagree. This allows rolling out routing changes safely:
+// the shadow configuration is exercised with production traffic while the
+// results of the primary one are still used.
+//
+//	shadow := urlpattern.NewShadowMatcher(primary, candidate, func(d urlpattern.ShadowDivergence) {
+//		slog.Warn("urlpattern divergence", "input", d.Input, "components", d.Components)
+//	})
+//	result := shadow.Exec(r.URL.String(), "")
+//
+// The shadow pattern is matched synchronously after the primary one, which
+// doubles the cost of matching.
+type ShadowMatcher struct {
+	primary      *URLPattern
+	shadow       *URLPattern
+	onDivergence func(ShadowDivergence)
+}
+
+// ShadowDivergence describes a URL matched differently by the primary and the
+// shadow patterns of a ShadowMatcher.
+type ShadowDivergence struct {
+	Input   string
+	BaseURL string
+	// Primary and Shadow are the results of the patterns, nil if they don't
+	// match.
+	Primary *URLPatternResult
+	Shadow  *URLPatternResult
+	// Components holds the names of the components whose input or groups
+	// differ, in the order of the specification. It is empty if only one of
+	// the patterns matches.
+	Components []string
+}
+
+// NewShadowMatcher returns a ShadowMatcher using the results of primary, and
+// calling onDivergence when the results of shadow differ. onDivergence may be
+// called from several goroutines at once.
+func NewShadowMatcher(primary, shadow *URLPattern, onDivergence func(ShadowDivergence)) *ShadowMatcher {
+	return &ShadowMatcher{primary: primary, shadow: shadow, onDivergence: onDivergence}
+}
+
+// Exec matches input, relative to baseURL if not empty, against both
+// patterns, and returns the result of the primary one.
+func (m *ShadowMatcher) Exec(input, baseURL string) *URLPatternResult {
+	primary := m.primary.Exec(input, baseURL)
+	shadow := m.shadow.Exec(input, baseURL)
+
+	if primary == nil && shadow == nil {
+		return nil
+	}
+
+	var components []string
+	if primary != nil && shadow != nil {
+		for _, name := range componentNames {
+			p, s := primary.component(name), shadow.component(name)
+			if p.Input != s.Input || !maps.Equal(p.Groups, s.Groups) {
+				components = append(components, name)
+			}
+		}
+
+		if components == nil {
+			return primary
+		}
+	}
+
+	m.onDivergence(ShadowDivergence{
+		Input:      input,
+		BaseURL:    baseURL,
+		Primary:    primary,
+		Shadow:     shadow,
+		Components: components,
+	})
+
+	return primary
+}
+
+// Test reports whether input, relative to baseURL if not empty, matches the
+// primary pattern, see Exec.
+func (m *ShadowMatcher) Test(input, baseURL string) bool {
+	return m.Exec(input, baseURL) != nil
+}
@@ -0,0 +1,45 @@
+package urlpattern_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestShadowMatcher(t *testing.T) {
+	primary, err := urlpattern.Compile("https://example.com/books/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	shadow, err := urlpattern.Compile("https://example.com/books/*", urlpattern.WithIgnoreCase(), urlpattern.WithWildcardNames("rest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var divergences []urlpattern.ShadowDivergence
+	m := urlpattern.NewShadowMatcher(primary, shadow, func(d urlpattern.ShadowDivergence) {
+		divergences = append(divergences, d)
+	})
+
+	if r := m.Exec("https://example.com/books/1", ""); r == nil || r.Pathname.Groups["0"] != "1" {
+		t.Errorf("want the result of the primary pattern; got %v", r)
+	}
+	if m.Test("https://example.com/BOOKS/1", "") {
+		t.Error("want no match")
+	}
+	if m.Test("https://example.com/authors/1", "") {
+		t.Error("want no match")
+	}
+
+	if len(divergences) != 2 {
+		t.Fatalf("want 2 divergences; got %d", len(divergences))
+	}
+
+	if d := divergences[0]; d.Input != "https://example.com/books/1" || d.Shadow.Pathname.Groups["rest"] != "1" || !slices.Equal(d.Components, []string{"pathname"}) {
+		t.Errorf("unexpected divergence %#v", d)
+	}
+	if d := divergences[1]; d.Input != "https://example.com/BOOKS/1" || d.Primary != nil || d.Shadow == nil || d.Components != nil {
+		t.Errorf("unexpected divergence %#v", d)
+	}
+}
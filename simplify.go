@@ -0,0 +1,128 @@
+package urlpattern
+
+import "slices"
+
+// Simplify returns a pattern equivalent to u whose components are rewritten
+// to a minimal form: adjacent fixed text is merged, the prefixes and suffixes
+// of groups without modifier are moved to the surrounding fixed text, except
+// the prefix code point (e.g. "/{:id}" becomes "/:id"), and regexp groups
+// equivalent to wildcards become wildcards (e.g. "/:id([^/]+?)" becomes
+// "/:id"). The pattern strings are cleaner, and the regular expressions
+// slightly smaller.
+//
+// Components are recompiled only if their part list changes: u is returned
+// if no component can be simplified.
+func (u *URLPattern) Simplify() *URLPattern {
+	s := *u
+
+	var changed bool
+	for i, c := range []**component{&s.protocol, &s.username, &s.password, &s.hostname, &s.port, &s.pathname, &s.search, &s.hash} {
+		if (*c).portRanges != nil || (*c).markInput != nil {
+			continue
+		}
+
+		pl := (*c).partList.simplify((*c).options)
+		if slices.Equal(pl, (*c).partList) {
+			continue
+		}
+
+		simplified, err := compilePartList(componentNames[i], pl, (*c).options)
+		if err != nil {
+			// Simplified part lists are valid if the original ones are.
+			continue
+		}
+
+		*c = simplified
+		changed = true
+	}
+
+	if !changed {
+		return u
+	}
+
+	return &s
+}
+
+// SimplifyParts rewrites parts to a minimal equivalent form, see
+// URLPattern.Simplify.
+func SimplifyParts(parts []Part, opts CompileOptions) []Part {
+	pl := make(partList, len(parts))
+	for i, p := range parts {
+		pl[i] = part{pType: partType(p.Type), value: p.Value, modifier: partModifier(p.Modifier), name: p.Name, prefix: p.Prefix, suffix: p.Suffix}
+	}
+
+	c := component{partList: pl.simplify(options{delimiterCodePoint: opts.DelimiterCodePoint, prefixCodePoint: opts.PrefixCodePoint, ignoreCase: opts.IgnoreCase})}
+
+	return c.parts()
+}
+
+// simplify returns a minimal part list equivalent to pl.
+func (pl partList) simplify(options options) partList {
+	segmentWildcardRegexps := []string{generateSegmentWildcardRegexp(options), "[^" + string(options.delimiterCodePoint) + "]+?"}
+
+	result := make(partList, 0, len(pl))
+	appendFixedText := func(value string) {
+		if value == "" {
+			return
+		}
+
+		if last := len(result) - 1; last >= 0 && result[last].pType == partFixedText && result[last].modifier == partModifierNone {
+			result[last].value += value
+
+			return
+		}
+
+		result = append(result, part{pType: partFixedText, value: value})
+	}
+
+	for _, p := range pl {
+		switch {
+		case p.pType == partFixedText && p.modifier == partModifierNone:
+			appendFixedText(p.value)
+
+			continue
+		case p.pType == partRegexp && slices.Contains(segmentWildcardRegexps, p.value) && isValidName(p.name):
+			// Unnamed segment wildcards are written as regexp groups in
+			// pattern strings anyway.
+			p.pType, p.value = partSegmentWildcard, ""
+		case p.pType == partRegexp && p.value == fullWildcardRegexpValue:
+			p.pType, p.value = partFullWildcard, ""
+		}
+
+		if p.pType == partFixedText || p.modifier != partModifierNone {
+			result = append(result, p)
+
+			continue
+		}
+
+		appendFixedText(p.prefix)
+		suffix := p.suffix
+		p.prefix, p.suffix = "", ""
+		result = append(result, p)
+		appendFixedText(suffix)
+	}
+
+	if options.prefixCodePoint == 0 {
+		return result
+	}
+
+	// Move the prefix code point ending fixed text to the following group, as
+	// when parsing "/:id".
+	prefix := string(options.prefixCodePoint)
+	for i := 1; i < len(result); i++ {
+		prev, p := &result[i-1], &result[i]
+		if p.pType == partFixedText || p.modifier != partModifierNone || p.prefix != "" ||
+			prev.pType != partFixedText || prev.modifier != partModifierNone || prev.value[len(prev.value)-1] != options.prefixCodePoint {
+			continue
+		}
+
+		p.prefix = prefix
+		prev.value = prev.value[:len(prev.value)-1]
+		if prev.value == "" {
+			result = slices.Delete(result, i-1, i)
+			i--
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,73 @@
+package urlpattern_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func ExampleURLPattern_Simplify() {
+	pattern, err := urlpattern.Compile("https://example.com/{:id}{/a:name}/:file([^/]+?)")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(pattern.Pathname())
+	fmt.Println(pattern.Simplify().Pathname())
+	// Output:
+	// /{:id}{/a:name}/:file([^/]+?)
+	// /:id/a:name/:file
+}
+
+func TestSimplify(t *testing.T) {
+	for pathname, want := range map[string]string{
+		"/books/:id":     "/books/:id",
+		"/{:id}":         "/:id",
+		"{/:id}?":        "/:id?",
+		"/{a:id.json}":   "/a:id.json",
+		"/(.*)":          "/*",
+		"/a{/:b}?{.:c}+": "/a/:b?{.:c}+",
+	} {
+		pattern, err := urlpattern.Compile(&urlpattern.URLPatternInit{Pathname: &pathname})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		simplified := pattern.Simplify()
+		if got := simplified.Pathname(); got != want {
+			t.Errorf("%s: want %q; got %q", pathname, want, got)
+		}
+
+		for _, input := range []string{"https://example.com/books/42", "https://example.com/42", "https://example.com/a42.json", "https://example.com/a/b.c.d", "https://example.com/a"} {
+			r1, r2 := pattern.Exec(input, ""), simplified.Exec(input, "")
+			if (r1 == nil) != (r2 == nil) || (r1 != nil && fmt.Sprint(r1.Pathname.Groups) != fmt.Sprint(r2.Pathname.Groups)) {
+				t.Errorf("%s: results differ for %q: %v, %v", pathname, input, r1, r2)
+			}
+		}
+	}
+
+	pattern, err := urlpattern.Compile("https://example.com/books/:id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pattern.Simplify() != pattern {
+		t.Error("want the same pattern when nothing can be simplified")
+	}
+}
+
+func TestSimplifyParts(t *testing.T) {
+	parts := urlpattern.SimplifyParts([]urlpattern.Part{
+		{Type: urlpattern.PartFixedText, Value: "/books"},
+		{Type: urlpattern.PartFixedText, Value: "/"},
+		{Type: urlpattern.PartRegexp, Value: `[^/]+?`, Name: "id", Suffix: ".json"},
+	}, urlpattern.CompileOptions{DelimiterCodePoint: '/', PrefixCodePoint: '/'})
+
+	c, err := urlpattern.CompileParts(parts, urlpattern.CompileOptions{DelimiterCodePoint: '/', PrefixCodePoint: '/'})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/books/:id.json"; c.String() != want {
+		t.Errorf("want %q; got %q", want, c.String())
+	}
+}
@@ -0,0 +1,127 @@
+package urlpattern
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// LogValue implements slog.LogValuer: the pattern is logged as a group of
+// the pattern strings of its components, without the components matching
+// anything ("*").
+//
+//	slog.Info("route added", "pattern", pattern)
+//	// INFO route added pattern.protocol=https pattern.hostname=example.com pattern.port="" pattern.pathname=/books/:id
+func (u *URLPattern) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(componentNames)+1)
+	for _, name := range componentNames {
+		if p := u.component(name).patternString; p != "*" {
+			attrs = append(attrs, slog.String(name, p))
+		}
+	}
+
+	if u.config.ignoreCase {
+		attrs = append(attrs, slog.Bool("ignoreCase", true))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer: the route is logged as a group of its
+// name, priority and pattern, see URLPattern.LogValue. The metadata aren't
+// logged.
+func (r Route) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 3)
+	if r.Name != "" {
+		attrs = append(attrs, slog.String("name", r.Name))
+	}
+	if r.Priority != 0 {
+		attrs = append(attrs, slog.Int("priority", r.Priority))
+	}
+	if r.Pattern != nil {
+		attrs = append(attrs, slog.Any("pattern", r.Pattern))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer: the result is logged as a group of its
+// inputs and of the components with a non-empty input or groups, see
+// URLPatternComponentResult.LogValue. A nil result, when nothing matched, is
+// logged as nil.
+//
+//	slog.Info("match", "result", pattern.Exec("https://example.com/books/42", ""))
+//	// INFO match result.inputs=[https://example.com/books/42] result.protocol.input=https ... result.pathname.groups.id=42
+//
+// The password component is never logged, and the passwords of the inputs are
+// replaced by "xxxxx", as by url.URL.Redacted.
+func (r *URLPatternResult) LogValue() slog.Value {
+	if r == nil {
+		return slog.AnyValue(nil)
+	}
+
+	attrs := make([]slog.Attr, 0, len(componentNames))
+	if len(r.Inputs) > 0 {
+		inputs := make([]string, len(r.Inputs))
+		for i, input := range r.Inputs {
+			inputs[i] = redactPassword(input)
+		}
+
+		attrs = append(attrs, slog.Any("inputs", inputs))
+	}
+
+	for _, name := range componentNames {
+		c := r.component(name)
+		if name == "password" || (c.Input == "" && len(c.Groups) == 0) {
+			continue
+		}
+
+		attrs = append(attrs, slog.Any(name, c))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// redactPassword returns input with the password of its userinfo, if any,
+// replaced by "xxxxx".
+func redactPassword(input string) string {
+	_, rest, ok := strings.Cut(input, "//")
+	if !ok {
+		return input
+	}
+
+	authority := rest
+	if i := strings.IndexAny(rest, `/\?#`); i != -1 {
+		authority = rest[:i]
+	}
+
+	at := strings.LastIndexByte(authority, '@')
+	if at == -1 {
+		return input
+	}
+
+	username, _, ok := strings.Cut(authority[:at], ":")
+	if !ok {
+		return input
+	}
+
+	start := len(input) - len(rest)
+
+	return input[:start] + username + ":xxxxx" + input[start+at:]
+}
+
+// LogValue implements slog.LogValuer: the component result is logged as a
+// group of its input and of its groups, in pattern order.
+func (r *URLPatternComponentResult) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("input", r.Input)}
+
+	if groups := r.OrderedGroups(); len(groups) > 0 {
+		groupAttrs := make([]slog.Attr, len(groups))
+		for i, g := range groups {
+			groupAttrs[i] = slog.String(g.Name, g.Value)
+		}
+
+		attrs = append(attrs, slog.Attr{Key: "groups", Value: slog.GroupValue(groupAttrs...)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,76 @@
+package urlpattern
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// WithStdlibURL parses the URLs matched by Exec and Test with net/url and
+// golang.org/x/net/idna instead of the WHATWG URL parser, see WithURLParser.
+//
+// The canonicalization is best-effort: the scheme and the hostname are
+// lowercased, the hostname is converted to punycode, default ports are
+// removed, dot segments are resolved and empty paths of special schemes
+// become "/", but the percent-encoding of net/url is kept, and URLs rejected
+// or accepted only by the WHATWG parser behave differently. This is faster
+// and enough for many server-side uses, which don't need browser-exact
+// behavior.
+func WithStdlibURL() Option {
+	return WithURLParser(stdlibURLParser{IDNAPunycode.parser()})
+}
+
+// stdlibURLParser parses URLs with net/url. Patterns are still canonicalized
+// with the basic URL parser and the percent-encoding of the embedded WHATWG
+// parser.
+type stdlibURLParser struct {
+	whatwgURLParser
+}
+
+func (stdlibURLParser) Parse(input, baseURL string) (URLComponents, error) {
+	u, err := url.Parse(input)
+	if err != nil {
+		return URLComponents{}, err
+	}
+
+	base := &url.URL{}
+	if baseURL != "" {
+		if base, err = url.Parse(baseURL); err != nil {
+			return URLComponents{}, err
+		}
+	}
+
+	// Resolving also removes the dot segments of absolute URLs.
+	u = base.ResolveReference(u)
+
+	hostname := u.Hostname()
+	if hostname != "" && !strings.HasPrefix(u.Host, "[") {
+		if hostname, err = idna.Lookup.ToASCII(hostname); err != nil {
+			return URLComponents{}, err
+		}
+	}
+
+	c := URLComponents{
+		Protocol: u.Scheme,
+		Username: u.User.Username(),
+		Hostname: strings.ToLower(hostname),
+		Port:     u.Port(),
+		Pathname: u.EscapedPath(),
+		Search:   u.RawQuery,
+		Hash:     u.EscapedFragment(),
+	}
+	c.Password, _ = u.User.Password()
+
+	if _, special := specialSchemeSet[c.Protocol]; special {
+		if port, _ := DefaultPort(c.Protocol); c.Port == port {
+			c.Port = ""
+		}
+
+		if c.Pathname == "" {
+			c.Pathname = "/"
+		}
+	}
+
+	return c, nil
+}
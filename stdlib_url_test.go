@@ -0,0 +1,43 @@
+package urlpattern_test
+
+import (
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestWithStdlibURL(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://xn--caf-dma.com/books/:id", urlpattern.WithStdlibURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for input, want := range map[string]bool{
+		"https://café.com/books/42":            true,
+		"HTTPS://CAFÉ.com:443/books/42":        true,
+		"https://xn--caf-dma.com/a/../books/4": true,
+		"https://café.com:8443/books/42":       false,
+		"https://café.com/authors/42":          false,
+		"https://café.com/books/42/":           false,
+		"http://café.com/books/42":             false,
+		"https://café.com/books/%zz":           false,
+	} {
+		if got := pattern.Test(input, ""); got != want {
+			t.Errorf("%s: want %v; got %v", input, want, got)
+		}
+	}
+
+	r := pattern.Exec("42?page=2", "https://café.com/books/")
+	if r == nil || r.Pathname.Groups["id"] != "42" || r.Search.Input != "page=2" {
+		t.Errorf("unexpected result %#v", r)
+	}
+
+	root, err := urlpattern.Compile("https://example.com/", urlpattern.WithStdlibURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !root.Test("https://example.com", "") {
+		t.Error("the empty path must be canonicalized to /")
+	}
+}
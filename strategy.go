@@ -0,0 +1,210 @@
+package urlpattern
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// MatchStrategy selects the pattern of a URLPatternList returned when several
+// patterns match a URL. Whatever the strategy, patterns with a higher
+// priority are preferred, and ties are broken by insertion order.
+type MatchStrategy uint8
+
+const (
+	// StrategyFirstMatch prefers the first pattern added to the list. It is
+	// the default.
+	StrategyFirstMatch MatchStrategy = iota
+	// StrategyMostSpecific prefers the pattern with the most fixed text in
+	// its components, then the one with the fewest "*" wildcards, then the
+	// one with the fewest groups, as API routers usually do.
+	StrategyMostSpecific
+	// StrategyLongestPrefix prefers the pattern with the longest fixed text
+	// at the start of its pathname, as CDN and proxy rules usually do.
+	StrategyLongestPrefix
+)
+
+// ListMatch is a pattern of a URLPatternList matching a URL.
+type ListMatch struct {
+	// Index is the index of the route in the list, in insertion order (see
+	// Routes).
+	Index int
+	// Route holds the pattern, its name and its metadata.
+	Route  Route
+	Result *URLPatternResult
+}
+
+// SetStrategy sets the strategy used by Match and MatchRoute when several
+// patterns match a URL, and the order of the matches returned by MatchAll and
+// Matches.
+func (l *URLPatternList) SetStrategy(s MatchStrategy) {
+	l.strategy = s
+}
+
+// MatchAll returns all the patterns matching input, ordered according to the
+// strategy of the list. It returns nil if input matches an exclusion (see
+// Exclude).
+func (l *URLPatternList) MatchAll(input string) []ListMatch {
+	sorted := l.sortedMatches(input)
+	if len(sorted) == 0 || l.excluded(input) {
+		return nil
+	}
+
+	matches := make([]ListMatch, 0, len(sorted))
+	for _, e := range sorted {
+		matches = append(matches, ListMatch{Index: e.index, Route: l.routes[e.index], Result: e.result})
+	}
+
+	return matches
+}
+
+// Matches returns an iterator over the patterns matching input and the results
+// of the matches, ordered according to the strategy of the list, so that
+// callers can stop at the first acceptable match:
+//
+//	for pattern, result := range list.Matches(input) {
+//		if authorized(pattern, result) {
+//			break
+//		}
+//	}
+//
+// With StrategyFirstMatch, the patterns are matched as the iteration goes, and
+// no slice of the matches is allocated; other strategies need all the matches
+// to order them. Nothing is yielded if input matches an exclusion (see
+// Exclude).
+func (l *URLPatternList) Matches(input string) iter.Seq2[*URLPattern, *URLPatternResult] {
+	return func(yield func(*URLPattern, *URLPatternResult) bool) {
+		if l.strategy != StrategyFirstMatch {
+			sorted := l.sortedMatches(input)
+			if len(sorted) == 0 || l.excluded(input) {
+				return
+			}
+
+			for _, e := range sorted {
+				if !yield(e.pattern, e.result) {
+					return
+				}
+			}
+
+			return
+		}
+
+		checked := false
+		for _, c := range l.candidates(input) {
+			result := c.pattern.Exec(input, "")
+			if result == nil {
+				continue
+			}
+
+			if !checked {
+				if l.excluded(input) {
+					return
+				}
+				checked = true
+			}
+
+			if !yield(c.pattern, result) {
+				return
+			}
+		}
+	}
+}
+
+// matchedEntry is a pattern matching a URL.
+type matchedEntry struct {
+	patternEntry
+	result *URLPatternResult
+}
+
+// sortedMatches returns all the patterns matching input, ordered according to
+// the strategy of the list.
+func (l *URLPatternList) sortedMatches(input string) []matchedEntry {
+	var matches []matchedEntry
+	for _, c := range l.candidates(input) {
+		if result := c.pattern.Exec(input, ""); result != nil {
+			matches = append(matches, matchedEntry{c, result})
+		}
+	}
+
+	if l.strategy != StrategyFirstMatch {
+		// Candidates are already sorted by priority and insertion order.
+		slices.SortStableFunc(matches, func(a, b matchedEntry) int {
+			if a.priority != b.priority {
+				return cmp.Compare(b.priority, a.priority)
+			}
+
+			return l.strategy.compare(a.pattern, b.pattern)
+		})
+	}
+
+	return matches
+}
+
+// compare returns a negative number if a is preferred to b according to s, a
+// positive number if b is preferred to a, and 0 otherwise.
+func (s MatchStrategy) compare(a, b *URLPattern) int {
+	switch s {
+	case StrategyMostSpecific:
+		sa, sb := specificityOf(a), specificityOf(b)
+
+		return cmp.Or(
+			cmp.Compare(sb.fixedText, sa.fixedText),
+			cmp.Compare(sa.fullWildcards, sb.fullWildcards),
+			cmp.Compare(sa.groups, sb.groups),
+		)
+	case StrategyLongestPrefix:
+		return cmp.Compare(staticPrefixLen(b.pathname.partList), staticPrefixLen(a.pathname.partList))
+	default:
+		return 0
+	}
+}
+
+// specificity measures how specific a pattern is, see StrategyMostSpecific.
+type specificity struct {
+	fixedText     int
+	fullWildcards int
+	groups        int
+}
+
+func specificityOf(u *URLPattern) specificity {
+	var s specificity
+	for _, name := range componentNames {
+		for _, p := range u.component(name).partList {
+			if p.pType == partFixedText {
+				if p.modifier == partModifierNone {
+					s.fixedText += len(p.value)
+				}
+
+				continue
+			}
+
+			s.groups++
+			if p.pType == partFullWildcard {
+				s.fullWildcards++
+			}
+		}
+	}
+
+	return s
+}
+
+// staticPrefixLen returns the length of the fixed text starting the pattern
+// of pl.
+func staticPrefixLen(pl partList) int {
+	var n int
+	for _, p := range pl {
+		if p.pType != partFixedText || p.modifier != partModifierNone {
+			// The prefix of a required group, such as "/" in "/:id", is
+			// fixed text.
+			if p.pType != partFixedText && (p.modifier == partModifierNone || p.modifier == partModifierOneOrMore) {
+				n += len(p.prefix)
+			}
+
+			break
+		}
+
+		n += len(p.value)
+	}
+
+	return n
+}
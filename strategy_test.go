@@ -0,0 +1,123 @@
+package urlpattern_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestMatchStrategy(t *testing.T) {
+	var list urlpattern.URLPatternList
+	for _, p := range []string{
+		"https://*.example.com/*",
+		"https://example.com/:section/*",
+		"https://example.com/static/*",
+		"https://example.com/static/:file.css",
+	} {
+		pattern, err := urlpattern.Compile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list.AddRoute(urlpattern.Route{Pattern: pattern, Name: p})
+	}
+
+	const input = "https://example.com/static/app.css"
+	for _, tc := range []struct {
+		strategy urlpattern.MatchStrategy
+		want     []string
+	}{
+		{urlpattern.StrategyFirstMatch, []string{"https://example.com/:section/*", "https://example.com/static/*", "https://example.com/static/:file.css"}},
+		{urlpattern.StrategyMostSpecific, []string{"https://example.com/static/:file.css", "https://example.com/static/*", "https://example.com/:section/*"}},
+		{urlpattern.StrategyLongestPrefix, []string{"https://example.com/static/*", "https://example.com/static/:file.css", "https://example.com/:section/*"}},
+	} {
+		list.SetStrategy(tc.strategy)
+
+		matches := list.MatchAll(input)
+		if len(matches) != len(tc.want) {
+			t.Fatalf("%d: want %d matches; got %d", tc.strategy, len(tc.want), len(matches))
+		}
+		for i, m := range matches {
+			if m.Route.Name != tc.want[i] || m.Result == nil {
+				t.Errorf("%d: want %s at %d; got %s", tc.strategy, tc.want[i], i, m.Route.Name)
+			}
+		}
+
+		if route, _ := list.MatchRoute(input); route.Name != tc.want[0] {
+			t.Errorf("%d: want %s; got %s", tc.strategy, tc.want[0], route.Name)
+		}
+	}
+}
+
+func TestURLPatternListMatches(t *testing.T) {
+	var list urlpattern.URLPatternList
+	for _, p := range []string{
+		"https://example.com/:section/*",
+		"https://example.com/static/*",
+		"https://example.com/static/:file.css",
+	} {
+		pattern, err := urlpattern.Compile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list.Add(pattern)
+	}
+
+	const input = "https://example.com/static/app.css"
+	for _, strategy := range []urlpattern.MatchStrategy{urlpattern.StrategyFirstMatch, urlpattern.StrategyMostSpecific} {
+		list.SetStrategy(strategy)
+
+		matches := list.MatchAll(input)
+		i := 0
+		for pattern, result := range list.Matches(input) {
+			if pattern != matches[i].Route.Pattern || result == nil {
+				t.Errorf("%d: unexpected match %d: %v", strategy, i, pattern)
+			}
+			i++
+		}
+		if i != len(matches) {
+			t.Errorf("%d: want %d matches; got %d", strategy, len(matches), i)
+		}
+
+		for pattern := range list.Matches(input) {
+			if pattern != matches[0].Route.Pattern {
+				t.Errorf("%d: unexpected first match %v", strategy, pattern)
+			}
+
+			break
+		}
+	}
+
+	css, err := urlpattern.Compile("*://*/static/*.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Exclude(css)
+
+	for _, strategy := range []urlpattern.MatchStrategy{urlpattern.StrategyFirstMatch, urlpattern.StrategyMostSpecific} {
+		list.SetStrategy(strategy)
+
+		for pattern := range list.Matches(input) {
+			t.Errorf("%d: want no match; got %v", strategy, pattern)
+		}
+	}
+}
+
+func TestMatchStrategyExtremePriorities(t *testing.T) {
+	var list urlpattern.URLPatternList
+	for _, priority := range []int{-2, math.MaxInt} {
+		p, err := urlpattern.Compile("https://example.com/books/*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		list.AddRoute(urlpattern.Route{Pattern: p, Priority: priority})
+	}
+
+	for _, strategy := range []urlpattern.MatchStrategy{urlpattern.StrategyMostSpecific, urlpattern.StrategyLongestPrefix} {
+		list.SetStrategy(strategy)
+
+		if matches := list.MatchAll("https://example.com/books/1"); len(matches) != 2 || matches[0].Route.Priority != math.MaxInt {
+			t.Errorf("%d: want the route with the highest priority first; got %#v", strategy, matches)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownTemplateVariable is returned by ExpandTemplate when a variable of
+// the template has no value.
+var ErrUnknownTemplateVariable = errors.New("unknown template variable")
+
+// ExpandTemplate replaces the variables of template, written {name}, by their
+// values in vars, escaped so that they are matched as fixed text. It allows
+// stamping out families of patterns from one template, e.g. one per tenant:
+//
+//	pattern, err := urlpattern.ExpandTemplate("https://{tenant}.example.com/:path*", map[string]string{"tenant": "acme"})
+//
+// As "{" and "}" also delimit groups in patterns, only the groups containing a
+// valid group name and not followed by a modifier are variables: "{s}?" in
+// "http{s}?" is left as is. ErrUnknownTemplateVariable is returned if a
+// variable isn't defined in vars.
+func ExpandTemplate(template string, vars map[string]string) (string, error) {
+	tl, err := tokenize(template, tokenizePolicyLenient)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		result strings.Builder
+		last   int
+	)
+
+	for i := 0; i < len(tl)-1; i++ {
+		if tl[i].tType != tokenOpen {
+			continue
+		}
+
+		end := i + 1
+		for end < len(tl) && tl[end].tType == tokenChar {
+			end++
+		}
+
+		if end == i+1 || end == len(tl) || tl[end].tType != tokenClose ||
+			tl[end+1].tType == tokenOtherModifier || tl[end+1].tType == tokenAsterisk {
+			continue
+		}
+
+		name := template[tl[i+1].index:tl[end].index]
+		if !isValidName(name) {
+			continue
+		}
+
+		value, ok := vars[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %q", ErrUnknownTemplateVariable, name)
+		}
+
+		result.WriteString(template[last:tl[i].index])
+		result.WriteString(escapeTemplateValue(value))
+		last = tl[end].index + 1
+		i = end
+	}
+
+	if last == 0 {
+		return template, nil
+	}
+
+	result.WriteString(template[last:])
+
+	return result.String(), nil
+}
+
+// escapeTemplateValue escapes the characters of s having a special meaning in
+// patterns, and the delimiters of the components of constructor strings. "#"
+// and "?" are percent-encoded, as they would otherwise start the hash or the
+// search when canonicalizing the component.
+func escapeTemplateValue(s string) string {
+	s = strings.NewReplacer("#", "%23", "?", "%3F").Replace(s)
+	s = EscapePatternString(s)
+	if !strings.ContainsAny(s, "/@[]") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := range len(s) {
+		switch s[i] {
+		case '/', '@', '[', ']':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
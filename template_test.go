@@ -0,0 +1,47 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func ExampleExpandTemplate() {
+	for _, tenant := range []string{"acme", "globex"} {
+		p, err := urlpattern.ExpandTemplate("http{s}?://{tenant}.example.com/:path*", map[string]string{"tenant": tenant})
+		if err != nil {
+			panic(err)
+		}
+
+		pattern, err := urlpattern.New(p, "", nil)
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Println(p, pattern.Test("https://acme.example.com/books/1", ""))
+	}
+
+	// Output: http{s}?://acme.example.com/:path* true
+	// http{s}?://globex.example.com/:path* false
+}
+
+func TestExpandTemplate(t *testing.T) {
+	p, err := urlpattern.ExpandTemplate("https://example.com/{section}/:id", map[string]string{"section": "a#b?c/d@e:*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pattern, err := urlpattern.New(p, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pattern.Test("https://example.com/a%23b%3Fc/d@e:*/1", "") {
+		t.Errorf("%s: want match", p)
+	}
+
+	if _, err := urlpattern.ExpandTemplate("https://{tenant}.example.com/{unknown}", map[string]string{"tenant": "acme"}); !errors.Is(err, urlpattern.ErrUnknownTemplateVariable) {
+		t.Errorf("want ErrUnknownTemplateVariable; got %v", err)
+	}
+}
@@ -1,14 +1,13 @@
 package urlpattern
 
 import (
-	"errors"
 	"fmt"
 	"unicode"
-
-	"golang.org/x/exp/utf8string"
+	"unicode/utf8"
 )
 
-var ErrType = errors.New("type error")
+// Deprecated: use ErrTypeError.
+var ErrType = ErrTypeError
 
 // https://wicg.github.io/urlpattern/#tokenizing
 type tokenizePolicy bool
@@ -18,8 +17,10 @@ const (
 	tokenizePolicyStrict  tokenizePolicy = true
 )
 
+// tokenizer positions (index, nextIndex and the index of emitted tokens) are
+// byte offsets in input.
 type tokenizer struct {
-	input     *utf8string.String
+	input     string
 	policy    tokenizePolicy
 	tokenList []token
 	index     int
@@ -28,13 +29,17 @@ type tokenizer struct {
 }
 
 func tokenize(input string, policy tokenizePolicy) ([]token, error) {
+	if err := validateUTF8(input); err != nil {
+		return nil, err
+	}
+
 	t := tokenizer{
-		input:     utf8string.NewString(input),
+		input:     input,
 		policy:    policy,
 		tokenList: make([]token, 0, len(input)),
 	}
 
-	len := t.input.RuneCount()
+	len := len(t.input)
 
 	for t.index < len {
 		t.seekAndGetNextCodePoint(t.index)
@@ -213,8 +218,9 @@ func tokenize(input string, policy tokenizePolicy) ([]token, error) {
 }
 
 func (t *tokenizer) getNextCodePoint() {
-	t.codePoint = t.input.At(t.nextIndex)
-	t.nextIndex++
+	codePoint, size := utf8.DecodeRuneInString(t.input[t.nextIndex:])
+	t.codePoint = codePoint
+	t.nextIndex += size
 }
 
 func (t *tokenizer) seekAndGetNextCodePoint(index int) {
@@ -226,7 +232,7 @@ func (t *tokenizer) addToken(tType tokenType, nextPosition, valuePosition, value
 	t.tokenList = append(t.tokenList, token{
 		tType: tType,
 		index: t.index,
-		value: t.input.Slice(valuePosition, valuePosition+valueLength),
+		value: t.input[valuePosition : valuePosition+valueLength],
 	})
 	t.index = nextPosition
 }
@@ -241,7 +247,7 @@ func (t *tokenizer) addTokenWithDefaultPositionAndLength(tType tokenType) {
 
 func (t *tokenizer) processTokenizingError(nextPosition, valuePosition int) error {
 	if t.policy == tokenizePolicyStrict {
-		return fmt.Errorf("%w: %#v", ErrType, t)
+		return fmt.Errorf("%w: %w: %q at index %d of %q", ErrTypeError, ErrInvalidCharacter, t.input[valuePosition:nextPosition], valuePosition, t.input)
 	}
 
 	t.addTokenWithDefaultLength(tokenInvalidChar, nextPosition, valuePosition)
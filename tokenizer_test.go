@@ -1,6 +1,8 @@
 package urlpattern_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -32,3 +34,23 @@ func TestTrailingBackslashDoesNotHang(t *testing.T) {
 		t.Fatal("expected an error for a pathname ending with a lone backslash, got nil")
 	}
 }
+
+func TestInvalidCharacterError(t *testing.T) {
+	for p, want := range map[string]string{
+		"https://example.com/a?(b":   `invalid character: "(" at index 0 of "(b"`,
+		"https://example.com/(a(b))": `invalid character: "(" at index 1 of "/(a(b))"`,
+		"https://example.com/a#b\\":  `invalid character: "\\" at index 1 of "b\\"`,
+		"https://example.com/foo(":   `invalid character: "(" at index 4 of "/foo("`,
+		"https://example.com/:":      `invalid character: ":" at index 1 of "/:"`,
+	} {
+		_, err := urlpattern.Compile(p)
+		if !errors.Is(err, urlpattern.ErrInvalidCharacter) || !errors.Is(err, urlpattern.ErrTypeError) {
+			t.Errorf("%s: want ErrInvalidCharacter; got %v", p, err)
+
+			continue
+		}
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: want %q in %q", p, want, err)
+		}
+	}
+}
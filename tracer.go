@@ -0,0 +1,70 @@
+package urlpattern
+
+import "time"
+
+// Tracer receives instrumentation events, e.g. to profile where time goes in
+// large route tables. Methods are called synchronously, possibly from
+// several goroutines at once, so implementations must be fast and safe for
+// concurrent use.
+type Tracer interface {
+	// OnCompileStart is called before compiling the pattern of a component.
+	OnCompileStart(component, pattern string)
+	// OnCompileEnd is called after compiling the pattern of a component,
+	// with the compilation error, if any.
+	OnCompileEnd(component string, elapsed time.Duration, err error)
+	// OnMatchStart is called before matching the (canonicalized) value of a
+	// component of a URL.
+	OnMatchStart(component, input string)
+	// OnMatchEnd is called after matching the value of a component of a URL.
+	OnMatchEnd(component string, elapsed time.Duration, matched bool)
+}
+
+// WithTracer sets a Tracer receiving the compilation and match events of
+// the pattern.
+func WithTracer(t Tracer) Option {
+	return func(c *config) {
+		c.tracer = t
+	}
+}
+
+// compileComponent compiles a component with the syntax enabled in c,
+// reporting to the tracer if any.
+func (c *config) compileComponent(name, input string, encodingCallback encodingCallback, options options) (*component, error) {
+	var compile compileFunc = compileComponent
+	if name == "pathname" && c.wildcardNames != "" {
+		compile = compileComponentNamingWildcards(c.wildcardNames)
+	}
+	if c.constraints {
+		compile = withConstraints(compile)
+	}
+	options.maxRepetitions = c.maxRepetitions
+
+	if c.tracer == nil {
+		return compile(name, input, encodingCallback, options)
+	}
+
+	c.tracer.OnCompileStart(name, input)
+	start := time.Now()
+
+	component, err := compile(name, input, encodingCallback, options)
+	c.tracer.OnCompileEnd(name, time.Since(start), err)
+
+	return component, err
+}
+
+// exec matches input against the component c named name, reporting to the
+// tracer if any.
+func (u *URLPattern) exec(name string, c *component, input string) []string {
+	t := u.config.tracer
+	if t == nil {
+		return c.exec(input)
+	}
+
+	t.OnMatchStart(name, input)
+	start := time.Now()
+
+	execResult := c.exec(input)
+	t.OnMatchEnd(name, time.Since(start), execResult != nil)
+
+	return execResult
+}
@@ -0,0 +1,77 @@
+package urlpattern_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dunglas/go-urlpattern"
+	"github.com/dunglas/go-urlpattern/urlpatterntest"
+)
+
+type recordingTracer struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingTracer) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *recordingTracer) OnCompileStart(component, _ string) {
+	r.record("compile start " + component)
+}
+
+func (r *recordingTracer) OnCompileEnd(component string, _ time.Duration, err error) {
+	if err != nil {
+		r.record("compile error " + component)
+
+		return
+	}
+
+	r.record("compile end " + component)
+}
+
+func (r *recordingTracer) OnMatchStart(component, _ string) {
+	r.record("match start " + component)
+}
+
+func (r *recordingTracer) OnMatchEnd(component string, _ time.Duration, matched bool) {
+	if !matched {
+		r.record("miss " + component)
+
+		return
+	}
+
+	r.record("match end " + component)
+}
+
+func TestTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+
+	pattern, err := urlpattern.Compile("https://example.com/books/:id", urlpattern.WithTracer(tracer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tracer.events) != 16 || tracer.events[0] != "compile start protocol" || tracer.events[15] != "compile end hash" {
+		t.Errorf("unexpected compile events %v", tracer.events)
+	}
+
+	tracer.events = nil
+	pattern.Test("https://example.com/authors/1", "")
+
+	if len(tracer.events) != 16 || !slices.Contains(tracer.events, "miss pathname") || !slices.Contains(tracer.events, "match end hostname") {
+		t.Errorf("unexpected match events %v", tracer.events)
+	}
+}
+
+func TestTracerConformance(t *testing.T) {
+	urlpatterntest.Run(t, urlpatterntest.Entries(), urlpatterntest.Config{
+		Options: []urlpattern.Option{urlpattern.WithTracer(&recordingTracer{})},
+	})
+}
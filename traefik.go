@@ -0,0 +1,311 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedRule is returned by ParseTraefikRule and
+// MethodPattern.TraefikRule when a rule cannot be converted.
+var ErrUnsupportedRule = errors.New("unsupported rule")
+
+// ParseTraefikRule converts a Traefik router rule made of matchers joined by
+// "&&", such as "Host(`example.com`) && PathPrefix(`/api`)", to a
+// MethodPattern, enabling URLPattern-driven tooling on top of reverse proxy
+// configurations. The supported matchers are Host, HostRegexp, Path,
+// PathPrefix, PathRegexp, Query (see WithSearchParams) and Method. Other
+// matchers, "||" and "!" are not supported.
+//
+// Regular expressions are matched against the whole component unless they
+// are anchored with "^" or "$".
+func ParseTraefikRule(rule string, opts ...Option) (*MethodPattern, error) {
+	matchers, err := parseTraefikMatchers(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		init    URLPatternInit
+		methods []string
+		query   map[string]string
+	)
+	set := func(dst **string, value string, m traefikMatcher) error {
+		if *dst != nil {
+			return fmt.Errorf("%w: several matchers for the same component in %q", ErrUnsupportedRule, m.name)
+		}
+		*dst = &value
+
+		return nil
+	}
+
+	for _, m := range matchers {
+		var err error
+
+		switch {
+		case m.name == "Query" && (len(m.args) == 1 || len(m.args) == 2):
+			key, value, _ := strings.Cut(m.args[0], "=")
+			if len(m.args) == 2 {
+				key, value = m.args[0], m.args[1]
+			}
+			if query == nil {
+				query = make(map[string]string)
+			}
+			query[key] = EscapePatternString(value)
+
+			continue
+
+		case len(m.args) != 1:
+			return nil, fmt.Errorf("%w: %s must have one argument", ErrUnsupportedRule, m.name)
+
+		case m.name == "Host":
+			err = set(&init.Hostname, EscapePatternString(m.args[0]), m)
+		case m.name == "HostRegexp":
+			err = set(&init.Hostname, traefikRegexpGroup(m.args[0]), m)
+		case m.name == "Path":
+			err = set(&init.Pathname, EscapePatternString(m.args[0]), m)
+		case m.name == "PathPrefix":
+			err = set(&init.Pathname, EscapePatternString(m.args[0])+"*", m)
+		case m.name == "PathRegexp":
+			err = set(&init.Pathname, traefikRegexpGroup(m.args[0]), m)
+		case m.name == "Method":
+			methods = append(methods, m.args[0])
+		default:
+			return nil, fmt.Errorf("%w: matcher %s", ErrUnsupportedRule, m.name)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if query != nil {
+		searchParams, err := NewSearchParamsPattern(query)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(slices.Clip(opts), WithSearchParams(searchParams))
+	}
+
+	u, err := Compile(&init, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMethodPattern(u, methods...), nil
+}
+
+// traefikRegexpGroup converts a regular expression of Traefik, matching
+// anywhere unless anchored, to a regexp group matching a whole component.
+func traefikRegexpGroup(re string) string {
+	re, start := strings.CutPrefix(re, "^")
+	if !start {
+		re = ".*" + re
+	}
+
+	re, end := strings.CutSuffix(re, "$")
+	if !end {
+		re += ".*"
+	}
+
+	return "(" + re + ")"
+}
+
+// traefikMatcher is a matcher of a Traefik rule, such as Host(`example.com`).
+type traefikMatcher struct {
+	name string
+	args []string
+}
+
+// parseTraefikMatchers parses a rule made of matchers joined by "&&".
+func parseTraefikMatchers(rule string) ([]traefikMatcher, error) {
+	var matchers []traefikMatcher
+
+	s := strings.TrimSpace(rule)
+	for {
+		open := strings.IndexByte(s, '(')
+		if open == -1 {
+			return nil, fmt.Errorf("%w: expected a matcher in %q", ErrUnsupportedRule, s)
+		}
+
+		m := traefikMatcher{name: strings.TrimSpace(s[:open])}
+		if m.name == "" || strings.ContainsAny(m.name, "!|&") {
+			return nil, fmt.Errorf("%w: expected a matcher in %q", ErrUnsupportedRule, s)
+		}
+
+		s = s[open+1:]
+		for {
+			s = strings.TrimSpace(s)
+			if s == "" || (s[0] != '`' && s[0] != '"') {
+				return nil, fmt.Errorf("%w: expected a quoted argument in %q", ErrUnsupportedRule, rule)
+			}
+
+			end := closingQuote(s)
+			if end == -1 {
+				return nil, fmt.Errorf("%w: unterminated argument in %q", ErrUnsupportedRule, rule)
+			}
+
+			arg, err := strconv.Unquote(s[:end+1])
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid argument %s: %w", ErrUnsupportedRule, s[:end+1], err)
+			}
+
+			m.args = append(m.args, arg)
+			s = strings.TrimSpace(s[end+1:])
+
+			if strings.HasPrefix(s, ",") {
+				s = s[1:]
+
+				continue
+			}
+
+			if !strings.HasPrefix(s, ")") {
+				return nil, fmt.Errorf("%w: expected ) in %q", ErrUnsupportedRule, rule)
+			}
+			s = strings.TrimSpace(s[1:])
+
+			break
+		}
+
+		matchers = append(matchers, m)
+
+		if s == "" {
+			return matchers, nil
+		}
+
+		var ok bool
+		if s, ok = strings.CutPrefix(s, "&&"); !ok {
+			return nil, fmt.Errorf("%w: only && is supported in %q", ErrUnsupportedRule, rule)
+		}
+		s = strings.TrimSpace(s)
+	}
+}
+
+// closingQuote returns the index of the quote closing the string literal
+// starting s, or -1. Raw string literals cannot contain escape sequences.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case s[0]:
+			return i
+		case '\\':
+			if s[0] == '"' {
+				i++
+			}
+		}
+	}
+
+	return -1
+}
+
+// TraefikRule converts m to a Traefik router rule, the reverse of
+// ParseTraefikRule. Fixed hostnames and pathnames are converted to Host and
+// Path matchers, pathnames made of fixed text followed by "*" to PathPrefix,
+// and the other hostnames and pathnames to HostRegexp and PathRegexp.
+// Parameters of patterns compiled with WithSearchParams are converted to
+// Query matchers if their values are fixed text.
+//
+// ErrUnsupportedRule is returned if the protocol, username, password, port,
+// search or hash of the pattern isn't "*", or if it ignores case.
+func (m *MethodPattern) TraefikRule() (string, error) {
+	u := m.pattern
+
+	if u.config.ignoreCase {
+		return "", fmt.Errorf("%w: case-insensitive pattern", ErrUnsupportedRule)
+	}
+
+	for _, c := range []*component{u.protocol, u.username, u.password, u.port, u.search, u.hash} {
+		if c.patternString != "*" {
+			return "", fmt.Errorf("%w: %q must be \"*\"", ErrUnsupportedRule, c.patternString)
+		}
+	}
+
+	var matchers []string
+	add := func(name string, args ...string) {
+		quoted := make([]string, len(args))
+		for i, a := range args {
+			if strconv.CanBackquote(a) {
+				quoted[i] = "`" + a + "`"
+			} else {
+				quoted[i] = strconv.Quote(a)
+			}
+		}
+
+		matchers = append(matchers, name+"("+strings.Join(quoted, ", ")+")")
+	}
+
+	if u.hostname.patternString != "*" {
+		if hostname, ok := fixedText(u.hostname); ok {
+			add("Host", hostname)
+		} else {
+			add("HostRegexp", traefikRegexp(u.hostname))
+		}
+	}
+
+	if u.pathname.patternString != "*" {
+		if pathname, ok := fixedText(u.pathname); ok {
+			add("Path", pathname)
+		} else if prefix, ok := traefikPathPrefix(u.pathname.partList); ok {
+			add("PathPrefix", prefix)
+		} else {
+			add("PathRegexp", traefikRegexp(u.pathname))
+		}
+	}
+
+	if sp := u.config.searchParams; sp != nil {
+		for _, p := range sp.params {
+			value, ok := fixedText(p.component)
+			if !ok || p.component.patternString == "*" {
+				return "", fmt.Errorf("%w: search parameter %q", ErrUnsupportedRule, p.key)
+			}
+
+			add("Query", p.key, value)
+		}
+	}
+
+	for _, method := range m.methods {
+		add("Method", method)
+	}
+
+	if len(matchers) == 0 {
+		return "PathPrefix(`/`)", nil
+	}
+
+	return strings.Join(matchers, " && "), nil
+}
+
+// traefikRegexp returns the regular expression of c, anchored.
+func traefikRegexp(c *component) string {
+	re := c.regularExpression.String()
+	re = strings.TrimPrefix(re, `\A`)
+	re = strings.TrimSuffix(re, `\z`)
+
+	return "^" + re + "$"
+}
+
+// traefikPathPrefix returns the prefix matched by a pathname made of fixed
+// text followed by a "*" wildcard.
+func traefikPathPrefix(pl partList) (string, bool) {
+	if len(pl) == 0 {
+		return "", false
+	}
+
+	last := pl[len(pl)-1]
+	if last.pType != partFullWildcard || last.modifier != partModifierNone || last.suffix != "" {
+		return "", false
+	}
+
+	var b strings.Builder
+	for _, p := range pl[:len(pl)-1] {
+		if p.pType != partFixedText || p.modifier != partModifierNone {
+			return "", false
+		}
+		b.WriteString(p.value)
+	}
+	b.WriteString(last.prefix)
+
+	return b.String(), true
+}
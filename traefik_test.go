@@ -0,0 +1,90 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestTraefikRule(t *testing.T) {
+	for _, tc := range []struct {
+		rule    string
+		match   []string
+		noMatch []string
+	}{
+		{"Host(`example.com`) && PathPrefix(`/api/`)", []string{"https://example.com/api/", "http://example.com:8080/api/users?a=b"}, []string{"https://example.org/api/", "https://example.com/api"}},
+		{"PathPrefix(`/api`)", []string{"https://example.com/api", "https://example.com/apis"}, []string{"https://example.com/"}},
+		{"Path(`/users`) && Method(`GET`)", []string{"https://example.com/users"}, []string{"https://example.com/users/1"}},
+		{"Path(`/search`) && Query(`q`, `go`)", []string{"https://example.com/search?q=go&page=2"}, []string{"https://example.com/search?q=rust", "https://example.com/search"}},
+		{"HostRegexp(`^[a-z]+\\.example\\.com$`)", []string{"https://api.example.com/"}, []string{"https://example.com/", "https://api.example.org/"}},
+	} {
+		t.Run(tc.rule, func(t *testing.T) {
+			m, err := urlpattern.ParseTraefikRule(tc.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, input := range tc.match {
+				if !m.Pattern().Test(input, "") {
+					t.Errorf("want %q to match", input)
+				}
+			}
+			for _, input := range tc.noMatch {
+				if m.Pattern().Test(input, "") {
+					t.Errorf("want %q not to match", input)
+				}
+			}
+
+			if _, err := m.TraefikRule(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	for _, rule := range []string{
+		"Host(`example.com`) && PathPrefix(`/api/`)",
+		"Path(`/users`) && Query(`q`, `go`) && Method(`GET`)",
+		"Host(\"a`b.example\")",
+	} {
+		m, err := urlpattern.ParseTraefikRule(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err := m.TraefikRule(); err != nil || got != rule {
+			t.Errorf("want %q; got %q, %v", rule, got, err)
+		}
+	}
+
+	m, err := urlpattern.ParseTraefikRule("PathPrefix(\"/api\") && Query(`page=2`)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := m.Pattern().Exec("https://example.com/api/v1?page=2", ""); r == nil || r.SearchParams["page"].Input != "2" {
+		t.Errorf("unexpected result %#v", r)
+	}
+
+	for _, rule := range []string{"", "Host(`a`) || Host(`b`)", "!Host(`a`)", "Host(`a`) && Host(`b`)", "Header(`a`, `b`)", "Host(`a`", "Host(a)", "(Host(`a`))"} {
+		if _, err := urlpattern.ParseTraefikRule(rule); !errors.Is(err, urlpattern.ErrUnsupportedRule) {
+			t.Errorf("%s: want ErrUnsupportedRule; got %v", rule, err)
+		}
+	}
+
+	u, err := urlpattern.Compile("https://example.com/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := urlpattern.NewMethodPattern(u).TraefikRule(); !errors.Is(err, urlpattern.ErrUnsupportedRule) {
+		t.Errorf("want ErrUnsupportedRule; got %v", err)
+	}
+
+	pathname := "/users/:id(\\d+)"
+	u, err = urlpattern.Compile(&urlpattern.URLPatternInit{Pathname: &pathname})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := urlpattern.NewMethodPattern(u).TraefikRule(); err != nil || got != "PathRegexp(`^(?:\\/users(?:\\/(?P<id>\\d+)))$`)" {
+		t.Errorf("unexpected rule %q, %v", got, err)
+	}
+}
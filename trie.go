@@ -0,0 +1,103 @@
+package urlpattern
+
+import (
+	"cmp"
+	"slices"
+)
+
+// patternEntry is a pattern stored in a trie, with its insertion index and
+// its priority.
+type patternEntry struct {
+	index    int
+	priority int
+	pattern  *URLPattern
+}
+
+// comparePatternEntries orders entries by decreasing priority, then by
+// insertion index.
+func comparePatternEntries(a, b patternEntry) int {
+	if a.priority != b.priority {
+		return cmp.Compare(b.priority, a.priority)
+	}
+
+	return a.index - b.index
+}
+
+// trieNode is a node of a trie of labels (hostname labels or pathname
+// segments) used to pre-filter the patterns that may match an input.
+type trieNode struct {
+	children map[string]*trieNode
+	// exact holds the patterns whose fixed text is exactly the labels of the
+	// path to the node.
+	exact []patternEntry
+	// prefix holds the patterns whose fixed text starts with the labels of
+	// the path to the node.
+	prefix []patternEntry
+}
+
+// insert adds e under labels.
+func (n *trieNode) insert(labels []string, e patternEntry, exact bool) {
+	for _, label := range labels {
+		child, ok := n.children[label]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[string]*trieNode)
+			}
+
+			child = &trieNode{}
+			n.children[label] = child
+		}
+
+		n = child
+	}
+
+	if exact {
+		n.exact = append(n.exact, e)
+	} else {
+		n.prefix = append(n.prefix, e)
+	}
+}
+
+// candidates appends to entries the patterns that may match an input made of
+// labels, sorted by decreasing priority and insertion index.
+func (n *trieNode) candidates(entries []patternEntry, labels []string) []patternEntry {
+	entries = append(entries, n.prefix...)
+
+	i := 0
+	for ; i < len(labels); i++ {
+		child, ok := n.children[labels[i]]
+		if !ok {
+			break
+		}
+
+		n = child
+		entries = append(entries, n.prefix...)
+	}
+
+	if i == len(labels) {
+		entries = append(entries, n.exact...)
+	}
+
+	slices.SortFunc(entries, comparePatternEntries)
+
+	return entries
+}
+
+// all appends to entries all the patterns of the trie, sorted by decreasing
+// priority and insertion index.
+func (n *trieNode) all(entries []patternEntry) []patternEntry {
+	var walk func(n *trieNode)
+	walk = func(n *trieNode) {
+		entries = append(entries, n.exact...)
+		entries = append(entries, n.prefix...)
+
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	slices.SortFunc(entries, comparePatternEntries)
+
+	return entries
+}
@@ -0,0 +1,52 @@
+package urlpattern
+
+// TypedList is a URLPatternList whose routes carry a value of type T, so that
+// gateways can colocate per-route configuration (timeouts, retry policy,
+// upstream...) with the patterns and get it back without type assertions:
+//
+//	var routes urlpattern.TypedList[Upstream]
+//	routes.Add(pattern, Upstream{Addr: "10.0.0.1:8080", Timeout: 5 * time.Second})
+//
+//	if upstream, result, ok := routes.FirstMatch(input); ok {
+//		// ...
+//	}
+//
+// The values are stored as the metadata of the routes. The zero value is ready
+// to use, and the concurrency rules of URLPatternList apply.
+type TypedList[T any] struct {
+	list URLPatternList
+}
+
+// Add appends pattern to the list, with the default priority and value.
+func (l *TypedList[T]) Add(pattern *URLPattern, value T) {
+	l.list.AddRoute(Route{Pattern: pattern, Metadata: value})
+}
+
+// AddRoute appends route to the list, with value as its metadata.
+func (l *TypedList[T]) AddRoute(route Route, value T) {
+	route.Metadata = value
+	l.list.AddRoute(route)
+}
+
+// FirstMatch returns the value of the route matching input, as returned by
+// URLPatternList.MatchRoute, the result of the match, and whether a route
+// matches.
+func (l *TypedList[T]) FirstMatch(input string) (T, *URLPatternResult, bool) {
+	route, result := l.list.MatchRoute(input)
+	if result == nil {
+		var zero T
+
+		return zero, nil, false
+	}
+
+	// Routes added to the underlying list without value have the zero value.
+	value, _ := route.Metadata.(T)
+
+	return value, result, true
+}
+
+// List returns the underlying list, e.g. to set its strategy, metrics and
+// exclusions, or to list its routes.
+func (l *TypedList[T]) List() *URLPatternList {
+	return &l.list
+}
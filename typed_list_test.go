@@ -0,0 +1,56 @@
+package urlpattern_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestTypedList(t *testing.T) {
+	type upstream struct {
+		addr    string
+		timeout time.Duration
+	}
+
+	var routes urlpattern.TypedList[upstream]
+	for _, r := range []struct {
+		pattern  string
+		priority int
+		upstream upstream
+	}{
+		{"https://example.com/api/*", 0, upstream{"10.0.0.1:8080", 5 * time.Second}},
+		{"https://example.com/api/reports/*", 1, upstream{"10.0.0.2:8080", time.Minute}},
+	} {
+		p, err := urlpattern.Compile(r.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		routes.AddRoute(urlpattern.Route{Pattern: p, Priority: r.priority}, r.upstream)
+	}
+
+	other, err := urlpattern.Compile("https://example.com/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes.List().Add(other)
+
+	for input, expected := range map[string]upstream{
+		"https://example.com/api/books":     {"10.0.0.1:8080", 5 * time.Second},
+		"https://example.com/api/reports/1": {"10.0.0.2:8080", time.Minute},
+		"https://example.com/about":         {},
+	} {
+		u, result, ok := routes.FirstMatch(input)
+		if !ok || result == nil || u != expected {
+			t.Errorf("%s: got %v, %v; want %v", input, u, ok, expected)
+		}
+	}
+
+	if u, result, ok := routes.FirstMatch("https://example.org/"); ok || result != nil || u != (upstream{}) {
+		t.Errorf("want no match; got %v", u)
+	}
+
+	if routes.List().Len() != 3 {
+		t.Errorf("want 3 routes; got %d", routes.List().Len())
+	}
+}
@@ -0,0 +1,110 @@
+package urlpattern
+
+import (
+	"fmt"
+	"regexp/syntax"
+)
+
+// UnsupportedRegexpFeatureError is returned when a regexp group of a pattern
+// uses a feature of JavaScript's unicode sets ("v") mode that Go regular
+// expressions do not support.
+//
+// Go would either reject these constructs or silently give them another
+// meaning (e.g. "&&" is a literal in a Go character class), so they are
+// detected before compiling the regular expression.
+type UnsupportedRegexpFeatureError struct {
+	// Component is the name of the component containing the regexp group,
+	// e.g. "pathname".
+	Component string
+	// Feature describes the unsupported construct, e.g. "set subtraction".
+	Feature string
+	// Regexp is the value of the offending regexp group.
+	Regexp string
+}
+
+func (e *UnsupportedRegexpFeatureError) Error() string {
+	return fmt.Sprintf("%s: unsupported regexp feature %s in %q", e.Component, e.Feature, e.Regexp)
+}
+
+// https://developer.mozilla.org/en-US/docs/Web/JavaScript/Reference/Global_Objects/RegExp/unicodeSets
+const (
+	featureSetSubtraction  = "set subtraction"
+	featureSetIntersection = "set intersection"
+	featureStringLiteral   = `string literal (\q{...})`
+	featureNestedCharClass = "nested character class"
+)
+
+// checkRegexpFeatures returns an *UnsupportedRegexpFeatureError if a regexp
+// part of pl uses a unicode sets mode construct, and the parsing error if a
+// regexp part isn't a valid regular expression on its own: an unterminated
+// character class could otherwise swallow the following groups of the
+// generated regular expression.
+func checkRegexpFeatures(componentName string, pl partList) error {
+	for _, p := range pl {
+		if p.pType != partRegexp {
+			continue
+		}
+
+		if feature := unsupportedRegexpFeature(p.value); feature != "" {
+			return &UnsupportedRegexpFeatureError{Component: componentName, Feature: feature, Regexp: p.value}
+		}
+
+		if _, err := syntax.Parse(p.value, syntax.Perl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unsupportedRegexpFeature returns the unicode sets mode construct found in
+// a character class of re, or the empty string. Set operations are reported
+// in priority over the nested classes they usually operate on.
+func unsupportedRegexpFeature(re string) string {
+	depth := 0
+	nested := false
+
+	// All the inspected code points are ASCII (regexp tokens only contain
+	// ASCII), so a byte loop is correct.
+	for i := 0; i < len(re); i++ {
+		c := re[i]
+		next := byte(0)
+		if i+1 < len(re) {
+			next = re[i+1]
+		}
+
+		switch {
+		case c == '\\':
+			if depth > 0 && next == 'q' {
+				return featureStringLiteral
+			}
+
+			i++
+
+		case c == '[':
+			if depth > 0 {
+				nested = true
+			}
+
+			depth++
+
+		case depth == 0:
+			// Set operations only exist in character classes.
+
+		case c == ']':
+			depth--
+
+		case c == '-' && next == '-':
+			return featureSetSubtraction
+
+		case c == '&' && next == '&':
+			return featureSetIntersection
+		}
+	}
+
+	if nested {
+		return featureNestedCharClass
+	}
+
+	return ""
+}
@@ -0,0 +1,180 @@
+package urlpattern
+
+import "github.com/nlnwa/whatwg-url/url"
+
+// URLParser parses URLs as specified by the WHATWG URL standard, see
+// WithURLParser.
+//
+// It is used both to canonicalize the components of patterns and to parse the
+// URLs matched by Exec and Test.
+type URLParser interface {
+	// Parse parses input, relative to baseURL if not empty, and returns
+	// its components.
+	Parse(input, baseURL string) (URLComponents, error)
+	// BasicParser runs the basic URL parser on input with state as state
+	// override, starting from the URL scheme://dummy.test, or from a new
+	// empty URL if scheme is empty, and returns the components of the
+	// resulting URL.
+	//
+	// https://url.spec.whatwg.org/#concept-basic-url-parser
+	BasicParser(input, scheme string, state ParserState) (URLComponents, error)
+	// PercentEncodeString percent-encodes the code points of s in set.
+	//
+	// https://url.spec.whatwg.org/#string-percent-encode-after-encoding
+	PercentEncodeString(s string, set PercentEncodeSet) string
+}
+
+// ParserState is a state override of the basic URL parser, see
+// URLParser.BasicParser.
+type ParserState uint8
+
+const (
+	ParserStateHostname ParserState = iota
+	ParserStatePort
+	ParserStatePathStart
+	ParserStateOpaquePath
+	ParserStateQuery
+	ParserStateFragment
+)
+
+// PercentEncodeSet is a percent-encode set of the WHATWG URL standard, see
+// URLParser.PercentEncodeString.
+type PercentEncodeSet uint8
+
+const (
+	// PercentEncodeUserInfo is the userinfo percent-encode set.
+	//
+	// https://url.spec.whatwg.org/#userinfo-percent-encode-set
+	PercentEncodeUserInfo PercentEncodeSet = iota
+)
+
+// URLComponents holds the components of a parsed URL, canonicalized as by
+// the WHATWG URL standard, without delimiters: no ":" after the protocol,
+// no "?" before the search and no "#" before the hash.
+type URLComponents struct {
+	Protocol string
+	Username string
+	Password string
+	Hostname string
+	Port     string
+	Pathname string
+	Search   string
+	Hash     string
+}
+
+// WithURLParser sets the parser used to canonicalize patterns and to parse
+// the URLs matched by Exec and Test, instead of the WHATWG URL parser of
+// github.com/nlnwa/whatwg-url, e.g. for performance. IDNAMode still applies
+// to the hostnames returned by p.
+func WithURLParser(p URLParser) Option {
+	return func(c *config) {
+		c.urlParser = p
+	}
+}
+
+// parser returns the URLParser set with WithURLParser, or the default one.
+func (c *config) parser() URLParser {
+	if c.urlParser != nil {
+		return c.urlParser
+	}
+
+	return c.idnaMode.parser()
+}
+
+// whatwgURLParser is the default URLParser.
+type whatwgURLParser struct {
+	parser url.Parser
+	// hostnameParser is used for ParserStateHostname, see IDNAMode.
+	hostnameParser url.Parser
+}
+
+// whatwgParserStates maps the states of ParserState to the states of
+// github.com/nlnwa/whatwg-url.
+var whatwgParserStates = [...]url.State{
+	ParserStateHostname:   url.StateHostname,
+	ParserStatePort:       url.StatePort,
+	ParserStatePathStart:  url.StatePathStart,
+	ParserStateOpaquePath: url.StateOpaquePath,
+	ParserStateQuery:      url.StateQuery,
+	ParserStateFragment:   url.StateFragment,
+}
+
+func (p whatwgURLParser) Parse(input, baseURLString string) (URLComponents, error) {
+	var baseURL *url.Url
+	if baseURLString != "" {
+		var err error
+		if baseURL, err = p.parser.Parse(baseURLString); err != nil {
+			return URLComponents{}, err
+		}
+	}
+
+	u, err := p.parser.BasicParser(input, baseURL, nil, url.NoState)
+	if err != nil {
+		return URLComponents{}, err
+	}
+
+	return whatwgURLComponents(u), nil
+}
+
+func (p whatwgURLParser) BasicParser(input, scheme string, state ParserState) (URLComponents, error) {
+	parser := p.parser
+	if state == ParserStateHostname {
+		parser = p.hostnameParser
+	}
+
+	u := parser.NewUrl()
+	if scheme != "" {
+		var err error
+		if u, err = parser.Parse(scheme + "://dummy.test"); err != nil {
+			return URLComponents{}, err
+		}
+	}
+
+	u, err := parser.BasicParser(input, nil, u, whatwgParserStates[state])
+	if err != nil {
+		return URLComponents{}, err
+	}
+
+	return whatwgURLComponents(u), nil
+}
+
+// PercentEncodeString encodes s in the userinfo percent-encode set, the only
+// PercentEncodeSet.
+func (p whatwgURLParser) PercentEncodeString(s string, _ PercentEncodeSet) string {
+	return p.parser.PercentEncodeString(s, url.UserInfoPercentEncodeSet)
+}
+
+// whatwgURLComponents returns the components of u.
+func whatwgURLComponents(u *url.Url) URLComponents {
+	return URLComponents{
+		Protocol: u.Scheme(),
+		Username: u.Username(),
+		Password: u.Password(),
+		Hostname: u.Hostname(),
+		Port:     u.Port(),
+		Pathname: u.Pathname(),
+		Search:   u.Query(),
+		Hash:     u.Fragment(),
+	}
+}
+
+// ExecParsed matches u, a URL already parsed with github.com/nlnwa/whatwg-url,
+// against the pattern, avoiding parsing it again for applications already
+// using that package. As u is already resolved, base, the URL u was parsed
+// against if not nil, is only reported in the Inputs of the result, like the
+// baseURL parameter of Exec.
+//
+// The URL parser set with WithURLParser is not used.
+func (u *URLPattern) ExecParsed(input *url.Url, base *url.Url) *URLPatternResult {
+	c := whatwgURLComponents(input)
+
+	r := u.match(c.Protocol, c.Username, c.Password, u.config.idnaMode.hostname(c.Hostname), c.Port, c.Pathname, c.Search, c.Hash)
+	if r != nil {
+		r.Inputs = []string{input.Href(false)}
+		if base != nil {
+			r.Inputs = append(r.Inputs, base.Href(false))
+		}
+	}
+
+	return r
+}
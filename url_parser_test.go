@@ -0,0 +1,133 @@
+package urlpattern_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+	whatwgurl "github.com/nlnwa/whatwg-url/url"
+)
+
+// stdlibURLParser is a naive URLParser based on net/url.
+type stdlibURLParser struct {
+	calls      int
+	basicCalls int
+}
+
+func (p *stdlibURLParser) Parse(input, baseURL string) (urlpattern.URLComponents, error) {
+	p.calls++
+
+	u, err := url.Parse(input)
+	if err != nil {
+		return urlpattern.URLComponents{}, err
+	}
+
+	if baseURL != "" {
+		base, err := url.Parse(baseURL)
+		if err != nil {
+			return urlpattern.URLComponents{}, err
+		}
+
+		u = base.ResolveReference(u)
+	}
+
+	password, _ := u.User.Password()
+
+	return urlpattern.URLComponents{
+		Protocol: u.Scheme,
+		Username: u.User.Username(),
+		Password: password,
+		Hostname: strings.ToLower(u.Hostname()),
+		Port:     u.Port(),
+		Pathname: u.EscapedPath(),
+		Search:   u.RawQuery,
+		Hash:     u.EscapedFragment(),
+	}, nil
+}
+
+func (p *stdlibURLParser) BasicParser(input, _ string, state urlpattern.ParserState) (urlpattern.URLComponents, error) {
+	p.basicCalls++
+
+	var c urlpattern.URLComponents
+	switch state {
+	case urlpattern.ParserStateHostname:
+		c.Hostname = strings.ToLower(input)
+	case urlpattern.ParserStatePort:
+		c.Port = input
+	case urlpattern.ParserStatePathStart:
+		c.Pathname = (&url.URL{Path: input}).EscapedPath()
+	case urlpattern.ParserStateOpaquePath:
+		c.Pathname = input
+	case urlpattern.ParserStateQuery:
+		c.Search = input
+	case urlpattern.ParserStateFragment:
+		c.Hash = (&url.URL{Fragment: input}).EscapedFragment()
+	}
+
+	return c, nil
+}
+
+func (p *stdlibURLParser) PercentEncodeString(s string, _ urlpattern.PercentEncodeSet) string {
+	return url.PathEscape(s)
+}
+
+func TestWithURLParser(t *testing.T) {
+	parser := &stdlibURLParser{}
+
+	pattern, err := urlpattern.Compile("https://example.com/books/:id", urlpattern.WithURLParser(parser))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parser.basicCalls == 0 {
+		t.Error("the pattern must be canonicalized with the parser")
+	}
+	compileCalls := parser.calls
+
+	r := pattern.Exec("/books/42", "https://EXAMPLE.com/authors/")
+	if r == nil || r.Pathname.Groups["id"] != "42" {
+		t.Errorf("unexpected result %#v", r)
+	}
+
+	if pattern.Test("https://example.com/authors/42", "") {
+		t.Error("pattern must not match")
+	}
+
+	if n := parser.calls - compileCalls; n != 2 {
+		t.Errorf("the parser must be called twice, got %d", n)
+	}
+}
+
+func TestExecParsed(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://*.example.com/books/:id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parser := whatwgurl.NewParser()
+	base, err := parser.Parse("https://www.example.com/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := parser.ParseRef(base.Href(false), "books/42?page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := pattern.ExecParsed(input, base)
+	if r == nil || r.Hostname.Groups["0"] != "www" || r.Pathname.Groups["id"] != "42" || r.Search.Input != "page=2" {
+		t.Fatalf("unexpected result %#v", r)
+	}
+	if len(r.Inputs) != 2 || r.Inputs[0] != "https://www.example.com/books/42?page=2" || r.Inputs[1] != "https://www.example.com/index.html" {
+		t.Errorf("unexpected inputs %q", r.Inputs)
+	}
+
+	input, err = parser.Parse("https://example.org/books/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pattern.ExecParsed(input, nil) != nil {
+		t.Error("pattern must not match")
+	}
+}
@@ -5,15 +5,30 @@ package urlpattern
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"strings"
-
-	"github.com/nlnwa/whatwg-url/url"
 )
 
 var (
+	// ErrTypeError is wrapped by all the errors returned when constructing a
+	// URLPattern, which the specification defines as throwing a TypeError.
+	// The underlying cause remains available through errors.Is and errors.As.
+	ErrTypeError = errors.New("type error")
+
 	ErrNoBaseURL             = errors.New("relative URL and no baseURL provided")
+	ErrBaseURLWithInit       = errors.New("baseURL must not be provided with a URLPatternInit input")
 	ErrUnexpectedEmptyString = errors.New("unexpected empty string")
+
+	// ErrInvalidCharacter is returned when a pattern contains an invalid
+	// character, such as an unbalanced "(" or a trailing backslash. The
+	// error tells the character and its index in the pattern of the
+	// component.
+	ErrInvalidCharacter = errors.New("invalid character")
+
+	// ErrDuplicateGroupName is returned by ValidateUniqueGroupNames when a
+	// group name is used by several components.
+	ErrDuplicateGroupName = errors.New("group name used in several components")
 )
 
 // Init-processing mode per https://urlpattern.spec.whatwg.org/#process-a-urlpatterninit.
@@ -43,15 +58,94 @@ type URLPatternResult struct {
 	Pathname URLPatternComponentResult
 	Search   URLPatternComponentResult
 	Hash     URLPatternComponentResult
+
+	// SearchParams holds the results of the search parameters for patterns
+	// compiled with WithSearchParams, keyed by parameter name.
+	SearchParams map[string]URLPatternComponentResult
+}
+
+// componentNames lists the names of the components of a URL pattern, in the
+// order of the specification.
+var componentNames = [...]string{"protocol", "username", "password", "hostname", "port", "pathname", "search", "hash"}
+
+// component returns the result of the component with the given name, or nil
+// if the name is unknown.
+func (r *URLPatternResult) component(name string) *URLPatternComponentResult {
+	switch name {
+	case "protocol":
+		return &r.Protocol
+	case "username":
+		return &r.Username
+	case "password":
+		return &r.Password
+	case "hostname":
+		return &r.Hostname
+	case "port":
+		return &r.Port
+	case "pathname":
+		return &r.Pathname
+	case "search":
+		return &r.Search
+	case "hash":
+		return &r.Hash
+	default:
+		return nil
+	}
 }
 
 type URLPatternComponentResult struct {
 	Input  string
 	Groups map[string]string
+
+	// CaseFoldedInput is Input lowercased, the casing used for matching the
+	// components of patterns ignoring case (see WithIgnoreCase), so that
+	// applications can echo Input while acting on the normalized value. It
+	// is empty for components matched case-sensitively.
+	CaseFoldedInput string
+
+	// TypedGroups holds the groups transformed by the mappers added with
+	// WithResultMapper, keyed by group name. It is nil for patterns without
+	// mappers and for components without groups.
+	TypedGroups map[string]any
+
+	// groupNames holds the names of the groups in pattern order, with an
+	// empty string for the whole match and unnamed subexpressions.
+	groupNames []string
+	// unmatchedGroups holds the names of the optional groups which didn't
+	// participate in the match, see URLPatternComponentResult.Has.
+	unmatchedGroups map[string]bool
+	// repeatSeparators holds the separators between the repetitions of the
+	// groups, see component.repeatSeparators.
+	repeatSeparators map[string]string
+}
+
+// Group is a named group of a component result.
+type Group struct {
+	Name  string
+	Value string
+}
+
+// OrderedGroups returns the groups of the component in the order they are
+// declared in the pattern.
+func (r *URLPatternComponentResult) OrderedGroups() []Group {
+	if len(r.Groups) == 0 {
+		return nil
+	}
+
+	groups := make([]Group, 0, len(r.Groups))
+	for _, name := range r.groupNames {
+		if name != "" {
+			groups = append(groups, Group{Name: name, Value: r.Groups[name]})
+		}
+	}
+
+	return groups
 }
 
 // https://urlpattern.spec.whatwg.org/#url-pattern-struct
 type URLPattern struct {
+	config config
+
 	protocol *component
 	username *component
 	password *component
@@ -106,8 +200,148 @@ func (u *URLPattern) Hash() string {
 type component struct {
 	patternString     string
 	regularExpression *regexp.Regexp
-	groupNameList     []string
-	hasRegexpGroups   bool
+	// groupNames holds the group name of each subexpression of
+	// regularExpression, like regexp.Regexp.SubexpNames: the name at index 0
+	// (the whole match) and of capturing groups nested in custom regexps are
+	// the empty string.
+	groupNames []string
+	// groupIndex maps each group name to its index in groupNames.
+	groupIndex map[string]int
+	// optionalGroups holds the names of the groups with an optional or
+	// zero-or-more modifier, undefined in browsers when they don't match.
+	optionalGroups map[string]bool
+	// repeatSeparators holds the separator between the repetitions of the
+	// groups with a one-or-more or zero-or-more modifier, made of the suffix
+	// and the prefix of their part, for the groups having one.
+	repeatSeparators map[string]string
+	hasRegexpGroups  bool
+	partList         partList
+	// options are the options the component was compiled with.
+	options options
+	// portRanges replaces regularExpression for port patterns using the
+	// syntax enabled by WithPortRanges.
+	portRanges portRanges
+	// shared reports whether the component is shared between patterns, see
+	// wildcardComponent.
+	shared bool
+	// ignored reports whether the component matches any input without
+	// running its regexp, see ignoredComponent.
+	ignored bool
+	// markInput, if set, inserts the markers of the constraints used by the
+	// pattern in the matched inputs, see constraintMarkers.
+	markInput func(input string) string
+}
+
+var (
+	// noGroupsExecResult is the result of exec for matching components
+	// without groups, whose submatches are never read.
+	noGroupsExecResult = []string{}
+	// emptyWildcardExecResult is the result of exec for the empty string and
+	// the shared wildcard components. It must not be modified.
+	emptyWildcardExecResult = []string{"", ""}
+)
+
+// exec matches input against the component, like regexp.Regexp.FindStringSubmatch.
+// The submatches are not returned for components without groups, nor computed
+// for the empty string and wildcard components, which avoids an allocation.
+func (c *component) exec(input string) []string {
+	if c.ignored {
+		return noGroupsExecResult
+	}
+
+	if c.markInput != nil {
+		return c.execMarked(input)
+	}
+
+	if c.portRanges == nil {
+		if c.shared && input == "" {
+			return emptyWildcardExecResult
+		}
+
+		if len(c.groupIndex) == 0 {
+			if c.regularExpression.MatchString(input) {
+				return noGroupsExecResult
+			}
+
+			return nil
+		}
+
+		return c.regularExpression.FindStringSubmatch(input)
+	}
+
+	if c.portRanges.contains(input) {
+		return []string{input}
+	}
+
+	return nil
+}
+
+// execMarked matches input with the markers of the constraints inserted, and
+// removes them from the submatches.
+func (c *component) execMarked(input string) []string {
+	m := c.regularExpression.FindStringSubmatch(c.markInput(input))
+	for i := range m {
+		m[i] = strings.ReplaceAll(m[i], constraintMarker, "")
+	}
+
+	return m
+}
+
+// unmatchedGroups returns the names of the optional groups of c which didn't
+// participate in the match of input, or nil if all of them did. Submatches
+// don't tell apart a group which didn't participate from a group which
+// matched the empty string, so input is matched again, recording the indexes
+// of the submatches, when an optional group is empty in execResult.
+func (c *component) unmatchedGroups(input string, execResult []string) map[string]bool {
+	if c.portRanges != nil {
+		return nil
+	}
+
+	empty := false
+	for name := range c.optionalGroups {
+		if index := c.groupIndex[name]; index < len(execResult) && execResult[index] == "" {
+			empty = true
+
+			break
+		}
+	}
+	if !empty {
+		return nil
+	}
+
+	if c.markInput != nil {
+		input = c.markInput(input)
+	}
+
+	var (
+		unmatched map[string]bool
+		indexes   = c.regularExpression.FindStringSubmatchIndex(input)
+	)
+	for name := range c.optionalGroups {
+		if index := c.groupIndex[name]; 2*index < len(indexes) && indexes[2*index] < 0 {
+			if unmatched == nil {
+				unmatched = make(map[string]bool)
+			}
+			unmatched[name] = true
+		}
+	}
+
+	return unmatched
+}
+
+// isExactlyEmpty reports whether c only matches the empty string.
+func (c *component) isExactlyEmpty() bool {
+	if c.portRanges != nil || c.ignored {
+		return false
+	}
+
+	for _, p := range c.partList {
+		if p.pType != partFixedText || p.value != "" {
+			return false
+		}
+	}
+
+	return true
 }
 
 // https://urlpattern.spec.whatwg.org/#protocol-component-matches-a-special-scheme
@@ -121,31 +355,99 @@ func (c *component) protocolComponentMatchesSpecialScheme() bool {
 	return false
 }
 
+// typeError wraps err in ErrTypeError, unless it is already wrapped.
+func typeError(err error) error {
+	if errors.Is(err, ErrTypeError) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", ErrTypeError, err)
+}
+
 // https://urlpattern.spec.whatwg.org/#url-pattern-create
 func New(input string, baseURL string, options *Options) (*URLPattern, error) {
-	init, err := parseConstructorString(input)
-	if err != nil {
-		return nil, err
+	c := options.config()
+	if baseURL != "" {
+		c.baseURL = &baseURL
+	}
+
+	return newFromString(input, c)
+}
+
+// Input is the input of a URLPattern constructor: a constructor string or a
+// URLPatternInit dictionary.
+//
+// https://urlpattern.spec.whatwg.org/#typedefdef-urlpatterninput
+type Input interface {
+	string | *URLPatternInit
+}
+
+// Compile creates a URLPattern from a constructor string or a URLPatternInit
+// dictionary, configured with functional options:
+//
+//	pattern, err := urlpattern.Compile("/books/:id", urlpattern.WithBaseURL("https://example.com"), urlpattern.WithIgnoreCase())
+//
+// As in the specification, WithBaseURL must not be used with a URLPatternInit
+// input (use URLPatternInit.BaseURL instead): ErrBaseURLWithInit is returned.
+//
+// There is no option to match pathnames by prefix, as the pattern syntax
+// already allows it: "/books{/*}?" matches "/books" and all its subpaths, but
+// not "/bookshelf".
+//
+// https://urlpattern.spec.whatwg.org/#url-pattern-initialize
+func Compile[T Input](input T, opts ...Option) (*URLPattern, error) {
+	c := newConfig(opts)
+	if c.err != nil {
+		return nil, typeError(c.err)
 	}
 
-	if baseURL == "" && init.Protocol == nil {
-		return nil, ErrNoBaseURL
+	if init, ok := any(input).(*URLPatternInit); ok {
+		if c.baseURL != nil {
+			return nil, typeError(ErrBaseURLWithInit)
+		}
+
+		if init == nil {
+			init = &URLPatternInit{}
+		}
+
+		return init.newWithConfig(c)
 	}
 
-	if baseURL != "" {
-		init.BaseURL = &baseURL
+	return newFromString(any(input).(string), c)
+}
+
+// https://urlpattern.spec.whatwg.org/#url-pattern-create
+func newFromString(input string, c *config) (*URLPattern, error) {
+	init, err := parseConstructorString(input, c.parser())
+	if err != nil {
+		return nil, typeError(err)
+	}
+
+	if c.baseURL == nil && init.Protocol == nil {
+		return nil, typeError(ErrNoBaseURL)
 	}
 
-	return init.New(options)
+	init.BaseURL = c.baseURL
+
+	return init.newWithConfig(c)
 }
 
 // https://urlpattern.spec.whatwg.org/#url-pattern-create
 func (init *URLPatternInit) New(opt *Options) (*URLPattern, error) {
-	if opt == nil {
-		opt = &Options{}
+	return init.newWithConfig(opt.config())
+}
+
+func (init *URLPatternInit) newWithConfig(c *config) (*URLPattern, error) {
+	urlPattern, err := init.create(c)
+	if err != nil {
+		return nil, typeError(err)
 	}
 
-	processedInit, err := init.process(initTypePattern, nil, nil, nil, nil, nil, nil, nil, nil)
+	return urlPattern, nil
+}
+
+func (init *URLPatternInit) create(c *config) (*URLPattern, error) {
+	processedInit, err := init.process(initTypePattern, c, nil, nil, nil, nil, nil, nil, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -178,8 +480,9 @@ func (init *URLPatternInit) New(opt *Options) (*URLPattern, error) {
 
 	var emptyString string
 	// Only clear the port when the protocol is a WHATWG special scheme; the
-	// exported DefaultPorts map is user-extendable, so keying off it alone
-	// would quietly apply the behaviour to arbitrary user-added protocols.
+	// default ports registry is user-extendable (see RegisterDefaultPort), so
+	// keying off it alone would quietly apply the behaviour to arbitrary
+	// user-added protocols.
 	//
 	// In "pattern" mode processedInit.Protocol is not canonicalized, so
 	// lowercase it for the comparison: the protocol component is compiled
@@ -187,24 +490,26 @@ func (init *URLPatternInit) New(opt *Options) (*URLPattern, error) {
 	// is the lowercase form.
 	canonicalProtocol := strings.ToLower(*processedInit.Protocol)
 	if _, isSpecial := specialSchemeSet[canonicalProtocol]; isSpecial {
-		if dp, ok := DefaultPorts[canonicalProtocol]; ok && *processedInit.Port == dp {
+		if dp, ok := DefaultPort(canonicalProtocol); ok && *processedInit.Port == dp {
 			processedInit.Port = &emptyString
 		}
 	}
 
 	defaultOptions := options{}
 
-	urlPattern := &URLPattern{}
-	urlPattern.protocol, err = compileComponent(*processedInit.Protocol, canonicalizeProtocol, defaultOptions)
+	p := c.parser()
+
+	urlPattern := &URLPattern{config: *c}
+	urlPattern.protocol, err = c.compileComponent("protocol", *processedInit.Protocol, withParser(p, canonicalizeProtocol), defaultOptions)
 	if err != nil {
 		return nil, err
 	}
-	urlPattern.username, err = compileComponent(*processedInit.Username, canonicalizeUsername, defaultOptions)
+	urlPattern.username, err = c.compileComponent("username", *processedInit.Username, c.encoding(withParser(p, canonicalizeUsername)), defaultOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	urlPattern.password, err = compileComponent(*processedInit.Password, canonicalizePassword, defaultOptions)
+	urlPattern.password, err = c.compileComponent("password", *processedInit.Password, c.encoding(withParser(p, canonicalizePassword)), defaultOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -213,54 +518,80 @@ func (init *URLPatternInit) New(opt *Options) (*URLPattern, error) {
 
 	protocolMatchesSpecialScheme := urlPattern.protocol.protocolComponentMatchesSpecialScheme()
 
+	if c.ipHostnames {
+		hostname := canonicalIPHostnamePattern(*processedInit.Hostname)
+		processedInit.Hostname = &hostname
+	}
+
 	hostnameOptions := options{delimiterCodePoint: '.'}
 	switch {
 	case hostnamePatternIsIPv6Address(*processedInit.Hostname):
-		urlPattern.hostname, err = compileComponent(*processedInit.Hostname, canonicalizeIPv6Hostname, hostnameOptions)
+		urlPattern.hostname, err = c.compileComponent("hostname", *processedInit.Hostname, canonicalizeIPv6Hostname, hostnameOptions)
 	case protocolMatchesSpecialScheme || *processedInit.Protocol == "*":
-		urlPattern.hostname, err = compileComponent(*processedInit.Hostname, canonicalizeDomainName, hostnameOptions)
+		urlPattern.hostname, err = c.compileComponent("hostname", *processedInit.Hostname, withParser(p, c.idnaMode.canonicalizeDomainName), hostnameOptions)
 	default:
-		urlPattern.hostname, err = compileComponent(*processedInit.Hostname, func(s string) (string, error) { return canonicalizeHostname(s, "") }, hostnameOptions)
+		urlPattern.hostname, err = c.compileComponent("hostname", *processedInit.Hostname, func(s string) (string, error) { return c.idnaMode.canonicalizeHostname(p, s, "") }, hostnameOptions)
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	urlPattern.port, err = compileComponent(*processedInit.Port, func(s string) (string, error) { return canonicalizePort(s, "") }, defaultOptions)
+	ranges, isRanges, err := parsePortRanges(*processedInit.Port)
+	switch {
+	case !c.portRanges || !isRanges:
+		urlPattern.port, err = c.compileComponent("port", *processedInit.Port, func(s string) (string, error) { return canonicalizePort(p, s, "") }, defaultOptions)
+	case err == nil:
+		urlPattern.port = &component{patternString: *processedInit.Port, portRanges: ranges}
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	compileOptions := defaultOptions
-	compileOptions.ignoreCase = opt.IgnoreCase
+	compileOptions.ignoreCase = c.ignoreCase
 
-	pathnameOptions := options{'/', '/', false}
+	pathnameOptions := options{delimiterCodePoint: '/', prefixCodePoint: '/'}
 
 	if protocolMatchesSpecialScheme {
 		pathCompileOptions := pathnameOptions
-		pathCompileOptions.ignoreCase = opt.IgnoreCase
+		pathCompileOptions.ignoreCase = c.ignoreCase
 
-		urlPattern.pathname, err = compileComponent(*processedInit.Pathname, canonicalizePathname, pathCompileOptions)
+		urlPattern.pathname, err = c.compileComponent("pathname", *processedInit.Pathname, c.encoding(withParser(p, canonicalizePathname)), pathCompileOptions)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		urlPattern.pathname, err = compileComponent(*processedInit.Pathname, canonicalizeOpaquePathname, compileOptions)
+		urlPattern.pathname, err = c.compileComponent("pathname", *processedInit.Pathname, c.encoding(withParser(p, canonicalizeOpaquePathname)), c.opaquePathnameOptions())
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	urlPattern.search, err = compileComponent(*processedInit.Search, canonicalizeSearch, compileOptions)
-	if err != nil {
+	if c.ignoreSearch {
+		urlPattern.search = ignoredComponent()
+	} else if urlPattern.search, err = c.compileComponent("search", *processedInit.Search, c.encoding(withParser(p, canonicalizeSearch)), compileOptions); err != nil {
 		return nil, err
 	}
 
-	urlPattern.hash, err = compileComponent(*processedInit.Hash, canonicalizeHash, compileOptions)
-	if err != nil {
+	if c.ignoreHash {
+		urlPattern.hash = ignoredComponent()
+	} else if urlPattern.hash, err = c.compileComponent("hash", *processedInit.Hash, c.encoding(withParser(p, canonicalizeHash)), compileOptions); err != nil {
 		return nil, err
 	}
 
+	if c.strictCompat {
+		if err := init.auditCompat(urlPattern, protocolMatchesSpecialScheme, c.compatReport); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := urlPattern.checkGroupValidators(); err != nil {
+		return nil, typeError(err)
+	}
+	if err := urlPattern.checkResultMappers(); err != nil {
+		return nil, typeError(err)
+	}
+
 	return urlPattern, nil
 }
 
@@ -277,7 +608,7 @@ func (u *URLPattern) ExecInit(input *URLPatternInit) *URLPatternResult {
 
 	inputs := []*URLPatternInit{input}
 
-	applyResult, err := input.process(initTypeURL, &protocol, &username, &password, &hostname, &port, &pathname, &search, &hash)
+	applyResult, err := input.process(initTypeURL, &u.config, &protocol, &username, &password, &hostname, &port, &pathname, &search, &hash)
 	if err != nil {
 		return nil
 	}
@@ -301,59 +632,191 @@ func (u *URLPattern) ExecInit(input *URLPatternInit) *URLPatternResult {
 
 // https://urlpattern.spec.whatwg.org/#dom-urlpattern-exec
 func (u *URLPattern) Exec(input, baseURLString string) *URLPatternResult {
-	inputs := []string{input}
-
-	var baseURL *url.Url
-	var err error
+	c, err := u.parseURL(input, baseURLString)
+	if err != nil {
+		return nil
+	}
 
-	if baseURLString != "" {
-		baseURL, err = url.Parse(baseURLString)
-		if err != nil {
-			return nil
+	r := u.match(c.Protocol, c.Username, c.Password, c.Hostname, c.Port, c.Pathname, c.Search, c.Hash)
+	if r != nil {
+		r.Inputs = []string{input}
+		if baseURLString != "" {
+			r.Inputs = append(r.Inputs, baseURLString)
 		}
+	}
 
-		inputs = append(inputs, baseURLString)
+	return r
+}
+
+// ExecPath matches only the pathname, search and hash components of the
+// pattern against pathAndQuery, a path optionally followed by a query string
+// and a fragment, such as the value returned by net/url.URL.RequestURI, for
+// server-side code which doesn't know the absolute URL of the request:
+//
+//	result := pattern.ExecPath(r.URL.RequestURI())
+//
+// pathAndQuery must start with "/", and is canonicalized as the path of an
+// http or https URL. The other components of the result are empty. It
+// returns nil if pathAndQuery doesn't match.
+func (u *URLPattern) ExecPath(pathAndQuery string) *URLPatternResult {
+	if !strings.HasPrefix(pathAndQuery, "/") {
+		return nil
 	}
 
-	ur, err := urlParser.BasicParser(input, baseURL, nil, url.NoState)
+	// Prepending the origin rather than resolving against it keeps paths
+	// starting with "//" from being parsed as scheme-relative URLs.
+	c, err := u.parseURL("https://example.invalid"+pathAndQuery, "")
 	if err != nil {
 		return nil
 	}
 
-	r := u.match(
-		ur.Scheme(), ur.Username(), ur.Password(), ur.Hostname(),
-		ur.Port(), ur.Pathname(), ur.Query(), ur.Fragment(),
+	pathname, search, hash := c.Pathname, c.Search, c.Hash
+	if u.config.normalizes() {
+		pathname = u.config.normalize(pathname)
+		search = u.config.normalize(search)
+		hash = u.config.normalize(hash)
+	}
+
+	pathnameExecResult := u.exec("pathname", u.pathname, pathname)
+	if pathnameExecResult == nil {
+		return nil
+	}
+	searchExecResult := u.exec("search", u.search, search)
+	if searchExecResult == nil {
+		return nil
+	}
+	hashExecResult := u.exec("hash", u.hash, hash)
+	if hashExecResult == nil {
+		return nil
+	}
+
+	var searchParams map[string]URLPatternComponentResult
+	if u.config.searchParams != nil {
+		if searchParams = u.config.searchParams.Exec(search); searchParams == nil {
+			return nil
+		}
+	}
+
+	result := &URLPatternResult{Inputs: []string{pathAndQuery}, SearchParams: searchParams}
+	result.Pathname = createComponentMatchResult(*u.pathname, pathname, pathnameExecResult)
+	result.Search = createComponentMatchResult(*u.search, search, searchExecResult)
+	result.Hash = createComponentMatchResult(*u.hash, hash, hashExecResult)
+
+	if !u.validGroups(result) || !u.mapGroups(result) {
+		return nil
+	}
+
+	return result
+}
+
+// ExecAny is Exec trying the base URLs in order, e.g. for a site served under
+// several aliases or locales. It returns the first match and the index of the
+// base URL it applies to, or nil and -1 if none matches.
+func (u *URLPattern) ExecAny(input string, baseURLs []string) (*URLPatternResult, int) {
+	for i, baseURL := range baseURLs {
+		if r := u.Exec(input, baseURL); r != nil {
+			return r, i
+		}
+	}
+
+	return nil, -1
+}
+
+// parseURL parses input, relative to baseURLString if not empty, as Exec does.
+func (u *URLPattern) parseURL(input, baseURLString string) (URLComponents, error) {
+	if err := validateUTF8(input); err != nil {
+		return URLComponents{}, err
+	}
+	if err := validateUTF8(baseURLString); err != nil {
+		return URLComponents{}, err
+	}
+
+	var (
+		c   URLComponents
+		err error
 	)
-	if r != nil {
-		r.Inputs = inputs
+	if c, err = u.config.parser().Parse(input, baseURLString); err != nil {
+		return URLComponents{}, err
 	}
 
-	return r
+	c.Hostname = u.config.idnaMode.hostname(c.Hostname)
+
+	return c, nil
+}
+
+// CanonicalURL holds the components of a URL that the caller guarantees are
+// canonicalized as by the WHATWG URL standard, see ExecCanonical.
+type CanonicalURL URLComponents
+
+// ExecCanonical is like ExecInit, but for trusted inputs already
+// canonicalized, e.g. by a prior parse with the WHATWG URL parser in a proxy
+// or a log pipeline: the processing and canonicalization of the components
+// are skipped, which saves latency when matching the same URL against many
+// patterns. The Inputs and InitInputs of the result are empty.
+func (u *URLPattern) ExecCanonical(input CanonicalURL) *URLPatternResult {
+	return u.match(input.Protocol, input.Username, input.Password, input.Hostname, input.Port, input.Pathname, input.Search, input.Hash)
 }
 
 // https://urlpattern.spec.whatwg.org/#url-pattern-match
 func (u *URLPattern) match(protocol, username, password, hostname, port, pathname, search, hash string) *URLPatternResult {
-	protocolExecResult := u.protocol.regularExpression.FindStringSubmatch(protocol)
-	usernameExecResult := u.username.regularExpression.FindStringSubmatch(username)
-	passwordExecResult := u.password.regularExpression.FindStringSubmatch(password)
-	hostnameExecResult := u.hostname.regularExpression.FindStringSubmatch(hostname)
-	portExecResult := u.port.regularExpression.FindStringSubmatch(port)
-	pathnameExecResult := u.pathname.regularExpression.FindStringSubmatch(pathname)
-	searchExecResult := u.search.regularExpression.FindStringSubmatch(search)
-	hashExecResult := u.hash.regularExpression.FindStringSubmatch(hash)
-
-	if protocolExecResult == nil ||
-		usernameExecResult == nil ||
-		passwordExecResult == nil ||
-		hostnameExecResult == nil ||
-		portExecResult == nil ||
-		pathnameExecResult == nil ||
-		searchExecResult == nil ||
-		hashExecResult == nil {
+	return u.matchAndReport(protocol, username, password, hostname, port, pathname, search, hash, nil)
+}
+
+// matchAndReport is match, also recording each step of the match in report
+// if it isn't nil, so that Explain can't diverge from the matching methods.
+func (u *URLPattern) matchAndReport(protocol, username, password, hostname, port, pathname, search, hash string, report *MatchReport) *URLPatternResult {
+	if u.config.normalizes() {
+		username = u.config.normalize(username)
+		password = u.config.normalize(password)
+		pathname = u.config.normalize(pathname)
+		search = u.config.normalize(search)
+		hash = u.config.normalize(hash)
+	}
+	if u.config.ipHostnames {
+		hostname = canonicalIPHostname(hostname)
+	}
+
+	portInput := u.portInput(port, protocol)
+
+	protocolExecResult := u.exec("protocol", u.protocol, protocol)
+	usernameExecResult := u.exec("username", u.username, username)
+	passwordExecResult := u.exec("password", u.password, password)
+	hostnameExecResult := u.exec("hostname", u.hostname, hostname)
+	portExecResult := u.exec("port", u.port, portInput)
+	pathnameExecResult := u.exec("pathname", u.pathname, pathname)
+	searchExecResult := u.exec("search", u.search, search)
+	hashExecResult := u.exec("hash", u.hash, hash)
+
+	componentsMatched := protocolExecResult != nil &&
+		usernameExecResult != nil &&
+		passwordExecResult != nil &&
+		hostnameExecResult != nil &&
+		portExecResult != nil &&
+		pathnameExecResult != nil &&
+		searchExecResult != nil &&
+		hashExecResult != nil
+
+	var searchParams map[string]URLPatternComponentResult
+	if u.config.searchParams != nil && (componentsMatched || report != nil) {
+		searchParams = u.config.searchParams.Exec(search)
+	}
+
+	if report != nil {
+		report.addComponents(u,
+			[...]string{protocol, username, password, hostname, portInput, pathname, search, hash},
+			[...]bool{
+				protocolExecResult != nil, usernameExecResult != nil, passwordExecResult != nil, hostnameExecResult != nil,
+				portExecResult != nil, pathnameExecResult != nil, searchExecResult != nil, hashExecResult != nil,
+			},
+		)
+		report.SearchParamsMatched = u.config.searchParams == nil || searchParams != nil
+	}
+
+	if !componentsMatched || (u.config.searchParams != nil && searchParams == nil) {
 		return nil
 	}
 
-	result := &URLPatternResult{}
+	result := &URLPatternResult{SearchParams: searchParams}
 	result.Protocol = createComponentMatchResult(*u.protocol, protocol, protocolExecResult)
 	result.Username = createComponentMatchResult(*u.username, username, usernameExecResult)
 	result.Password = createComponentMatchResult(*u.password, password, passwordExecResult)
@@ -363,9 +826,28 @@ func (u *URLPattern) match(protocol, username, password, hostname, port, pathnam
 	result.Search = createComponentMatchResult(*u.search, search, searchExecResult)
 	result.Hash = createComponentMatchResult(*u.hash, hash, hashExecResult)
 
+	valid := u.validGroups(result) && u.mapGroups(result)
+	if report != nil {
+		report.GroupsValid = valid
+	}
+	if !valid {
+		return nil
+	}
+
 	return result
 }
 
+// portInput returns the value matched against the port component for the port
+// of a URL: the default port of the protocol if the port is empty and the
+// component uses port ranges.
+func (u *URLPattern) portInput(port, protocol string) string {
+	if port == "" && u.port.portRanges != nil {
+		port, _ = DefaultPort(protocol)
+	}
+
+	return port
+}
+
 // https://urlpattern.spec.whatwg.org/#dom-urlpattern-test
 func (u *URLPattern) Test(input, baseURL string) bool {
 	return u.Exec(input, baseURL) != nil
@@ -388,33 +870,128 @@ func (u *URLPattern) HasRegexpGroups() bool {
 		u.hash.hasRegexpGroups
 }
 
+// ComponentHasRegexpGroups reports whether the component with the given name
+// (e.g. "pathname") uses regexp groups. It returns false for unknown names.
+//
+// This is the per-component counterpart of HasRegexpGroups, for callers such
+// as the Service Worker static routing API which only reject patterns whose
+// relevant components use regexp groups.
+func (u *URLPattern) ComponentHasRegexpGroups(name string) bool {
+	c := u.component(name)
+
+	return c != nil && c.hasRegexpGroups
+}
+
+// IsExactlyEmpty reports whether the component with the given name (e.g.
+// "port") only matches the empty string, as the port of
+// "https://example.com/*", unlike wildcard components which match any value.
+// It returns false for unknown names.
+//
+// Exactly empty components are those whose pattern string is empty, or only
+// contains empty fixed text (e.g. "{}"), which lets tools reconstruct the
+// properties exposed by browsers without parsing pattern strings.
+func (u *URLPattern) IsExactlyEmpty(name string) bool {
+	c := u.component(name)
+
+	return c != nil && c.isExactlyEmpty()
+}
+
+// ValidateUniqueGroupNames returns an error wrapping ErrDuplicateGroupName if
+// the same group name is declared in more than one component, for instance
+// in both the pathname and the search. Such patterns produce ambiguous
+// bindings when the groups of all components are flattened into a single
+// map, as many routers do.
+//
+// Groups automatically named with an index (unnamed regexp groups and
+// wildcards) are not checked.
+func (u *URLPattern) ValidateUniqueGroupNames() error {
+	seen := make(map[string]string)
+
+	for _, componentName := range componentNames {
+		for _, name := range u.component(componentName).groupNames {
+			if name == "" || (name[0] >= '0' && name[0] <= '9') {
+				continue
+			}
+
+			if previous, ok := seen[name]; ok {
+				return fmt.Errorf("%w: %q is used in %s and %s", ErrDuplicateGroupName, name, previous, componentName)
+			}
+
+			seen[name] = componentName
+		}
+	}
+
+	return nil
+}
+
+// component returns the component with the given name, or nil if the name
+// is unknown.
+func (u *URLPattern) component(name string) *component {
+	switch name {
+	case "protocol":
+		return u.protocol
+	case "username":
+		return u.username
+	case "password":
+		return u.password
+	case "hostname":
+		return u.hostname
+	case "port":
+		return u.port
+	case "pathname":
+		return u.pathname
+	case "search":
+		return u.search
+	case "hash":
+		return u.hash
+	default:
+		return nil
+	}
+}
+
 // https://urlpattern.spec.whatwg.org/#create-a-component-match-result
+// createComponentMatchResult doesn't allocate the groups map of components
+// without groups, and allocates it with the exact number of groups otherwise.
 func createComponentMatchResult(component component, input string, execResult []string) URLPatternComponentResult {
-	result := URLPatternComponentResult{Input: input}
-
-	if len(component.groupNameList) == 0 || (len(execResult) == 2 && execResult[0] == "" && execResult[1] == "") {
-		return result
+	result := URLPatternComponentResult{
+		Input:            input,
+		groupNames:       component.groupNames,
+		unmatchedGroups:  component.unmatchedGroups(input, execResult),
+		repeatSeparators: component.repeatSeparators,
+	}
+	if component.options.ignoreCase {
+		result.CaseFoldedInput = strings.ToLower(input)
 	}
 
-	result.Groups = make(map[string]string, len(component.groupNameList))
-	limit := len(execResult)
-	if namedLimit := len(component.groupNameList) + 1; namedLimit < limit {
-		limit = namedLimit
+	if len(component.groupIndex) == 0 || (len(execResult) == 2 && execResult[0] == "" && execResult[1] == "") {
+		return result
 	}
-	for index := 1; index < limit; index++ {
-		name := component.groupNameList[index-1]
-		value := execResult[index]
 
-		result.Groups[name] = value
+	result.Groups = make(map[string]string, len(component.groupIndex))
+	for index, name := range component.groupNames {
+		if name != "" {
+			result.Groups[name] = execResult[index]
+		}
 	}
 
 	return result
 }
 
+// Options configures a URLPattern.
+//
+// New settings are only added as Option functions, see Compile.
 type Options struct {
 	IgnoreCase bool
 }
 
+func (o *Options) config() *config {
+	if o == nil {
+		return &config{}
+	}
+
+	return &config{ignoreCase: o.IgnoreCase}
+}
+
 // https://urlpattern.spec.whatwg.org/#dictdef-urlpatterninit
 type URLPatternInit struct {
 	Protocol *string
@@ -430,66 +1007,70 @@ type URLPatternInit struct {
 }
 
 // https://urlpattern.spec.whatwg.org/#process-a-urlpatterninit
-func (init *URLPatternInit) process(iType string, protocol, username, password, hostname, port, pathname, search, hash *string) (*URLPatternInit, error) {
+func (init *URLPatternInit) process(iType string, c *config, protocol, username, password, hostname, port, pathname, search, hash *string) (*URLPatternInit, error) {
+	if err := init.validateUTF8(); err != nil {
+		return nil, err
+	}
+
 	result := &URLPatternInit{protocol, username, password, hostname, port, pathname, search, hash, nil}
 
-	var (
-		baseURL *url.Url
-		err     error
-	)
+	p := c.parser()
+
+	var baseURL *URLComponents
 	if init.BaseURL != nil {
-		baseURL, err = url.Parse(*init.BaseURL)
+		u, err := p.Parse(*init.BaseURL, "")
 		if err != nil {
 			return nil, err
 		}
+		baseURL = &u
 
 		if init.Protocol == nil {
-			p := processBaseURLString(baseURL.Scheme(), iType)
+			p := processBaseURLString(baseURL.Protocol, iType)
 			result.Protocol = &p
 		}
 
 		// TODO: the end of this block can be simplified, but let's be as close as possible from the standard algorithm for now
 
 		if iType != initTypePattern && init.Protocol == nil && init.Hostname == nil && init.Port == nil && init.Username == nil {
-			u := processBaseURLString(baseURL.Username(), iType)
+			u := processBaseURLString(baseURL.Username, iType)
 			result.Username = &u
 		}
 
 		if iType != initTypePattern && init.Protocol == nil && init.Hostname == nil && init.Port == nil && init.Username == nil && init.Password == nil {
-			password := baseURL.Password()
+			password := baseURL.Password
 			p := processBaseURLString(password, iType)
 			result.Password = &p
 		}
 
 		if init.Protocol == nil && init.Hostname == nil {
-			baseHost := baseURL.Hostname()
+			baseHost := c.idnaMode.hostname(baseURL.Hostname)
 			h := processBaseURLString(baseHost, iType)
 			result.Hostname = &h
 		}
 
 		if init.Protocol == nil && init.Hostname == nil && init.Port == nil {
-			p := baseURL.Port()
+			p := baseURL.Port
 			result.Port = &p
 		}
 
 		if init.Protocol == nil && init.Hostname == nil && init.Port == nil && init.Pathname == nil {
-			p := processBaseURLString(baseURL.Pathname(), iType)
+			p := processBaseURLString(baseURL.Pathname, iType)
 			result.Pathname = &p
 		}
 
 		if init.Protocol == nil && init.Hostname == nil && init.Port == nil && init.Pathname == nil && init.Search == nil {
-			s := processBaseURLString(baseURL.Query(), iType)
+			s := processBaseURLString(baseURL.Search, iType)
 			result.Search = &s
 		}
 
 		if init.Protocol == nil && init.Hostname == nil && init.Port == nil && init.Pathname == nil && init.Search == nil && init.Hash == nil {
-			h := processBaseURLString(baseURL.Fragment(), iType)
+			h := processBaseURLString(baseURL.Hash, iType)
 			result.Hash = &h
 		}
 	}
 
 	if init.Protocol != nil {
-		p, err := processProtocolForInit(*init.Protocol, iType)
+		p, err := processProtocolForInit(p, *init.Protocol, iType)
 		if err != nil {
 			return nil, err
 		}
@@ -498,7 +1079,7 @@ func (init *URLPatternInit) process(iType string, protocol, username, password,
 	}
 
 	if init.Username != nil {
-		u, err := processUsernameForInit(*init.Username, iType)
+		u, err := processUsernameForInit(p, *init.Username, iType)
 		if err != nil {
 			return nil, err
 		}
@@ -507,7 +1088,7 @@ func (init *URLPatternInit) process(iType string, protocol, username, password,
 	}
 
 	if init.Password != nil {
-		p, err := processPasswordForInit(*init.Password, iType)
+		p, err := processPasswordForInit(p, *init.Password, iType)
 		if err != nil {
 			return nil, err
 		}
@@ -523,7 +1104,7 @@ func (init *URLPatternInit) process(iType string, protocol, username, password,
 	}
 
 	if init.Hostname != nil {
-		h, err := processHostnameForInit(*init.Hostname, proto, iType)
+		h, err := processHostnameForInit(p, *init.Hostname, proto, iType, c.idnaMode)
 		if err != nil {
 			return nil, err
 		}
@@ -532,7 +1113,7 @@ func (init *URLPatternInit) process(iType string, protocol, username, password,
 	}
 
 	if init.Port != nil {
-		p, err := processPortForInit(*init.Port, proto, iType)
+		p, err := processPortForInit(p, *init.Port, proto, iType)
 		if err != nil {
 			return nil, err
 		}
@@ -544,8 +1125,9 @@ func (init *URLPatternInit) process(iType string, protocol, username, password,
 		result.Pathname = init.Pathname
 
 		// TODO: according to the spec, we should check that he path is opaque, but it's illogical and breaks the tests
-		if baseURL != nil && !baseURL.OpaquePath() && !isAbsolutePathname(*result.Pathname, iType) {
-			baseURLPath := processBaseURLString(baseURL.Pathname(), iType)
+		// Only opaque paths don't start with "/", empty paths have no "/" to join.
+		if baseURL != nil && strings.HasPrefix(baseURL.Pathname, "/") && !isAbsolutePathname(*result.Pathname, iType) {
+			baseURLPath := processBaseURLString(baseURL.Pathname, iType)
 
 			slashIndex := strings.LastIndex(baseURLPath, "/")
 			if slashIndex != -1 {
@@ -554,7 +1136,7 @@ func (init *URLPatternInit) process(iType string, protocol, username, password,
 			}
 		}
 
-		p, err := processPathnameForInit(*result.Pathname, proto, iType)
+		p, err := processPathnameForInit(p, *result.Pathname, proto, iType)
 		if err != nil {
 			return nil, err
 		}
@@ -563,7 +1145,7 @@ func (init *URLPatternInit) process(iType string, protocol, username, password,
 	}
 
 	if init.Search != nil {
-		s, err := processSearchForInit(*init.Search, iType)
+		s, err := processSearchForInit(p, *init.Search, iType)
 		if err != nil {
 			return nil, err
 		}
@@ -572,7 +1154,7 @@ func (init *URLPatternInit) process(iType string, protocol, username, password,
 	}
 
 	if init.Hash != nil {
-		h, err := processHashForInit(*init.Hash, iType)
+		h, err := processHashForInit(p, *init.Hash, iType)
 		if err != nil {
 			return nil, err
 		}
@@ -589,101 +1171,101 @@ func processBaseURLString(input, uType string) string {
 		return input
 	}
 
-	return escapePatternString(input)
+	return EscapePatternString(input)
 }
 
 // https://urlpattern.spec.whatwg.org/#process-protocol-for-init
-func processProtocolForInit(value, pType string) (string, error) {
+func processProtocolForInit(p URLParser, value, pType string) (string, error) {
 	strippedValue := strings.TrimSuffix(value, ":")
 
 	if pType == initTypePattern {
 		return strippedValue, nil
 	}
 
-	return canonicalizeProtocol(strippedValue)
+	return canonicalizeProtocol(p, strippedValue)
 }
 
 // https://urlpattern.spec.whatwg.org/#process-username-for-init
-func processUsernameForInit(value, uType string) (string, error) {
+func processUsernameForInit(p URLParser, value, uType string) (string, error) {
 	if uType == initTypePattern {
 		return value, nil
 	}
 
-	return canonicalizeUsername(value)
+	return canonicalizeUsername(p, value)
 }
 
 // https://urlpattern.spec.whatwg.org/#process-password-for-init
-func processPasswordForInit(value, uType string) (string, error) {
+func processPasswordForInit(p URLParser, value, uType string) (string, error) {
 	if uType == initTypePattern {
 		return value, nil
 	}
 
-	return canonicalizePassword(value)
+	return canonicalizePassword(p, value)
 }
 
 // https://urlpattern.spec.whatwg.org/#process-hostname-for-init
-func processHostnameForInit(value, protocolValue, uType string) (string, error) {
+func processHostnameForInit(p URLParser, value, protocolValue, uType string, idnaMode IDNAMode) (string, error) {
 	if uType == initTypePattern {
 		return value, nil
 	}
 
 	if protocolValue == "" {
-		return canonicalizeDomainName(value)
+		return idnaMode.canonicalizeDomainName(p, value)
 	}
 
 	if _, ok := specialSchemeSet[protocolValue]; ok {
-		return canonicalizeDomainName(value)
+		return idnaMode.canonicalizeDomainName(p, value)
 	}
 
-	return canonicalizeHostname(value, protocolValue)
+	return idnaMode.canonicalizeHostname(p, value, protocolValue)
 }
 
 // https://urlpattern.spec.whatwg.org/#process-port-for-init
-func processPortForInit(portValue, protocolValue, pType string) (string, error) {
+func processPortForInit(p URLParser, portValue, protocolValue, pType string) (string, error) {
 	if pType == initTypePattern {
 		return portValue, nil
 	}
 
-	return canonicalizePort(portValue, protocolValue)
+	return canonicalizePort(p, portValue, protocolValue)
 }
 
 // https://urlpattern.spec.whatwg.org/#process-pathname-for-init
-func processPathnameForInit(pathnameValue, protocolValue, ptype string) (string, error) {
+func processPathnameForInit(p URLParser, pathnameValue, protocolValue, ptype string) (string, error) {
 	if ptype == initTypePattern {
 		return pathnameValue, nil
 	}
 
 	if protocolValue == "" {
-		return canonicalizePathname(pathnameValue)
+		return canonicalizePathname(p, pathnameValue)
 	}
 
 	if _, ok := specialSchemeSet[protocolValue]; ok {
-		return canonicalizePathname(pathnameValue)
+		return canonicalizePathname(p, pathnameValue)
 	}
 
-	return canonicalizeOpaquePathname(pathnameValue)
+	return canonicalizeOpaquePathname(p, pathnameValue)
 }
 
 // https://urlpattern.spec.whatwg.org/#process-search-for-init
-func processSearchForInit(value, sType string) (string, error) {
+func processSearchForInit(p URLParser, value, sType string) (string, error) {
 	strippedValue := strings.TrimPrefix(value, "?")
 
 	if sType == initTypePattern {
 		return strippedValue, nil
 	}
 
-	return canonicalizeSearch(strippedValue)
+	return canonicalizeSearch(p, strippedValue)
 }
 
 // https://urlpattern.spec.whatwg.org/#process-hash-for-init
-func processHashForInit(value, hType string) (string, error) {
+func processHashForInit(p URLParser, value, hType string) (string, error) {
 	strippedValue := strings.TrimPrefix(value, "#")
 
 	if hType == initTypePattern {
 		return strippedValue, nil
 	}
 
-	return canonicalizeHash(strippedValue)
+	return canonicalizeHash(p, strippedValue)
 }
 
 // https://urlpattern.spec.whatwg.org/#is-an-absolute-pathname
@@ -709,15 +1291,14 @@ func hostnamePatternIsIPv6Address(input string) bool {
 		return false
 	}
 
-	i := []rune(input)
-
-	if i[0] == '[' {
+	// All the compared code points are ASCII, so comparing bytes is enough.
+	if input[0] == '[' {
 		return true
 	}
-	if i[0] == '{' && i[1] == '[' {
+	if input[0] == '{' && input[1] == '[' {
 		return true
 	}
-	if i[0] == '\\' && i[1] == '[' {
+	if input[0] == '\\' && input[1] == '[' {
 		return true
 	}
 
@@ -1,446 +1,411 @@
 package urlpattern_test
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"reflect"
-	"slices"
-	"strconv"
-	"strings"
+	"regexp"
 	"testing"
 
 	"github.com/dunglas/go-urlpattern"
-	"github.com/nlnwa/whatwg-url/url"
+	"github.com/dunglas/go-urlpattern/urlpatterntest"
 )
 
-// Port of https://github.com/web-platform-tests/wpt/blob/d3e55612911b00cb53271476de610e75a8603ae7/urlpattern/resources/urlpatterntests.js
+func TestURLPattern(t *testing.T) {
+	urlpatterntest.Run(t, urlpatterntest.Entries(), urlpatterntest.Config{})
+}
 
-//go:generate curl https://raw.githubusercontent.com/web-platform-tests/wpt/master/urlpattern/resources/urlpatterntestdata.json -o testdata/urlpatterntestdata.json
+func Example() {
+	pattern, err := urlpattern.New("/books/:id", "https://example.com", nil)
+	if err != nil {
+		panic(err)
+	}
 
-var (
-	errInvalidPatternParam = errors.New("invalid constructor parameter")
-	errBaseURLWithInit     = errors.New("invalid second argument: baseURL provided with a URLPatternInit input; use URLPatternInit.BaseURL instead")
-)
+	fmt.Printf("%t\n", pattern.Test("https://example.com/books/123", ""))
+	fmt.Printf("%t\n", pattern.Test("https://example.com/authors/123", ""))
 
-type Entry struct {
-	Pattern                []any `json:"pattern"`
-	Inputs                 []any `json:"inputs"`
-	ExactlyEmptyComponents []string      `json:"exactly_empty_components"`
-	ExpectedObj            any   `json:"expected_obj"`
-	ExpectedMatch          any   `json:"expected_match"`
+	fmt.Printf("%v", pattern.Exec("123", "https://example.com/books/").Pathname.Groups)
+
+	// Output: true
+	// false
+	// map[id:123]
 }
 
-func TestURLPattern(t *testing.T) {
-	content, err := os.ReadFile("testdata/urlpatterntestdata.json")
+func TestOrderedGroups(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://example.com/:zebra/(\\d+)/:apple")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	var data []Entry
-	if err := json.Unmarshal(content, &data); err != nil {
+	r := pattern.Exec("https://example.com/z/42/a", "")
+	if r == nil {
+		t.Fatal("pattern must match")
+	}
+
+	want := []urlpattern.Group{{Name: "zebra", Value: "z"}, {Name: "0", Value: "42"}, {Name: "apple", Value: "a"}}
+	if got := r.Pathname.OrderedGroups(); !reflect.DeepEqual(want, got) {
+		t.Errorf("want %#v; got %#v", want, got)
+	}
+
+	if got := r.Hostname.OrderedGroups(); got != nil {
+		t.Errorf("want nil; got %#v", got)
+	}
+}
+
+func TestNestedCapturingGroups(t *testing.T) {
+	pattern, err := urlpattern.New("/(a(?<x>b)):id", "https://example.com", nil)
+	if err != nil {
 		t.Fatal(err)
 	}
 
-	for i, entry := range data {
-		t.Run(strconv.Itoa(i), func(t *testing.T) {
-			pattern, err := newPattern(t, &entry)
+	r := pattern.Exec("https://example.com/ab42", "")
+	if r == nil {
+		t.Fatal("expected a match")
+	}
 
-			if e, _ := entry.ExpectedObj.(string); e == "error" {
-				if err == nil {
-					t.Logf("want error for %#v", entry.Pattern)
-					t.FailNow()
-				}
+	if want := map[string]string{"0": "ab", "id": "42"}; !reflect.DeepEqual(want, r.Pathname.Groups) {
+		t.Errorf("want %#v; got %#v", want, r.Pathname.Groups)
+	}
+}
 
-				return
-			}
+func TestNamedCapturingGroups(t *testing.T) {
+	pattern, err := urlpattern.New("/:x(a(?<x>b))/:café/:id", "https://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-			if err != nil {
-				t.Logf("unexpected error: %s (%#v)", err, entry)
-				t.FailNow()
-			}
+	r := pattern.Exec("https://example.com/ab/c/42", "")
+	if r == nil {
+		t.Fatal("expected a match")
+	}
 
-			assertExpectedObject(t, entry, pattern)
-
-			if e, _ := entry.ExpectedMatch.(string); e == "error" {
-				_, err := callTest(pattern, entry)
-				if err == nil {
-					t.Logf("want error when running Test for %#v", entry)
-					t.FailNow()
-				}
-				_, err = callExec(pattern, entry)
-				if err == nil {
-					t.Logf("want error when running Test for %#v", entry)
-					t.FailNow()
-				}
-
-				return
-			}
+	if want := map[string]string{"x": "ab", "café": "c", "id": "42"}; !reflect.DeepEqual(want, r.Pathname.Groups) {
+		t.Errorf("want %#v; got %#v", want, r.Pathname.Groups)
+	}
 
-			testResult, err := callTest(pattern, entry)
-			if err != nil {
-				if len(entry.Inputs) == 1 {
-					if i, ok := entry.Inputs[0].(map[string]any); ok {
-						if p, _ := i["protocol"].(string); p == "café" {
-							t.Skip("TODO: check why this fails, probably a bug in the test suite")
-						}
-					}
-				}
-
-				t.Logf("unexpected error when running Test: %s (%#v)", err, entry)
-				t.FailNow()
-			}
+	// Names clashing with groups of custom regexps or that aren't valid Go
+	// group names are generated as unnamed groups.
+	re := regexp.MustCompile(pattern.Explain("https://example.com/ab/c/42", "").Components[5].Regexp)
+	if want := []string{"", "", "x", "", "id"}; !reflect.DeepEqual(want, re.SubexpNames()) {
+		t.Errorf("want %#v; got %#v", want, re.SubexpNames())
+	}
+}
 
-			expectedTestResult := entry.ExpectedMatch != nil
-
-			if testResult != expectedTestResult {
-				if len(entry.Pattern) > 0 {
-					e, _ := entry.Pattern[0].(map[string]any)
-					if pa := e["pathname"]; pa != nil {
-						p := pa.(string)
-						if strings.Contains(p, "[") && (strings.Contains(p, "--") || strings.Contains(p, "&&")) {
-							t.Skip("Advanced unicode features aren't supported by Go")
-						}
-					}
-				}
-
-				t.Logf("Test must return %v; got %v (%#v)", expectedTestResult, testResult, entry)
-				t.FailNow()
+func TestUnsupportedRegexpFeature(t *testing.T) {
+	for _, tc := range []struct{ pattern, component, feature string }{
+		{`/([[a-z]--a])`, "pathname", "set subtraction"},
+		{`/([\d&&[0-1]])`, "pathname", "set intersection"},
+		{`/([[a-z][0-9]])`, "pathname", "nested character class"},
+		{`/#([\q{abc}])`, "hash", `string literal (\q{...})`},
+	} {
+		t.Run(tc.pattern, func(t *testing.T) {
+			_, err := urlpattern.New(tc.pattern, "https://example.com", nil)
+
+			var unsupportedErr *urlpattern.UnsupportedRegexpFeatureError
+			if !errors.As(err, &unsupportedErr) {
+				t.Fatalf("want UnsupportedRegexpFeatureError; got %v", err)
 			}
 
-			execResult, err := callExec(pattern, entry)
-			if err != nil {
-				t.Logf("unexpected error when running Test: %s (%#v)", err, entry)
-				t.FailNow()
+			if unsupportedErr.Component != tc.component || unsupportedErr.Feature != tc.feature {
+				t.Errorf("want %s in %s; got %s in %s", tc.feature, tc.component, unsupportedErr.Feature, unsupportedErr.Component)
 			}
+		})
+	}
 
-			if entry.ExpectedMatch == nil {
-				if execResult != nil {
-					t.Logf("Match must return nil, go %#v (%#v)", execResult, entry)
-					t.Fail()
-				}
-
-				return
-			}
+	if _, err := urlpattern.New(`/([a-z\-]+)/([a\[b]+)`, "https://example.com", nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
 
-			expectedObj := entry.ExpectedMatch.(map[string]any)
-			if _, ok := expectedObj["inputs"]; !ok {
-				expectedObj["inputs"] = entry.Inputs
+func TestConstructorErrorsWrapTypeError(t *testing.T) {
+	for _, tc := range []struct {
+		name, pattern, baseURL string
+		cause                  error
+	}{
+		{"no base URL", "/foo", "", urlpattern.ErrNoBaseURL},
+		{"duplicate name", "https://example.com/:id/:id", "", urlpattern.ErrDuplicatePartName},
+		{"tokenizer", "https://example.com/(foo", "", nil},
+		{"canonicalizer", "https://exa mple.com", "", nil},
+		{"regexp", "https://example.com/([[a-z]--a])", "", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := urlpattern.New(tc.pattern, tc.baseURL, nil)
+			if !errors.Is(err, urlpattern.ErrTypeError) {
+				t.Fatalf("want ErrTypeError; got %v", err)
 			}
 
-			if er := newExpectedResult(entry); !reflect.DeepEqual(er, execResult) {
-				t.Logf("want %#v; got %#v (%#v)", er, execResult, entry)
-				t.Fail()
+			if tc.cause != nil && !errors.Is(err, tc.cause) {
+				t.Errorf("want %v; got %v", tc.cause, err)
 			}
 		})
 	}
 }
 
-func newPattern(t *testing.T, entry *Entry) (*urlpattern.URLPattern, error) {
-	t.Helper()
-
-	var baseURL string
-	options := &urlpattern.Options{}
-
-	switch len(entry.Pattern) {
-	case 0:
-		i := &urlpattern.URLPatternInit{}
-		return i.New(options)
-
-	case 2:
-		switch v := entry.Pattern[1].(type) {
-		case map[string]any:
-			options.IgnoreCase = true
-
-		case string:
-			baseURL = v
-
-		default:
-			return nil, errInvalidPatternParam
-		}
+func TestComponentHasRegexpGroups(t *testing.T) {
+	pattern, err := urlpattern.New("https://:sub.example.com/items/(\\d+)", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	case 3:
-		options.IgnoreCase = true
+	if !pattern.HasRegexpGroups() {
+		t.Error("HasRegexpGroups must return true")
+	}
 
-		bu, ok := entry.Pattern[1].(string)
-		if !ok {
-			return nil, errInvalidPatternParam
+	for name, want := range map[string]bool{
+		"protocol": false,
+		"hostname": false,
+		"pathname": true,
+		"search":   false,
+		"unknown":  false,
+	} {
+		if got := pattern.ComponentHasRegexpGroups(name); got != want {
+			t.Errorf("%s: want %t; got %t", name, want, got)
 		}
-
-		baseURL = bu
 	}
+}
 
-	switch v := entry.Pattern[0].(type) {
-	case string:
-		return urlpattern.New(v, baseURL, options)
+func TestIsExactlyEmpty(t *testing.T) {
+	pattern, err := urlpattern.New("https://example.com/books/*#{}", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	case map[string]any:
-		if baseURL != "" {
-			return nil, errBaseURLWithInit
+	for name, want := range map[string]bool{
+		"protocol": false,
+		"username": false,
+		"port":     true,
+		"pathname": false,
+		"search":   true,
+		"hash":     true,
+		"unknown":  false,
+	} {
+		if got := pattern.IsExactlyEmpty(name); got != want {
+			t.Errorf("%s: want %t; got %t", name, want, got)
 		}
+	}
+}
 
-		return initFromObj(v).New(options)
+func ExampleCompile() {
+	pattern, err := urlpattern.Compile("/books/:id", urlpattern.WithBaseURL("https://example.com"), urlpattern.WithIgnoreCase())
+	if err != nil {
+		panic(err)
 	}
 
-	t.Fatalf("invalid entry pattern %#v", entry.Pattern)
+	fmt.Printf("%t\n", pattern.Test("https://example.com/BOOKS/123", ""))
+	fmt.Printf("%t\n", pattern.Test("https://example.org/books/123", ""))
 
-	return nil, nil
+	// Output: true
+	// false
 }
 
-func newExpectedResult(e Entry) *urlpattern.URLPatternResult {
-	expectedResult := urlpattern.URLPatternResult{}
-	for k, v := range e.ExpectedMatch.(map[string]any) {
-		if k == "inputs" {
-			for _, initInput := range v.([]any) {
-				if ip, ok := initInput.(map[string]any); ok {
-					expectedResult.InitInputs = append(expectedResult.InitInputs, initFromObj(ip))
-				} else {
-					expectedResult.Inputs = append(expectedResult.Inputs, initInput.(string))
-				}
-			}
-
-			continue
-		}
-		mv := v.(map[string]any)
-		component := urlpattern.URLPatternComponentResult{}
-		component.Input = mv["input"].(string)
-		len := len(mv["groups"].(map[string]any))
-
-		if len > 0 {
-			component.Groups = make(map[string]string, len)
-
-			for k, v := range mv["groups"].(map[string]any) {
-				if v == nil {
-					// TODO: this should probably be nil, but it's currently not implemented
-					component.Groups[k] = ""
-					continue
-				}
-
-				component.Groups[k] = v.(string)
-			}
-		}
-
-		switch k {
-		case "protocol":
-			expectedResult.Protocol = component
+func TestCaseFoldedInput(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://example.com/books/:id", urlpattern.WithIgnoreCase())
+	if err != nil {
+		t.Fatal(err)
+	}
 
-		case "username":
-			expectedResult.Username = component
+	r := pattern.Exec("https://EXAMPLE.com/Books/AbC", "")
+	if r == nil || r.Pathname.Input != "/Books/AbC" || r.Pathname.CaseFoldedInput != "/books/abc" || r.Pathname.Groups["id"] != "AbC" {
+		t.Errorf("unexpected result %#v", r)
+	}
+	if r.Hostname.CaseFoldedInput != "" {
+		t.Errorf("want no case-folded hostname; got %q", r.Hostname.CaseFoldedInput)
+	}
 
-		case "password":
-			expectedResult.Password = component
+	pattern, err = urlpattern.Compile("https://example.com/books/:id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := pattern.Exec("https://example.com/books/AbC", ""); r == nil || r.Pathname.CaseFoldedInput != "" {
+		t.Errorf("unexpected result %#v", r)
+	}
+}
 
-		case "hostname":
-			expectedResult.Hostname = component
+func TestCompileInit(t *testing.T) {
+	pathname := "/books/:id"
+	init := &urlpattern.URLPatternInit{Pathname: &pathname}
 
-		case "port":
-			expectedResult.Port = component
+	pattern, err := urlpattern.Compile(init)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pattern.Test("https://example.com/books/1", "") {
+		t.Error("want match")
+	}
 
-		case "pathname":
-			expectedResult.Pathname = component
+	_, err = urlpattern.Compile(init, urlpattern.WithBaseURL("https://example.com"))
+	if !errors.Is(err, urlpattern.ErrBaseURLWithInit) || !errors.Is(err, urlpattern.ErrTypeError) {
+		t.Errorf("want ErrBaseURLWithInit; got %v", err)
+	}
+}
 
-		case "search":
-			expectedResult.Search = component
+func ExampleCompile_prefix() {
+	pattern, err := urlpattern.Compile("https://example.com/books{/*}?")
+	if err != nil {
+		panic(err)
+	}
 
-		case "hash":
-			expectedResult.Hash = component
-		}
+	for _, input := range []string{"https://example.com/books", "https://example.com/books/1/reviews", "https://example.com/bookshelf"} {
+		fmt.Println(input, pattern.Test(input, ""))
 	}
 
-	return &expectedResult
+	// Output:
+	// https://example.com/books true
+	// https://example.com/books/1/reviews true
+	// https://example.com/bookshelf false
 }
 
-func stringOrNil(v any) *string {
-	if v == nil {
-		return nil
+func ExampleURLPatternResult_Values() {
+	pattern, err := urlpattern.New("https://:tenant.example.com/books/:id", "", nil)
+	if err != nil {
+		panic(err)
 	}
 
-	s := v.(string)
+	fmt.Println(pattern.Exec("https://acme.example.com/books/123", "").Values().Encode())
 
-	return &s
+	// Output: hostname.tenant=acme&pathname.id=123
 }
 
-func callTest(pattern *urlpattern.URLPattern, entry Entry) (bool, error) {
-	if len(entry.Inputs) == 0 {
-		return pattern.TestInit(&urlpattern.URLPatternInit{}), nil
+func TestValidateUniqueGroupNames(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://*.example.com/:id/edit?id=:id")
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	if u, ok := entry.Inputs[0].(string); ok {
-		var baseURL string
-		if len(entry.Inputs) > 1 {
-			baseURL = entry.Inputs[1].(string)
-		}
-
-		return pattern.Test(u, baseURL), nil
+	if err := pattern.ValidateUniqueGroupNames(); !errors.Is(err, urlpattern.ErrDuplicateGroupName) {
+		t.Errorf("want ErrDuplicateGroupName; got %v", err)
 	}
 
-	if len(entry.Inputs) > 1 {
-		return false, errInvalidPatternParam
+	pattern, err = urlpattern.Compile("https://*.example.com/:id/edit?page=:page")
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	return pattern.TestInit(initFromObj(entry.Inputs[0].(map[string]any))), nil
-}
-
-func callExec(pattern *urlpattern.URLPattern, entry Entry) (*urlpattern.URLPatternResult, error) {
-	if len(entry.Inputs) == 0 {
-		return pattern.ExecInit(&urlpattern.URLPatternInit{}), nil
+	if err := pattern.ValidateUniqueGroupNames(); err != nil {
+		t.Errorf("unexpected error: %v", err)
 	}
+}
 
-	if u, ok := entry.Inputs[0].(string); ok {
-		var baseURL string
-		if len(entry.Inputs) > 1 {
-			baseURL = entry.Inputs[1].(string)
+func TestConstructorStringProtocol(t *testing.T) {
+	for input, want := range map[string]string{
+		"HTTPS://example.com/books?q": "/books",
+		"git.v2://example.com/books":  "/books",
+		"http{s}?://example.com/a":    "/a",
+	} {
+		pattern, err := urlpattern.New(input, "", nil)
+		if err != nil {
+			t.Fatalf("%s: %v", input, err)
 		}
 
-		return pattern.Exec(u, baseURL), nil
+		if pattern.Pathname() != want {
+			t.Errorf("%s: want pathname %q; got %q", input, want, pattern.Pathname())
+		}
 	}
+}
 
-	if len(entry.Inputs) > 1 {
-		return nil, errInvalidPatternParam
+func TestMultiByteSegments(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://*.例え.jp/文書/:a/:b")
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	return pattern.ExecInit(initFromObj(entry.Inputs[0].(map[string]any))), nil
-}
+	// Non-ASCII code points are percent-encoded and converted to punycode
+	// before matching.
+	r := pattern.Exec("https://ドメイン.例え.jp/文書/中文/😀", "")
+	if r == nil || r.Hostname.Groups["0"] != "xn--eckwd4c7c" || r.Pathname.Groups["a"] != "%E4%B8%AD%E6%96%87" || r.Pathname.Groups["b"] != "%F0%9F%98%80" {
+		t.Errorf("unexpected result %#v", r)
+	}
 
-func initFromObj(m map[string]any) *urlpattern.URLPatternInit {
-	return &urlpattern.URLPatternInit{
-		Protocol: stringOrNil(m["protocol"]),
-		Username: stringOrNil(m["username"]),
-		Password: stringOrNil(m["password"]),
-		Hostname: stringOrNil(m["hostname"]),
-		Port:     stringOrNil(m["port"]),
-		Pathname: stringOrNil(m["pathname"]),
-		Search:   stringOrNil(m["search"]),
-		Hash:     stringOrNil(m["hash"]),
-		BaseURL:  stringOrNil(m["baseURL"]),
+	// Regexps match code points, not bytes.
+	pathname := "/:a/(.)"
+	pattern, err = urlpattern.Compile(&urlpattern.URLPatternInit{Pathname: &pathname})
+	if err != nil {
+		t.Fatal(err)
 	}
-}
 
-var earlierComponents = map[string][]string{
-	"hostname": {"protocol"},
-	"port":     {"protocol", "hostname"},
-	"pathname": {"protocol", "hostname", "port"},
-	"search":   {"protocol", "hostname", "port", "pathname"},
-	"hash":     {"protocol", "hostname", "port", "pathname", "search"},
+	r = pattern.ExecCanonical(urlpattern.CanonicalURL{Protocol: "https", Hostname: "example.com", Pathname: "/中文/😀"})
+	if r == nil || r.Pathname.Groups["a"] != "中文" || r.Pathname.Groups["0"] != "😀" {
+		t.Errorf("unexpected result %#v", r)
+	}
+	if pattern.ExecCanonical(urlpattern.CanonicalURL{Protocol: "https", Hostname: "example.com", Pathname: "/中文/😀😀"}) != nil {
+		t.Error("pattern must not match")
+	}
 }
 
-func buildExpected(entry Entry, component string) *string {
-	if entry.ExpectedObj == nil {
-		if slices.Contains(entry.ExactlyEmptyComponents, component) {
-			es := ""
-			return &es
-		}
-
-		if len(entry.Pattern) > 0 {
-			star := "*"
-
-			p, ok := entry.Pattern[0].(map[string]any)
-			if ok {
-				if p[component] != nil {
-					v := p[component].(string)
-
-					return &v
-				}
-
-				for _, e := range earlierComponents[component] {
-					if _, ok := p[e]; ok {
-						return &star
-					}
-				}
-
-				var baseURL *url.Url
-				if bu, ok := p["baseURL"]; ok {
-					baseURL, _ = url.Parse(bu.(string))
-				} else if len(entry.Pattern) > 1 {
-					if bu, ok := entry.Pattern[1].(string); ok {
-						baseURL, _ = url.Parse(bu)
-					}
-				}
-
-				if baseURL != nil && component != "username" && component != "password" {
-					var baseValue string
-					switch component {
-					case "protocol":
-						baseValue = baseURL.Protocol()
-						baseValue = baseValue[:len(baseValue)-1]
-
-					case "hostname":
-						baseValue = baseURL.Hostname()
-
-					case "port":
-						baseValue = baseURL.Port()
-
-					case "pathname":
-						baseValue = baseURL.Pathname()
-
-					case "search":
-						baseValue = baseURL.Search()[1:]
-
-					case "hash":
-						baseValue = baseURL.Hash()[1:]
-					}
+func TestExecCanonical(t *testing.T) {
+	pattern, err := urlpattern.Compile("https://example.com/books/:id")
+	if err != nil {
+		t.Fatal(err)
+	}
 
-					return &baseValue
-				}
+	input := urlpattern.CanonicalURL{Protocol: "https", Hostname: "example.com", Pathname: "/books/42", Search: "page=2"}
+	if r := pattern.ExecCanonical(input); r == nil || r.Pathname.Groups["id"] != "42" || r.Search.Input != "page=2" || r.Inputs != nil {
+		t.Errorf("unexpected result %#v", r)
+	}
 
-				return &star
-			}
-		}
+	// Unlike ExecInit, the input isn't canonicalized.
+	input.Hostname = "EXAMPLE.com"
+	if pattern.ExecCanonical(input) != nil {
+		t.Error("pattern must not match")
+	}
+}
 
-		return nil
+func TestExecAllocations(t *testing.T) {
+	if raceEnabled || testing.Short() {
+		t.Skip("allocation counts are unreliable with the race detector and skipped in short mode")
 	}
 
-	o := entry.ExpectedObj.(map[string]any)
-	e, ok := o[component]
-	if !ok {
-		return nil
+	pattern, err := urlpattern.Compile("https://example.com/books")
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	expected := e.(string)
+	input := urlpattern.CanonicalURL{Protocol: "https", Hostname: "example.com", Pathname: "/books"}
 
-	return &expected
+	// Only the result itself is allocated: components without groups don't
+	// allocate submatches nor groups maps.
+	if allocs := testing.AllocsPerRun(100, func() { pattern.ExecCanonical(input) }); allocs > 1 {
+		t.Errorf("want at most 1 allocation; got %v", allocs)
+	}
 }
 
-func assertExpectedObject(t *testing.T, entry Entry, pattern *urlpattern.URLPattern) {
-	t.Helper()
-
-	assertExpectedObjectProp(t, "protocol", entry, pattern.Protocol())
-	assertExpectedObjectProp(t, "username", entry, pattern.Username())
-	assertExpectedObjectProp(t, "password", entry, pattern.Password())
-	assertExpectedObjectProp(t, "hostname", entry, pattern.Hostname())
-	assertExpectedObjectProp(t, "port", entry, pattern.Port())
-	assertExpectedObjectProp(t, "pathname", entry, pattern.Pathname())
-	assertExpectedObjectProp(t, "search", entry, pattern.Search())
-	assertExpectedObjectProp(t, "hash", entry, pattern.Hash())
-}
+func ExampleURLPattern_ExecAny() {
+	pattern, err := urlpattern.New("https://*.example.com/:locale/books/:id", "", nil)
+	if err != nil {
+		panic(err)
+	}
 
-func assertExpectedObjectProp(t *testing.T, key string, entry Entry, value string) {
-	t.Helper()
+	r, i := pattern.ExecAny("books/123", []string{"https://example.org/en/", "https://www.example.com/fr/"})
+	fmt.Println(i, r.Pathname.Groups["locale"], r.Pathname.Groups["id"])
 
-	expected := buildExpected(entry, key)
-	if expected == nil {
-		return
-	}
+	_, i = pattern.ExecAny("books/123", []string{"https://example.org/en/"})
+	fmt.Println(i)
 
-	if *expected != value {
-		t.Logf("%s: want %q, got %q (%#v)", key, *expected, value, entry.Pattern)
-		t.FailNow()
-	}
+	// Output: 1 fr 123
+	// -1
 }
 
-func Example() {
-	pattern, err := urlpattern.New("/books/:id", "https://example.com", nil)
+func TestExecPath(t *testing.T) {
+	pattern, err := urlpattern.New(`https://example.com/books/:id\?page=:page`, "", nil)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
-	fmt.Printf("%t\n", pattern.Test("https://example.com/books/123", ""))
-	fmt.Printf("%t\n", pattern.Test("https://example.com/authors/123", ""))
+	r := pattern.ExecPath("/books/42?page=2#top")
+	if r == nil || r.Pathname.Groups["id"] != "42" || r.Search.Groups["page"] != "2" || r.Hash.Input != "top" {
+		t.Fatalf("unexpected result %#v", r)
+	}
+	if r.Hostname.Input != "" || r.Protocol.Input != "" {
+		t.Errorf("only the path, query and fragment must be matched, got %#v", r)
+	}
 
-	fmt.Printf("%v", pattern.Exec("123", "https://example.com/books/").Pathname.Groups)
+	if r := pattern.ExecPath("/books/../books/%7Bid%7D?page=1"); r == nil || r.Pathname.Groups["id"] != "%7Bid%7D" {
+		t.Errorf("the path must be canonicalized, got %#v", r)
+	}
 
-	// Output: true
-	// false
-	// map[id:123]
+	for _, input := range []string{"/authors/42?page=2", "books/42?page=2", "https://example.com/books/42?page=2", "//example.com/books/42?page=2"} {
+		if pattern.ExecPath(input) != nil {
+			t.Errorf("%q must not match", input)
+		}
+	}
 }
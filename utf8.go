@@ -0,0 +1,48 @@
+package urlpattern
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTF8 is returned when a pattern, a URLPatternInit member or a
+// matched URL isn't valid UTF-8. Invalid byte sequences would otherwise be
+// decoded as U+FFFD by the tokenizer and the canonicalizers, silently
+// changing the meaning of patterns.
+var ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+// validateUTF8 returns an error wrapping ErrInvalidUTF8 with the index of the
+// first invalid byte of s, if any.
+func validateUTF8(s string) error {
+	if utf8.ValidString(s) {
+		return nil
+	}
+
+	for i, r := range s {
+		if r != utf8.RuneError {
+			continue
+		}
+
+		if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+			return fmt.Errorf("%w: %q at index %d", ErrInvalidUTF8, s[i], i)
+		}
+	}
+
+	return nil
+}
+
+// validateUTF8 checks that all the members of init are valid UTF-8.
+func (init *URLPatternInit) validateUTF8() error {
+	for _, m := range []*string{init.Protocol, init.Username, init.Password, init.Hostname, init.Port, init.Pathname, init.Search, init.Hash, init.BaseURL} {
+		if m == nil {
+			continue
+		}
+
+		if err := validateUTF8(*m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestInvalidUTF8(t *testing.T) {
+	for _, pattern := range []string{
+		"https://example.com/\xff",
+		"https://example.com/:id(\xc3)",
+		"https://ex\xe2\x82ample.com/*",
+	} {
+		_, err := urlpattern.Compile(pattern)
+		if !errors.Is(err, urlpattern.ErrInvalidUTF8) || !errors.Is(err, urlpattern.ErrTypeError) {
+			t.Errorf("%q: want ErrInvalidUTF8; got %v", pattern, err)
+		}
+	}
+
+	pathname := "/books/\xff"
+	if _, err := urlpattern.Compile(&urlpattern.URLPatternInit{Pathname: &pathname}); !errors.Is(err, urlpattern.ErrInvalidUTF8) {
+		t.Errorf("want ErrInvalidUTF8; got %v", err)
+	}
+
+	pattern, err := urlpattern.Compile("https://example.com/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pattern.Test("https://example.com/\xff", "") {
+		t.Error("want no match")
+	}
+	if pattern.TestInit(&urlpattern.URLPatternInit{Pathname: &pathname}) {
+		t.Error("want no match")
+	}
+	if report := pattern.Explain("https://example.com/\xff", ""); !errors.Is(report.Err, urlpattern.ErrInvalidUTF8) {
+		t.Errorf("want ErrInvalidUTF8; got %v", report.Err)
+	}
+
+	if !pattern.Test("https://example.com/café", "") {
+		t.Error("want match")
+	}
+}
@@ -0,0 +1,18 @@
+package urlpattern
+
+import "net/url"
+
+// Values flattens the groups of all the components into url.Values. Keys are
+// prefixed by the name of the component, e.g. "pathname.id" or
+// "hostname.0", so the result can be fed to form or query based binding code.
+func (r *URLPatternResult) Values() url.Values {
+	values := make(url.Values)
+
+	for _, name := range componentNames {
+		for group, value := range r.component(name).Groups {
+			values.Add(name+"."+group, value)
+		}
+	}
+
+	return values
+}
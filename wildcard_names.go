@@ -0,0 +1,110 @@
+package urlpattern
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrInvalidWildcardName is returned by Compile when the prefix passed to
+// WithWildcardNames isn't a valid group name.
+var ErrInvalidWildcardName = errors.New("invalid wildcard name")
+
+// WithWildcardNames names the groups of the "*" wildcards of the pathname,
+// which are otherwise numbered like the other unnamed groups ("0", "1"...):
+// the first wildcard is named prefix, the following ones prefix followed by
+// their index among the wildcards of the pathname ("rest", "rest1"...).
+//
+//	pattern, _ := urlpattern.Compile("https://example.com/static/*", urlpattern.WithWildcardNames("rest"))
+//	pattern.Exec("https://example.com/static/css/app.css", "").Pathname.Groups["rest"] // "css/app.css"
+//
+// The pattern string of the pathname reflects the names, e.g.
+// "/static/:rest(.*)". Compile returns ErrDuplicatePartName if a name is
+// already used by a named group of the pathname, and ErrInvalidWildcardName
+// if prefix isn't a valid group name.
+func WithWildcardNames(prefix string) Option {
+	return func(c *config) {
+		if !isValidName(prefix) {
+			c.setErr(ErrInvalidWildcardName)
+
+			return
+		}
+
+		c.wildcardNames = prefix
+	}
+}
+
+// isValidName reports whether name can be used as the name of a group in a
+// pattern string.
+func isValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		if !isValidNameCodePoint(r, i == 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compileComponentNamingWildcards returns a compileFunc naming the full
+// wildcards of the compiled component, see WithWildcardNames.
+func compileComponentNamingWildcards(prefix string) compileFunc {
+	return func(name, input string, encodingCallback encodingCallback, options options) (*component, error) {
+		partList, err := parsePatternString(input, options, encodingCallback)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := partList.nameWildcards(prefix); err != nil {
+			return nil, err
+		}
+
+		return compilePartList(name, partList, options)
+	}
+}
+
+// nameWildcards names the unnamed full wildcards of pl, see WithWildcardNames.
+// The other unnamed groups are numbered from 0 again, so that the generated
+// pattern string gives the same names.
+func (pl partList) nameWildcards(prefix string) error {
+	names := make(map[string]struct{}, len(pl))
+	for _, p := range pl {
+		if p.name != "" {
+			names[p.name] = struct{}{}
+		}
+	}
+
+	var wildcards, unnamed int
+	for i := range pl {
+		if !IsAnonymousGroupName(pl[i].name) {
+			continue
+		}
+
+		if pl[i].pType != partFullWildcard {
+			// Renumber the other unnamed groups as when parsing the pattern
+			// string of the component.
+			pl[i].name = AnonymousGroupName(unnamed)
+			unnamed++
+
+			continue
+		}
+
+		name := prefix
+		if wildcards > 0 {
+			name += strconv.Itoa(wildcards)
+		}
+		wildcards++
+
+		if _, ok := names[name]; ok {
+			return ErrDuplicatePartName
+		}
+		names[name] = struct{}{}
+
+		pl[i].name = name
+	}
+
+	return nil
+}
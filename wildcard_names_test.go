@@ -0,0 +1,52 @@
+package urlpattern_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dunglas/go-urlpattern"
+)
+
+func TestWithWildcardNames(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, input string
+		opts           []urlpattern.Option
+		pathname       string
+		groups         map[string]string
+	}{
+		{"https://*.example.com/static/*", "https://www.example.com/static/css/app.css", nil, "/static/:rest(.*)", map[string]string{"rest": "css/app.css"}},
+		{"https://example.com/*/(\\d+)/*", "https://example.com/a/1/b/c", nil, "/:rest(.*)/(\\d+)/:rest1(.*)", map[string]string{"rest": "a", "0": "1", "rest1": "b/c"}},
+		{"https://example.com/*", "https://example.com/a", nil, "/:rest(.*)", map[string]string{"rest": "a"}},
+		{"https://example.com/:id<int>/*", "https://example.com/42/a", []urlpattern.Option{urlpattern.WithConstraints()}, "/:id(\\d+)/:rest(.*)", map[string]string{"id": "42", "rest": "a"}},
+	} {
+		t.Run(tc.pattern, func(t *testing.T) {
+			pattern, err := urlpattern.Compile(tc.pattern, append(tc.opts, urlpattern.WithWildcardNames("rest"))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if pattern.Pathname() != tc.pathname {
+				t.Errorf("want pathname %q; got %q", tc.pathname, pattern.Pathname())
+			}
+
+			r := pattern.Exec(tc.input, "")
+			if r == nil {
+				t.Fatal("expected a match")
+			}
+			if !reflect.DeepEqual(tc.groups, r.Pathname.Groups) {
+				t.Errorf("want %#v; got %#v", tc.groups, r.Pathname.Groups)
+			}
+			if r.Hostname.Groups["rest"] != "" {
+				t.Error("only the pathname wildcards must be named")
+			}
+		})
+	}
+
+	if _, err := urlpattern.Compile("https://example.com/:rest/*", urlpattern.WithWildcardNames("rest")); !errors.Is(err, urlpattern.ErrDuplicatePartName) {
+		t.Errorf("want ErrDuplicatePartName; got %v", err)
+	}
+	if _, err := urlpattern.Compile("https://example.com/*", urlpattern.WithWildcardNames("1st")); !errors.Is(err, urlpattern.ErrInvalidWildcardName) {
+		t.Errorf("want ErrInvalidWildcardName; got %v", err)
+	}
+}